@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BlockUser - Kullanıcıyı engeller ve iki yönlü takip ilişkilerini kaldırır
+func BlockUser(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, Response{Success: false, Message: "Oturum bilgisi bulunamadı"})
+		return
+	}
+
+	var target models.User
+	if err := database.DB.Where("username = ?", c.Param("username")).First(&target).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, Response{Success: false, Message: "Kullanıcı bulunamadı"})
+		} else {
+			c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Kullanıcı aranırken veritabanı hatası: " + err.Error()})
+		}
+		return
+	}
+
+	if target.ID == userID.(uint) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Kendinizi engelleyemezsiniz"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		block := models.Block{BlockerID: userID.(uint), BlockedID: target.ID, CreatedAt: time.Now()}
+		if err := tx.Where("blocker_id = ? AND blocked_id = ?", block.BlockerID, block.BlockedID).
+			FirstOrCreate(&block).Error; err != nil {
+			return err
+		}
+
+		// Engellenen kullanıcıyla olan takip ilişkileri ve bekleyen istekler kaldırılır
+		if err := tx.Where("(follower_id = ? AND following_id = ?) OR (follower_id = ? AND following_id = ?)",
+			userID, target.ID, target.ID, userID).Delete(&models.Follow{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("(follower_id = ? AND following_id = ?) OR (follower_id = ? AND following_id = ?)",
+			userID, target.ID, target.ID, userID).Delete(&models.FollowRequest{}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Kullanıcı engellenirken hata oluştu: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Kullanıcı engellendi"})
+}
+
+// UnblockUser - Kullanıcının engelini kaldırır
+func UnblockUser(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, Response{Success: false, Message: "Oturum bilgisi bulunamadı"})
+		return
+	}
+
+	var target models.User
+	if err := database.DB.Where("username = ?", c.Param("username")).First(&target).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Kullanıcı bulunamadı"})
+		return
+	}
+
+	if err := database.DB.Where("blocker_id = ? AND blocked_id = ?", userID, target.ID).
+		Delete(&models.Block{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Engel kaldırılırken hata oluştu: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Kullanıcının engeli kaldırıldı"})
+}
+
+// GetBlockedUsers - Oturumdaki kullanıcının engellediği kullanıcıları listeler
+func GetBlockedUsers(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, Response{Success: false, Message: "Oturum bilgisi bulunamadı"})
+		return
+	}
+
+	var blocks []models.Block
+	if err := database.DB.Preload("Blocked").Where("blocker_id = ?", userID).
+		Order("created_at desc").Find(&blocks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Engellenen kullanıcılar alınamadı"})
+		return
+	}
+
+	users := []gin.H{}
+	for _, block := range blocks {
+		users = append(users, gin.H{
+			"id":           block.Blocked.ID,
+			"username":     block.Blocked.Username,
+			"fullName":     block.Blocked.FullName,
+			"profileImage": block.Blocked.ProfileImage,
+			"blockedAt":    block.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Engellenen kullanıcılar getirildi", Data: users})
+}
+
+// isBlockedBetween iki kullanıcıdan birinin diğerini engelleyip engellemediğini kontrol eder
+func isBlockedBetween(userA, userB uint) bool {
+	if userA == 0 || userB == 0 || userA == userB {
+		return false
+	}
+	var count int64
+	database.DB.Model(&models.Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userA, userB, userB, userA).
+		Count(&count)
+	return count > 0
+}
+
+// blockedUserIDsSQL oturumdaki kullanıcıyla arasında engel bulunan kullanıcı ID'lerini veren alt sorgu.
+// İki kez userID parametresi bekler.
+const blockedUserIDsSQL = `SELECT blocked_id FROM blocks WHERE blocker_id = ? UNION SELECT blocker_id FROM blocks WHERE blocked_id = ?`
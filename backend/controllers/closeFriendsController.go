@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findFollowerByUsername :username parametresindeki kullanıcının oturumdaki kullanıcıyı takip eden
+// kaydını getirir; yakın arkadaş listesine sadece takipçiler eklenebilir
+func findFollowerByUsername(c *gin.Context, userID uint) (models.Follow, bool) {
+	var follow models.Follow
+
+	var target models.User
+	if err := database.DB.Where("username = ?", c.Param("username")).First(&target).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, Response{Success: false, Message: "Kullanıcı bulunamadı"})
+		} else {
+			c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Kullanıcı aranırken veritabanı hatası: " + err.Error()})
+		}
+		return follow, false
+	}
+
+	if target.ID == userID {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Kendinizi yakın arkadaş listesine ekleyemezsiniz"})
+		return follow, false
+	}
+
+	if err := database.DB.Where("follower_id = ? AND following_id = ?", target.ID, userID).First(&follow).Error; err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Bu kullanıcı sizi takip etmiyor"})
+		return follow, false
+	}
+	return follow, true
+}
+
+// AddCloseFriend - Bir takipçiyi yakın arkadaş listesine ekler
+func AddCloseFriend(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	follow, ok := findFollowerByUsername(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Model(&follow).Update("is_close_friend", true).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yakın arkadaş eklenirken hata oluştu: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Kullanıcı yakın arkadaş listesine eklendi"})
+}
+
+// RemoveCloseFriend - Bir takipçiyi yakın arkadaş listesinden çıkarır
+func RemoveCloseFriend(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	follow, ok := findFollowerByUsername(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Model(&follow).Update("is_close_friend", false).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yakın arkadaş kaldırılırken hata oluştu: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Kullanıcı yakın arkadaş listesinden çıkarıldı"})
+}
+
+// GetCloseFriends - Oturumdaki kullanıcının yakın arkadaş listesini getirir
+func GetCloseFriends(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var follows []models.Follow
+	if err := database.DB.Preload("Follower").
+		Where("following_id = ? AND is_close_friend = ?", userID, true).
+		Order("updated_at desc").Find(&follows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yakın arkadaşlar alınamadı"})
+		return
+	}
+
+	users := []gin.H{}
+	for _, follow := range follows {
+		users = append(users, gin.H{
+			"id":           follow.Follower.ID,
+			"username":     follow.Follower.Username,
+			"fullName":     follow.Follower.FullName,
+			"profileImage": follow.Follower.ProfileImage,
+			"addedAt":      follow.UpdatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Yakın arkadaşlar getirildi", Data: users})
+}
@@ -0,0 +1,526 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// collectionItemRequest koleksiyona eklenecek, taşınacak veya kapak yapılacak içerik
+type collectionItemRequest struct {
+	ItemType string `json:"itemType" binding:"required"` // post veya reel
+	ItemID   uint   `json:"itemId" binding:"required"`
+}
+
+// respondCollectionError koleksiyon işlemlerindeki hatayı uygun durum koduyla döndürür
+func respondCollectionError(c *gin.Context, err error) {
+	if errors.Is(err, errCollectionNotFound) {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Koleksiyon işlemi başarısız: " + err.Error()})
+}
+
+// validateCollectionName koleksiyon adını temizler ve doğrular
+func validateCollectionName(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > maxCollectionNameLength {
+		return "", false
+	}
+	return name, true
+}
+
+// bindCollectionItem içerik isteğini doğrular ve içeriğin kullanıcı tarafından görülebildiğini kontrol eder
+func bindCollectionItem(c *gin.Context, userID uint) (collectionItemRequest, bool) {
+	var request collectionItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+		return request, false
+	}
+	if !isValidSavedItemType(request.ItemType) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz içerik türü: post veya reel olmalı"})
+		return request, false
+	}
+
+	var visible int64
+	visibleCollectionItems(userID).Where("item_type = ? AND item_id = ?", request.ItemType, request.ItemID).Count(&visible)
+	if visible == 0 {
+		// Henüz kaydedilmemiş içerik için doğrudan görünürlük kontrolü yapılır
+		found := false
+		if request.ItemType == models.SavedItemPost {
+			var post models.Post
+			found = database.DB.First(&post, request.ItemID).Error == nil && CanViewPost(userID, post) &&
+				isPublished(post.PublishStatus)
+		} else {
+			var reel models.Reels
+			found = database.DB.First(&reel, request.ItemID).Error == nil && canViewReel(userID, reel) &&
+				isPublished(reel.PublishStatus) && reel.Status == models.ReelStatusReady
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, Response{Success: false, Message: "İçerik bulunamadı"})
+			return request, false
+		}
+	}
+	return request, true
+}
+
+// saveContent içeriği kaydeder (zaten kayıtlıysa dokunmaz) ve varsayılan ile verilen koleksiyonlara ekler
+func saveContent(tx *gorm.DB, userID uint, itemType string, itemID uint, collectionIDs ...uint) error {
+	if itemType == models.SavedItemPost {
+		var count int64
+		tx.Model(&models.SavedPost{}).Where("user_id = ? AND post_id = ?", userID, itemID).Count(&count)
+		if count == 0 {
+			if err := tx.Create(&models.SavedPost{UserID: userID, PostID: itemID, CreatedAt: time.Now()}).Error; err != nil {
+				return err
+			}
+		}
+	} else {
+		var count int64
+		tx.Model(&models.SavedReel{}).Where("user_id = ? AND reel_id = ?", userID, itemID).Count(&count)
+		if count == 0 {
+			if err := tx.Create(&models.SavedReel{UserID: userID, ReelID: itemID, CreatedAt: time.Now()}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return addToCollections(tx, userID, itemType, itemID, collectionIDs...)
+}
+
+// GetCollections - Kullanıcının kayıt koleksiyonlarını (varsayılan koleksiyon ilk sırada) getirir
+func GetCollections(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	if _, err := ensureDefaultCollection(database.DB, userID); err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	var collections []models.SavedCollection
+	if err := database.DB.Where("user_id = ?", userID).
+		Order("is_default DESC, position, id").Find(&collections).Error; err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	results := []gin.H{}
+	for _, collection := range collections {
+		results = append(results, collectionResponse(userID, collection))
+	}
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Koleksiyonlar getirildi", Data: gin.H{"collections": results}})
+}
+
+// CreateCollection - Yeni bir kayıt koleksiyonu oluşturur; istenirse içerikleri de ekler
+func CreateCollection(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		Name  string                  `json:"name" binding:"required"`
+		Items []collectionItemRequest `json:"items"` // Koleksiyona eklenecek kaydedilmiş içerikler
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz koleksiyon verisi: " + err.Error()})
+		return
+	}
+	name, ok := validateCollectionName(request.Name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Koleksiyon adı 1-" + strconv.Itoa(maxCollectionNameLength) + " karakter olmalı",
+		})
+		return
+	}
+
+	var count int64
+	database.DB.Model(&models.SavedCollection{}).Where("user_id = ? AND is_default = ?", userID, false).Count(&count)
+	if count >= maxCollectionsPerUser {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "En fazla " + strconv.Itoa(maxCollectionsPerUser) + " koleksiyon oluşturabilirsiniz",
+		})
+		return
+	}
+
+	var collection models.SavedCollection
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := ensureDefaultCollection(tx, userID); err != nil {
+			return err
+		}
+
+		var maxPosition int
+		tx.Model(&models.SavedCollection{}).Where("user_id = ?", userID).Select("COALESCE(MAX(position), 0)").Scan(&maxPosition)
+		collection = models.SavedCollection{UserID: userID, Name: name, Position: maxPosition + 1}
+		if err := tx.Create(&collection).Error; err != nil {
+			return err
+		}
+
+		// Sadece kullanıcının kaydettiği içerikler eklenebilir
+		for _, item := range request.Items {
+			var saved int64
+			tx.Model(&models.SavedCollectionItem{}).
+				Where("user_id = ? AND item_type = ? AND item_id = ?", userID, item.ItemType, item.ItemID).Count(&saved)
+			if saved == 0 {
+				continue
+			}
+			if err := addToCollections(tx, userID, item.ItemType, item.ItemID, collection.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Success: true,
+		Message: "Koleksiyon oluşturuldu",
+		Data:    gin.H{"collection": collectionResponse(userID, collection)},
+	})
+}
+
+// RenameCollection - Koleksiyonun adını değiştirir (varsayılan koleksiyon yeniden adlandırılamaz)
+func RenameCollection(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	collection, err := findUserCollection(database.DB, userID, c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	if collection.IsDefault {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Varsayılan koleksiyon yeniden adlandırılamaz"})
+		return
+	}
+
+	var request struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+		return
+	}
+	name, ok := validateCollectionName(request.Name)
+	if !ok {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Koleksiyon adı 1-" + strconv.Itoa(maxCollectionNameLength) + " karakter olmalı",
+		})
+		return
+	}
+
+	if err := database.DB.Model(&collection).Update("name", name).Error; err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Koleksiyon yeniden adlandırıldı",
+		Data:    gin.H{"collection": collectionResponse(userID, collection)},
+	})
+}
+
+// DeleteCollection - Koleksiyonu siler. İçerikler kayıtlı kalır ve varsayılan koleksiyonda görünmeye devam eder.
+func DeleteCollection(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	collection, err := findUserCollection(database.DB, userID, c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	if collection.IsDefault {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Varsayılan koleksiyon silinemez"})
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", collection.ID).Delete(&models.SavedCollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&collection).Error
+	})
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Koleksiyon silindi"})
+}
+
+// ReorderCollections - Koleksiyonların sırasını değiştirir. Varsayılan koleksiyon dışındaki tüm koleksiyonlar
+// istenen sırayla verilmelidir; varsayılan koleksiyon her zaman ilk sıradadır.
+func ReorderCollections(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		CollectionIDs []uint `json:"collectionIds" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+		return
+	}
+
+	var ownedIDs []uint
+	database.DB.Model(&models.SavedCollection{}).Where("user_id = ? AND is_default = ?", userID, false).Pluck("id", &ownedIDs)
+	owned := make(map[uint]bool, len(ownedIDs))
+	for _, id := range ownedIDs {
+		owned[id] = true
+	}
+	ids := uniqueUints(request.CollectionIDs)
+	if len(ids) != len(request.CollectionIDs) || len(ids) != len(ownedIDs) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Tüm koleksiyonlarınız birer kez verilmelidir"})
+		return
+	}
+	for _, id := range ids {
+		if !owned[id] {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Tüm koleksiyonlarınız birer kez verilmelidir"})
+			return
+		}
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&models.SavedCollection{}).Where("id = ?", id).Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Koleksiyonlar yeniden sıralandı", Data: gin.H{"collectionIds": ids}})
+}
+
+// GetCollectionItems - Koleksiyondaki gönderi ve reelleri en son eklenenden başlayarak sayfalı getirir (?type=post|reel)
+func GetCollectionItems(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	collection, err := findUserCollection(database.DB, userID, c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultCollectionItemLimit)))
+	if limit < 1 || limit > maxCollectionItemLimit {
+		limit = defaultCollectionItemLimit
+	}
+
+	query := visibleCollectionItems(userID).Where("saved_collection_items.collection_id = ?", collection.ID)
+	switch itemType := c.Query("type"); itemType {
+	case "":
+	case models.SavedItemPost, models.SavedItemReel:
+		query = query.Where("saved_collection_items.item_type = ?", itemType)
+	default:
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz içerik türü: post veya reel olmalı"})
+		return
+	}
+
+	var items []models.SavedCollectionItem
+	if err := query.Order("saved_collection_items.created_at DESC, saved_collection_items.id DESC").
+		Limit(limit + 1).Offset((page - 1) * limit).Find(&items).Error; err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Koleksiyon içerikleri getirildi",
+		Data: gin.H{
+			"collection": collectionResponse(userID, collection),
+			"items":      hydrateCollectionItems(userID, items),
+			"page":       page,
+			"limit":      limit,
+			"hasMore":    hasMore,
+		},
+	})
+}
+
+// AddCollectionItem - İçeriği koleksiyona ekler; içerik kaydedilmemişse önce kaydedilir
+func AddCollectionItem(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	collection, err := findUserCollection(database.DB, userID, c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	item, ok := bindCollectionItem(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return saveContent(tx, userID, item.ItemType, item.ItemID, collection.ID)
+	}); err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, Response{Success: true, Message: "İçerik koleksiyona eklendi", Data: gin.H{"saved": true}})
+}
+
+// RemoveCollectionItem - İçeriği koleksiyondan çıkarır. Varsayılan koleksiyondan çıkarmak kaydı tamamen kaldırır.
+func RemoveCollectionItem(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	collection, err := findUserCollection(database.DB, userID, c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	itemType := c.Param("type")
+	itemID, err := strconv.ParseUint(c.Param("itemId"), 10, 32)
+	if err != nil || !isValidSavedItemType(itemType) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz içerik"})
+		return
+	}
+
+	var removed bool
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if collection.IsDefault {
+			var err error
+			removed, err = unsaveItem(tx, userID, itemType, uint(itemID))
+			return err
+		}
+		result := tx.Where("collection_id = ? AND item_type = ? AND item_id = ?", collection.ID, itemType, itemID).
+			Delete(&models.SavedCollectionItem{})
+		removed = result.RowsAffected > 0
+		return result.Error
+	})
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "İçerik bu koleksiyonda değil"})
+		return
+	}
+
+	message := "İçerik koleksiyondan çıkarıldı"
+	if collection.IsDefault {
+		message = "İçerik kayıtlardan kaldırıldı"
+	}
+	c.JSON(http.StatusOK, Response{Success: true, Message: message, Data: gin.H{"saved": !collection.IsDefault}})
+}
+
+// MoveCollectionItem - İçeriği bir koleksiyondan diğerine taşır. Varsayılan koleksiyon tüm kayıtları içerdiğinden
+// oradan taşınan içerik varsayılan koleksiyonda da kalır.
+func MoveCollectionItem(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	source, err := findUserCollection(database.DB, userID, c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	var request struct {
+		collectionItemRequest
+		TargetCollectionID uint `json:"targetCollectionId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+		return
+	}
+	if request.TargetCollectionID == source.ID {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Hedef koleksiyon kaynak koleksiyonla aynı olamaz"})
+		return
+	}
+	target, err := findUserCollection(database.DB, userID, request.TargetCollectionID)
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	var count int64
+	database.DB.Model(&models.SavedCollectionItem{}).Where("collection_id = ? AND item_type = ? AND item_id = ?",
+		source.ID, request.ItemType, request.ItemID).Count(&count)
+	if count == 0 {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "İçerik bu koleksiyonda değil"})
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := addToCollections(tx, userID, request.ItemType, request.ItemID, target.ID); err != nil {
+			return err
+		}
+		if source.IsDefault {
+			return nil
+		}
+		return tx.Where("collection_id = ? AND item_type = ? AND item_id = ?", source.ID, request.ItemType, request.ItemID).
+			Delete(&models.SavedCollectionItem{}).Error
+	})
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "İçerik taşındı",
+		Data:    gin.H{"fromCollectionId": source.ID, "toCollectionId": target.ID},
+	})
+}
+
+// SetCollectionCover - Koleksiyonun kapak içeriğini belirler. Gövde boş gönderilirse kapak
+// otomatik seçime (en son eklenen içerik) döner.
+func SetCollectionCover(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	collection, err := findUserCollection(database.DB, userID, c.Param("id"))
+	if err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+
+	var request struct {
+		ItemType string `json:"itemType"`
+		ItemID   uint   `json:"itemId"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+			return
+		}
+	}
+
+	if request.ItemType != "" {
+		var count int64
+		visibleCollectionItems(userID).Where("saved_collection_items.collection_id = ? AND item_type = ? AND item_id = ?",
+			collection.ID, request.ItemType, request.ItemID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Kapak sadece koleksiyondaki içeriklerden seçilebilir"})
+			return
+		}
+	}
+
+	if err := database.DB.Model(&collection).Updates(map[string]interface{}{
+		"cover_item_type": request.ItemType,
+		"cover_item_id":   request.ItemID,
+	}).Error; err != nil {
+		respondCollectionError(c, err)
+		return
+	}
+	collection.CoverItemType, collection.CoverItemID = request.ItemType, request.ItemID
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Koleksiyon kapağı güncellendi",
+		Data:    gin.H{"collection": collectionResponse(userID, collection)},
+	})
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDefaultCollectionCreatedOnceUnderConcurrency(t *testing.T) {
+	setupTestDatabase(t)
+	gin.SetMode(gin.TestMode)
+	user := createTestUser(t, "koleksiyoncu")
+
+	// Koleksiyonlardan önce kaydedilmiş gönderi varsayılan koleksiyona bir kez aktarılmalı
+	post := models.Post{UserID: user.ID, Content: "kayıtlı", PublishStatus: models.PublishStatusPublished}
+	database.DB.Create(&post)
+	database.DB.Create(&models.SavedPost{UserID: user.ID, PostID: post.ID, CreatedAt: time.Now()})
+
+	router := gin.New()
+	auth := func(c *gin.Context) { c.Set("userID", user.ID) }
+	router.GET("/collections", auth, GetCollections)
+	router.POST("/collections", auth, CreateCollection)
+
+	// Kullanıcının ilk istekleri aynı anda gelir; hepsi varsayılan koleksiyonu oluşturmaya çalışır
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	statuses := make(map[int]int)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			request := httptest.NewRequest(http.MethodGet, "/collections", nil)
+			if i%2 == 1 {
+				request = httptest.NewRequest(http.MethodPost, "/collections", strings.NewReader(fmt.Sprintf(`{"name":"Liste %d"}`, i)))
+				request.Header.Set("Content-Type", "application/json")
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			mu.Lock()
+			statuses[recorder.Code]++
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	if statuses[http.StatusOK]+statuses[http.StatusCreated] != 8 {
+		t.Errorf("yanıt durumları %v, tüm istekler başarılı olmalı", statuses)
+	}
+	var defaults []models.SavedCollection
+	database.DB.Where("user_id = ? AND is_default = ?", user.ID, true).Find(&defaults)
+	if len(defaults) != 1 {
+		t.Fatalf("%d varsayılan koleksiyon oluştu, beklenen 1", len(defaults))
+	}
+	var items int64
+	database.DB.Model(&models.SavedCollectionItem{}).Where("collection_id = ?", defaults[0].ID).Count(&items)
+	if items != 1 {
+		t.Errorf("varsayılan koleksiyonda %d içerik var, eski kayıt bir kez aktarılmalı", items)
+	}
+
+	// Veritabanı da ikinci bir varsayılan koleksiyonu reddeder
+	duplicate := models.SavedCollection{UserID: user.ID, Name: models.DefaultCollectionName, IsDefault: true}
+	if err := database.DB.Create(&duplicate).Error; err == nil {
+		t.Error("ikinci varsayılan koleksiyon eklenebildi")
+	}
+}
@@ -0,0 +1,329 @@
+package controllers
+
+import (
+	"errors"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Koleksiyon sınırları
+const (
+	maxCollectionNameLength    = 100
+	maxCollectionsPerUser      = 100
+	defaultCollectionItemLimit = 24
+	maxCollectionItemLimit     = 100
+)
+
+// errCollectionNotFound kullanıcıya ait olmayan veya var olmayan koleksiyon
+var errCollectionNotFound = errors.New("Koleksiyon bulunamadı")
+
+// isValidSavedItemType kaydedilebilir içerik türünü doğrular
+func isValidSavedItemType(itemType string) bool {
+	return itemType == models.SavedItemPost || itemType == models.SavedItemReel
+}
+
+// ensureDefaultCollection kullanıcının varsayılan koleksiyonunu döndürür, yoksa oluşturur. Koleksiyonlardan
+// önce yapılmış kayıtlar varsayılan koleksiyon ilk oluşturulduğunda ona aktarılır. Kullanıcı başına tek
+// varsayılan koleksiyona izin veren benzersiz indeks sayesinde eşzamanlı isteklerden sadece biri oluşturur;
+// diğerleri onun oluşturduğu koleksiyonu kullanır. Ekleme okumadan önce yapılır; böylece işlem içinde
+// çağrıldığında yazma kilidi baştan alınır ve eşzamanlı işlemler eski bir anlık görüntüden yazmaya çalışmaz.
+func ensureDefaultCollection(tx *gorm.DB, userID uint) (models.SavedCollection, error) {
+	collection := models.SavedCollection{UserID: userID, Name: models.DefaultCollectionName, IsDefault: true}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&collection)
+	if result.Error != nil {
+		return collection, result.Error
+	}
+	if result.RowsAffected == 0 {
+		err := tx.Where("user_id = ? AND is_default = ?", userID, true).First(&collection).Error
+		return collection, err
+	}
+	if err := tx.Exec(`INSERT OR IGNORE INTO saved_collection_items (collection_id, user_id, item_type, item_id, created_at)
+		SELECT ?, user_id, ?, post_id, COALESCE(created_at, CURRENT_TIMESTAMP) FROM saved_posts WHERE user_id = ?`,
+		collection.ID, models.SavedItemPost, userID).Error; err != nil {
+		return collection, err
+	}
+	if err := tx.Exec(`INSERT OR IGNORE INTO saved_collection_items (collection_id, user_id, item_type, item_id, created_at)
+		SELECT ?, user_id, ?, reel_id, COALESCE(created_at, CURRENT_TIMESTAMP) FROM saved_reels WHERE user_id = ?`,
+		collection.ID, models.SavedItemReel, userID).Error; err != nil {
+		return collection, err
+	}
+	return collection, nil
+}
+
+// findUserCollection kullanıcının koleksiyonunu getirir
+func findUserCollection(tx *gorm.DB, userID uint, collectionID interface{}) (models.SavedCollection, error) {
+	var collection models.SavedCollection
+	if err := tx.Where("id = ? AND user_id = ?", collectionID, userID).First(&collection).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return collection, errCollectionNotFound
+		}
+		return collection, err
+	}
+	return collection, nil
+}
+
+// addToCollections kaydedilmiş içeriği varsayılan koleksiyona ve verilen koleksiyonlara ekler
+// (zaten ekliyse değişiklik yapmaz)
+func addToCollections(tx *gorm.DB, userID uint, itemType string, itemID uint, collectionIDs ...uint) error {
+	defaultCollection, err := ensureDefaultCollection(tx, userID)
+	if err != nil {
+		return err
+	}
+
+	targets := []uint{defaultCollection.ID}
+	if len(collectionIDs) > 0 {
+		var owned []uint
+		if err := tx.Model(&models.SavedCollection{}).Where("id IN ? AND user_id = ?", collectionIDs, userID).
+			Pluck("id", &owned).Error; err != nil {
+			return err
+		}
+		if len(owned) != len(uniqueUints(collectionIDs)) {
+			return errCollectionNotFound
+		}
+		targets = append(targets, owned...)
+	}
+
+	now := time.Now()
+	for _, collectionID := range targets {
+		item := models.SavedCollectionItem{
+			CollectionID: collectionID,
+			UserID:       userID,
+			ItemType:     itemType,
+			ItemID:       itemID,
+			CreatedAt:    now,
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&item).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeFromCollections kaydı kaldırılan içeriği kullanıcının tüm koleksiyonlarından çıkarır
+func removeFromCollections(tx *gorm.DB, userID uint, itemType string, itemID uint) error {
+	return tx.Where("user_id = ? AND item_type = ? AND item_id = ?", userID, itemType, itemID).
+		Delete(&models.SavedCollectionItem{}).Error
+}
+
+// deleteCollectionItems silinen içeriği tüm kullanıcıların koleksiyonlarından çıkarır
+func deleteCollectionItems(tx *gorm.DB, itemType string, itemID uint) error {
+	return tx.Where("item_type = ? AND item_id = ?", itemType, itemID).Delete(&models.SavedCollectionItem{}).Error
+}
+
+// unsaveItem içeriğin kaydını ve tüm koleksiyonlardaki kopyalarını kaldırır; kayıt yoksa false döner
+func unsaveItem(tx *gorm.DB, userID uint, itemType string, itemID uint) (bool, error) {
+	var result *gorm.DB
+	if itemType == models.SavedItemPost {
+		result = tx.Where("user_id = ? AND post_id = ?", userID, itemID).Delete(&models.SavedPost{})
+	} else {
+		result = tx.Where("user_id = ? AND reel_id = ?", userID, itemID).Delete(&models.SavedReel{})
+	}
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if err := removeFromCollections(tx, userID, itemType, itemID); err != nil {
+		return false, err
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// bindCollectionIDs kaydetme isteğindeki opsiyonel koleksiyon listesini okur (gövde boş olabilir)
+func bindCollectionIDs(c *gin.Context) ([]uint, error) {
+	var request struct {
+		CollectionIDs []uint `json:"collectionIds"`
+	}
+	if c.Request.ContentLength == 0 {
+		return nil, nil
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		return nil, err
+	}
+	return request.CollectionIDs, nil
+}
+
+// visibleCollectionItems kullanıcının hâlâ görebildiği içeriklere ait koleksiyon öğelerini seçen sorguyu döndürür.
+// Silinen, yayından kaldırılan veya yakın arkadaş listesinden çıkarılan içerikler koleksiyonlarda görünmez.
+func visibleCollectionItems(userID uint) *gorm.DB {
+	postSQL, postArgs := contentVisibilityClause("posts", userID)
+	reelSQL, reelArgs := contentVisibilityClause("reels", userID)
+
+	args := []interface{}{userID, models.SavedItemPost}
+	args = append(args, postArgs...)
+	args = append(args, models.SavedItemReel, models.ReelStatusReady)
+	args = append(args, reelArgs...)
+
+	return database.DB.Model(&models.SavedCollectionItem{}).Where(`saved_collection_items.user_id = ? AND (
+		(saved_collection_items.item_type = ? AND saved_collection_items.item_id IN
+			(SELECT posts.id FROM posts WHERE posts.deleted_at IS NULL AND `+postSQL+`))
+		OR (saved_collection_items.item_type = ? AND saved_collection_items.item_id IN
+			(SELECT reels.id FROM reels WHERE reels.deleted_at IS NULL AND reels.status = ? AND `+reelSQL+`)))`, args...)
+}
+
+// collectionItemCover içeriğin kapak görselini döndürür (gönderinin ilk görseli, reelin kapak görseli)
+func collectionItemCover(itemType string, itemID uint) string {
+	var urls []string
+	if itemType == models.SavedItemPost {
+		database.DB.Model(&models.PostImage{}).Where("post_id = ?", itemID).Order("id").Limit(1).Pluck("url", &urls)
+	} else {
+		database.DB.Model(&models.Reels{}).Where("id = ?", itemID).Pluck("thumbnail_url", &urls)
+	}
+	if len(urls) == 0 {
+		return ""
+	}
+	return urls[0]
+}
+
+// collectionResponse koleksiyonu öğe sayısı ve kapak görseliyle birlikte istemci formatına çevirir.
+// Seçilen kapak içeriği koleksiyondan çıkarılmışsa veya artık görünmüyorsa en son eklenen içerik kullanılır.
+func collectionResponse(userID uint, collection models.SavedCollection) gin.H {
+	var itemCount int64
+	visibleCollectionItems(userID).Where("saved_collection_items.collection_id = ?", collection.ID).Count(&itemCount)
+
+	var cover models.SavedCollectionItem
+	found := false
+	if collection.CoverItemType != "" {
+		found = visibleCollectionItems(userID).Where("saved_collection_items.collection_id = ? AND item_type = ? AND item_id = ?",
+			collection.ID, collection.CoverItemType, collection.CoverItemID).First(&cover).Error == nil
+	}
+	if !found {
+		found = visibleCollectionItems(userID).Where("saved_collection_items.collection_id = ?", collection.ID).
+			Order("saved_collection_items.created_at DESC, saved_collection_items.id DESC").First(&cover).Error == nil
+	}
+
+	coverURL := ""
+	if found {
+		coverURL = collectionItemCover(cover.ItemType, cover.ItemID)
+	}
+
+	return gin.H{
+		"id":            collection.ID,
+		"name":          collection.Name,
+		"isDefault":     collection.IsDefault,
+		"position":      collection.Position,
+		"itemCount":     itemCount,
+		"coverUrl":      coverURL,
+		"coverItemType": collection.CoverItemType,
+		"coverItemId":   collection.CoverItemID,
+		"createdAt":     collection.CreatedAt,
+		"updatedAt":     collection.UpdatedAt,
+	}
+}
+
+// hydrateCollectionItems koleksiyon öğelerini gönderi ve reel bilgileriyle doldurur (sıra korunur)
+func hydrateCollectionItems(userID uint, items []models.SavedCollectionItem) []gin.H {
+	var postIDs, reelIDs []uint
+	for _, item := range items {
+		if item.ItemType == models.SavedItemPost {
+			postIDs = append(postIDs, item.ItemID)
+		} else {
+			reelIDs = append(reelIDs, item.ItemID)
+		}
+	}
+
+	posts := make(map[uint]gin.H)
+	if len(postIDs) > 0 {
+		var rows []models.Post
+		database.DB.Preload("User").Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		}).Where("id IN ?", postIDs).Find(&rows)
+
+		var likedIDs []uint
+		database.DB.Model(&models.Like{}).Where("user_id = ? AND post_id IN ?", userID, postIDs).Pluck("post_id", &likedIDs)
+		liked := make(map[uint]bool)
+		for _, id := range likedIDs {
+			liked[id] = true
+		}
+
+		mentionIDs := mentionUserIDs(postTexts(rows)...)
+		for _, post := range rows {
+			posts[post.ID] = gin.H{
+				"id":              post.ID,
+				"content":         post.Content,
+				"caption":         post.Caption,
+				"contentEntities": textEntities(post.Content, mentionIDs),
+				"captionEntities": textEntities(post.Caption, mentionIDs),
+				"tags":            splitTagsString(post.TagsString),
+				"likes":           post.LikeCount,
+				"comments":        post.CommentCount,
+				"shares":          post.ShareCount,
+				"repostOf":        repostReference(userID, post),
+				"poll":            postPoll(userID, post),
+				"createdAt":       formatTimeAgo(post.CreatedAt),
+				"liked":           liked[post.ID],
+				"saved":           true,
+				"images":          postImageURLs(post),
+				"audience":        post.Audience,
+				"edited":          post.EditedAt != nil,
+				"editedAt":        post.EditedAt,
+				"user": gin.H{
+					"id":           post.User.ID,
+					"username":     post.User.Username,
+					"profileImage": post.User.ProfileImage,
+				},
+			}
+		}
+	}
+
+	reels := make(map[uint]gin.H)
+	if len(reelIDs) > 0 {
+		var rows []models.Reels
+		database.DB.Preload("User").Where("id IN ?", reelIDs).Find(&rows)
+		for _, reel := range rows {
+			reels[reel.ID] = gin.H{
+				"id":           reel.ID,
+				"caption":      reel.Caption,
+				"thumbnailURL": reel.ThumbnailURL,
+				"videoURL":     reel.VideoURL,
+				"hlsURL":       reel.HLSURL,
+				"likeCount":    reel.LikeCount,
+				"commentCount": reel.CommentCount,
+				"viewCount":    reel.ViewCount,
+				"duration":     reel.Duration,
+				"saved":        true,
+				"createdAt":    reel.CreatedAt,
+				"user": gin.H{
+					"id":           reel.User.ID,
+					"username":     reel.User.Username,
+					"profileImage": reel.User.ProfileImage,
+				},
+			}
+		}
+	}
+
+	results := []gin.H{}
+	for _, item := range items {
+		var data gin.H
+		if item.ItemType == models.SavedItemPost {
+			data = posts[item.ItemID]
+		} else {
+			data = reels[item.ItemID]
+		}
+		if data == nil {
+			continue
+		}
+		results = append(results, gin.H{
+			"type":    item.ItemType,
+			"savedAt": item.CreatedAt,
+			"item":    data,
+		})
+	}
+	return results
+}
+
+// uniqueUints tekrar eden kimlikleri ilk görülme sırasını koruyarak ayıklar
+func uniqueUints(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
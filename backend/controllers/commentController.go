@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
 	"log"
 	"social-media-app/backend/database"
@@ -24,6 +25,13 @@ func GetComments(c *gin.Context) {
 	// Mevcut kullanıcı ID'sini al
 	userID := c.GetUint("userID")
 
+	// Gönderiyi görme yetkisi olmayan kullanıcı yorumları da göremez
+	var post models.Post
+	if err := database.DB.First(&post, postID).Error; err != nil || !CanViewPost(userID, post) {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Gönderi bulunamadı"})
+		return
+	}
+
 	var comments []models.Comment
 	result := database.DB.
 		Preload("User").
@@ -57,10 +65,18 @@ func GetComments(c *gin.Context) {
 		}
 	}
 
+	// Yorum ve yanıtlardaki mention'ları tek sorguda çözümle
+	fillCommentEntities(comments)
+
+	canComment, reason := canCommentOn(userID, post.UserID, post.CommentPermission)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"comments": comments,
+			"comments":              comments,
+			"canComment":            canComment,
+			"commentPermission":     effectiveCommentPermission(post.UserID, post.CommentPermission),
+			"commentDisabledReason": reason,
 		},
 	})
 }
@@ -81,11 +97,16 @@ func AddComment(c *gin.Context) {
 	}
 
 	var post models.Post
-	if err := database.DB.First(&post, postID).Error; err != nil {
+	if err := database.DB.First(&post, postID).Error; err != nil || !CanViewPost(userID.(uint), post) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Gönderi bulunamadı"})
 		return
 	}
 
+	if allowed, reason := canCommentOn(userID.(uint), post.UserID, post.CommentPermission); !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": reason})
+		return
+	}
+
 	var input struct {
 		Content string `json:"content" binding:"required"`
 	}
@@ -103,17 +124,20 @@ func AddComment(c *gin.Context) {
 		Content: input.Content,
 	}
 
-	if err := database.DB.Create(&comment).Error; err != nil {
+	// Yorumu ve gönderinin yorum sayacını aynı transaction içinde kaydet
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		return adjustCounter(tx, &models.Post{}, commentCountColumn, 1, post.ID)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Yorum eklenirken hata oluştu"})
 		return
 	}
 
-	// Post'un yorum sayısını güncelle
-	database.DB.Model(&post).Update("comment_count", post.CommentCount+1)
-
 	// Yorum yapan kullanıcının bilgilerini al
 	var user models.User
-	database.DB.Select("id, username, profile_image").First(&user, userID)
+	database.DB.Select("id, username, full_name, profile_image").First(&user, userID)
 
 	// Post sahibine bildirim oluştur (kendi postuna yorum yapıyorsa bildirim oluşturma)
 	if post.UserID != userID.(uint) {
@@ -131,13 +155,17 @@ func AddComment(c *gin.Context) {
 		}
 	}
 
+	// Yorumda bahsedilen kullanıcıları kaydet ve bildirim gönder
+	saveMentions(c.Request.Context(), user, models.Mention{PostID: comment.PostID, CommentID: &comment.ID}, comment.Content)
+
 	// Oluşturulan yorumu kullanıcı bilgileriyle birlikte döndür
 	responseComment := struct {
-		ID        uint      `json:"id"`
-		PostID    *uint     `json:"postID"`
-		Content   string    `json:"content"`
-		LikeCount int       `json:"likeCount"`
-		CreatedAt time.Time `json:"createdAt"`
+		ID        uint                `json:"id"`
+		PostID    *uint               `json:"postID"`
+		Content   string              `json:"content"`
+		Entities  []models.TextEntity `json:"entities"`
+		LikeCount int                 `json:"likeCount"`
+		CreatedAt time.Time           `json:"createdAt"`
 		User      struct {
 			ID           uint   `json:"id"`
 			Username     string `json:"username"`
@@ -147,6 +175,7 @@ func AddComment(c *gin.Context) {
 		ID:        comment.ID,
 		PostID:    comment.PostID,
 		Content:   comment.Content,
+		Entities:  buildTextEntities(comment.Content),
 		LikeCount: comment.LikeCount,
 		CreatedAt: comment.CreatedAt,
 		User: struct {
@@ -183,7 +212,7 @@ func ToggleCommentLike(c *gin.Context) {
 	}
 
 	var comment models.Comment
-	if err := database.DB.First(&comment, commentID).Error; err != nil {
+	if err := database.DB.First(&comment, commentID).Error; err != nil || !canViewCommentTarget(userID.(uint), comment) {
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "error": "Yorum bulunamadı"})
 		return
 	}
@@ -193,15 +222,17 @@ func ToggleCommentLike(c *gin.Context) {
 	likeExists := database.DB.Where("comment_id = ? AND user_id = ?", commentID, userID).First(&existingLike).Error == nil
 
 	if likeExists {
-		// Like varsa kaldır
-		if err := database.DB.Delete(&existingLike).Error; err != nil {
+		// Like varsa kaldır ve yorum beğeni sayısını güncelle
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Delete(&existingLike).Error; err != nil {
+				return err
+			}
+			return adjustCounter(tx, &models.Comment{}, likeCountColumn, -1, comment.ID)
+		}); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Beğeni kaldırılırken hata oluştu"})
 			return
 		}
 
-		// Yorum beğeni sayısını güncelle
-		database.DB.Model(&comment).Update("like_count", comment.LikeCount-1)
-
 		c.JSON(http.StatusOK, gin.H{
 			"success": true,
 			"message": "Yorum beğenisi kaldırıldı",
@@ -214,17 +245,20 @@ func ToggleCommentLike(c *gin.Context) {
 			UserID:    userID.(uint),
 		}
 
-		if err := database.DB.Create(&like).Error; err != nil {
+		// Beğeniyi ekle ve yorum beğeni sayısını güncelle
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&like).Error; err != nil {
+				return err
+			}
+			return adjustCounter(tx, &models.Comment{}, likeCountColumn, 1, comment.ID)
+		}); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Beğeni eklenirken hata oluştu"})
 			return
 		}
 
-		// Yorum beğeni sayısını güncelle
-		database.DB.Model(&comment).Update("like_count", comment.LikeCount+1)
-
 		// Beğeni yapan kullanıcının bilgilerini getir
 		var user models.User
-		database.DB.Select("id, username, profile_image").First(&user, userID)
+		database.DB.Select("id, username, full_name, profile_image").First(&user, userID)
 
 		// Yorum sahibine bildirim oluştur (kendi yorumunu beğeniyorsa bildirim oluşturma)
 		if comment.UserID != userID.(uint) {
@@ -285,23 +319,29 @@ func DeleteComment(c *gin.Context) {
 		return
 	}
 
-	// Yorumu sil
-	if err := database.DB.Delete(&comment).Error; err != nil {
+	// Yorumu sil; yorum sayacı sadece üst seviye yorumları saydığından yanıtlarda sayaç değişmez
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&comment).Error; err != nil {
+			return err
+		}
+		if comment.ParentID != nil {
+			return nil
+		}
+		if comment.PostID != nil {
+			return adjustCounter(tx, &models.Post{}, commentCountColumn, -1, *comment.PostID)
+		}
+		if comment.ReelID != nil {
+			return adjustCounter(tx, &models.Reels{}, commentCountColumn, -1, *comment.ReelID)
+		}
+		return nil
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"message": "Yorum silinirken bir hata oluştu",
+			"message": "Yorum silinirken bir hata oluştu",
 		})
 		return
 	}
 
-	// Gönderi yorum sayısını azalt
-	var post models.Post
-	if comment.PostID != nil {
-		if err := database.DB.First(&post, *comment.PostID).Error; err == nil && post.CommentCount > 0 {
-			database.DB.Model(&post).Update("comment_count", post.CommentCount-1)
-		}
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Yorum başarıyla silindi",
@@ -373,6 +413,170 @@ func ReportComment(c *gin.Context) {
 	})
 }
 
+// commentTarget yorumun ait olduğu gönderi veya reelin sahibini, kitlesini ve yorum izni ayarını getirir
+func commentTarget(comment models.Comment) (ownerID uint, audience, permission string, ok bool) {
+	if comment.PostID != nil {
+		var post models.Post
+		if database.DB.First(&post, *comment.PostID).Error != nil {
+			return 0, "", "", false
+		}
+		return post.UserID, post.Audience, post.CommentPermission, true
+	}
+	if comment.ReelID != nil {
+		var reel models.Reels
+		if database.DB.First(&reel, *comment.ReelID).Error != nil {
+			return 0, "", "", false
+		}
+		return reel.UserID, reel.Audience, reel.CommentPermission, true
+	}
+	return 0, "", "", false
+}
+
+// canViewCommentTarget yorumun ait olduğu gönderi veya reelin kullanıcıya görünür olup olmadığını kontrol eder.
+// Yorum listeleriyle aynı kurallar uygulanır; taslak, zamanlanmış ve arşivlenmiş içeriklerin yorumlarını
+// sadece içeriğin sahibi görebilir.
+func canViewCommentTarget(userID uint, comment models.Comment) bool {
+	if comment.PostID != nil {
+		var post models.Post
+		return database.DB.First(&post, *comment.PostID).Error == nil && CanViewPost(userID, post)
+	}
+	if comment.ReelID != nil {
+		var reel models.Reels
+		return database.DB.First(&reel, *comment.ReelID).Error == nil && canViewReel(userID, reel)
+	}
+	return false
+}
+
+// isValidCommentPermission yorum izni değerini doğrular
+func isValidCommentPermission(permission string) bool {
+	return permission == models.CommentPermissionAll ||
+		permission == models.CommentPermissionFollowers ||
+		permission == models.CommentPermissionNone
+}
+
+// effectiveCommentPermission içeriğe özel izin varsa onu, yoksa sahibinin hesap ayarını döndürür
+func effectiveCommentPermission(ownerID uint, override string) string {
+	if isValidCommentPermission(override) {
+		return override
+	}
+	var owner models.User
+	if err := database.DB.Select("id, comment_permission").First(&owner, ownerID).Error; err != nil ||
+		!isValidCommentPermission(owner.CommentPermission) {
+		return models.CommentPermissionAll
+	}
+	return owner.CommentPermission
+}
+
+// canCommentOn kullanıcının ownerID'ye ait içeriğe yorum yapıp yapamayacağını kontrol eder.
+// Yapamıyorsa istemciye gösterilecek nedeni döndürür. İçerik sahibi her zaman yorum yapabilir.
+func canCommentOn(userID, ownerID uint, override string) (bool, string) {
+	if userID == ownerID {
+		return true, ""
+	}
+	if isBlockedBetween(userID, ownerID) {
+		return false, "Bu içeriğe yorum yapamazsınız"
+	}
+
+	switch effectiveCommentPermission(ownerID, override) {
+	case models.CommentPermissionNone:
+		return false, "Bu içerik yorumlara kapalı"
+	case models.CommentPermissionFollowers:
+		var count int64
+		database.DB.Model(&models.Follow{}).
+			Where("follower_id = ? AND following_id = ?", userID, ownerID).
+			Count(&count)
+		if count == 0 {
+			return false, "Bu içeriğe sadece takipçiler yorum yapabilir"
+		}
+	}
+	return true, ""
+}
+
+// commentPermissionRequest gönderi/reel yorum izni güncelleme isteği. Boş değer özel ayarı kaldırır.
+type commentPermissionRequest struct {
+	CommentPermission string `json:"commentPermission"`
+}
+
+// bindCommentPermission isteği okur ve doğrular
+func bindCommentPermission(c *gin.Context) (string, bool) {
+	var request commentPermissionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+		return "", false
+	}
+	if request.CommentPermission != "" && !isValidCommentPermission(request.CommentPermission) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Yorum izni all, followers veya none olmalı"})
+		return "", false
+	}
+	return request.CommentPermission, true
+}
+
+// UpdatePostCommentPermission - Gönderiye özel yorum iznini ayarlar (sadece gönderi sahibi)
+func UpdatePostCommentPermission(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var post models.Post
+	if err := database.DB.First(&post, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Gönderi bulunamadı"})
+		return
+	}
+	if post.UserID != userID {
+		c.JSON(http.StatusForbidden, Response{Success: false, Message: "Bu gönderinin ayarlarını değiştirme yetkiniz yok"})
+		return
+	}
+
+	permission, ok := bindCommentPermission(c)
+	if !ok {
+		return
+	}
+	if err := database.DB.Model(&post).Update("comment_permission", permission).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yorum izni güncellenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Yorum izni güncellendi",
+		Data: gin.H{
+			"commentPermission":          permission,
+			"effectiveCommentPermission": effectiveCommentPermission(post.UserID, permission),
+		},
+	})
+}
+
+// UpdateReelCommentPermission - Reele özel yorum iznini ayarlar (sadece reel sahibi)
+func UpdateReelCommentPermission(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var reel models.Reels
+	if err := database.DB.First(&reel, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Reel bulunamadı"})
+		return
+	}
+	if reel.UserID != userID {
+		c.JSON(http.StatusForbidden, Response{Success: false, Message: "Bu reelin ayarlarını değiştirme yetkiniz yok"})
+		return
+	}
+
+	permission, ok := bindCommentPermission(c)
+	if !ok {
+		return
+	}
+	if err := database.DB.Model(&reel).Update("comment_permission", permission).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yorum izni güncellenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Yorum izni güncellendi",
+		Data: gin.H{
+			"commentPermission":          permission,
+			"effectiveCommentPermission": effectiveCommentPermission(reel.UserID, permission),
+		},
+	})
+}
+
 // ReplyToComment yanıt vermek için kullanılır
 func ReplyToComment(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -389,11 +593,17 @@ func ReplyToComment(c *gin.Context) {
 	}
 
 	var parentComment models.Comment
-	if err := database.DB.First(&parentComment, commentID).Error; err != nil {
+	if err := database.DB.First(&parentComment, commentID).Error; err != nil || !canViewCommentTarget(userID.(uint), parentComment) {
 		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Yanıt verilecek yorum bulunamadı"})
 		return
 	}
 
+	ownerID, _, permission, _ := commentTarget(parentComment)
+	if allowed, reason := canCommentOn(userID.(uint), ownerID, permission); !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": reason})
+		return
+	}
+
 	var input struct {
 		Content string `json:"content" binding:"required"`
 	}
@@ -418,7 +628,7 @@ func ReplyToComment(c *gin.Context) {
 
 	// Yanıt veren kullanıcının bilgilerini al
 	var user models.User
-	database.DB.Select("id, username, profile_image").First(&user, userID)
+	database.DB.Select("id, username, full_name, profile_image").First(&user, userID)
 
 	// Ana yorum sahibine bildirim oluştur (kendi yorumuna yanıt veriyorsa bildirim oluşturma)
 	if parentComment.UserID != userID.(uint) {
@@ -436,14 +646,18 @@ func ReplyToComment(c *gin.Context) {
 		}
 	}
 
+	// Yanıtta bahsedilen kullanıcıları kaydet ve bildirim gönder
+	saveMentions(c.Request.Context(), user, models.Mention{PostID: reply.PostID, ReelID: parentComment.ReelID, CommentID: &reply.ID}, reply.Content)
+
 	// Yanıt veren kullanıcının bilgileriyle yanıtı döndür
 	responseReply := struct {
-		ID        uint      `json:"id"`
-		PostID    *uint     `json:"postID"`
-		ParentID  uint      `json:"parentID"`
-		Content   string    `json:"content"`
-		LikeCount int       `json:"likeCount"`
-		CreatedAt time.Time `json:"createdAt"`
+		ID        uint                `json:"id"`
+		PostID    *uint               `json:"postID"`
+		ParentID  uint                `json:"parentID"`
+		Content   string              `json:"content"`
+		Entities  []models.TextEntity `json:"entities"`
+		LikeCount int                 `json:"likeCount"`
+		CreatedAt time.Time           `json:"createdAt"`
 		User      struct {
 			ID           uint   `json:"id"`
 			Username     string `json:"username"`
@@ -454,6 +668,7 @@ func ReplyToComment(c *gin.Context) {
 		PostID:    reply.PostID,
 		ParentID:  *reply.ParentID,
 		Content:   reply.Content,
+		Entities:  buildTextEntities(reply.Content),
 		LikeCount: reply.LikeCount,
 		CreatedAt: reply.CreatedAt,
 		User: struct {
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// commentAction kullanıcı adına bir yoruma beğeni veya yanıt isteği gönderir ve yanıt durumunu döndürür
+func commentAction(userID, commentID uint, action string) int {
+	router := gin.New()
+	auth := func(c *gin.Context) { c.Set("userID", userID) }
+	if action == "like" {
+		router.POST("/comments/:id/like", auth, ToggleCommentLike)
+	} else {
+		router.POST("/comments/:commentID/reply", auth, ReplyToComment)
+	}
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/comments/%d/%s", commentID, action),
+		strings.NewReader(`{"content":"yanıt"}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func TestCommentActionsFollowTargetVisibility(t *testing.T) {
+	setupTestDatabase(t)
+	gin.SetMode(gin.TestMode)
+	owner := createTestUser(t, "sahip")
+	viewer := createTestUser(t, "izleyici")
+
+	archivedAt := time.Now()
+	published := models.Post{UserID: owner.ID, Content: "yayında", PublishStatus: models.PublishStatusPublished}
+	draft := models.Post{UserID: owner.ID, Content: "taslak", PublishStatus: models.PublishStatusDraft}
+	archived := models.Post{UserID: owner.ID, Content: "arşiv", PublishStatus: models.PublishStatusPublished, ArchivedAt: &archivedAt}
+	database.DB.Create(&published)
+	database.DB.Create(&draft)
+	database.DB.Create(&archived)
+	draftReel := createTestReel(t, owner.ID, 15)
+	database.DB.Model(&draftReel).UpdateColumn("publish_status", models.PublishStatusDraft)
+
+	// Sahibin taslak veya arşivlenmiş içerikte bıraktığı yorumlar
+	comments := map[string]models.Comment{
+		"yayındaki gönderi": {UserID: owner.ID, PostID: &published.ID, Content: "a"},
+		"taslak gönderi":    {UserID: owner.ID, PostID: &draft.ID, Content: "b"},
+		"arşivlenmiş":       {UserID: owner.ID, PostID: &archived.ID, Content: "c"},
+		"taslak reel":       {UserID: owner.ID, ReelID: &draftReel.ID, Content: "d"},
+	}
+	for name, comment := range comments {
+		database.DB.Create(&comment)
+		comments[name] = comment
+	}
+
+	tests := []struct {
+		target string
+		user   models.User
+		want   bool
+	}{
+		{"yayındaki gönderi", viewer, true},
+		{"taslak gönderi", viewer, false},
+		{"arşivlenmiş", viewer, false},
+		{"taslak reel", viewer, false},
+		{"taslak gönderi", owner, true},
+		{"arşivlenmiş", owner, true},
+		{"taslak reel", owner, true},
+	}
+	for _, tt := range tests {
+		comment := comments[tt.target]
+		like := commentAction(tt.user.ID, comment.ID, "like")
+		reply := commentAction(tt.user.ID, comment.ID, "reply")
+		if visible := like != http.StatusNotFound; visible != tt.want {
+			t.Errorf("%s: %s beğeni durumu %d", tt.target, tt.user.Username, like)
+		}
+		if visible := reply != http.StatusNotFound; visible != tt.want {
+			t.Errorf("%s: %s yanıt durumu %d", tt.target, tt.user.Username, reply)
+		}
+	}
+
+	var replies int64
+	database.DB.Model(&models.Comment{}).Where("parent_id IS NOT NULL AND user_id = ?", viewer.ID).Count(&replies)
+	if replies != 1 {
+		t.Errorf("izleyicinin %d yanıtı kaydedildi, sadece yayındaki gönderiye yanıt verebilmeli", replies)
+	}
+}
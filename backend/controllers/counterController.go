@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"social-media-app/backend/database"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Varsayılan uzlaştırma aralığı (COUNTER_RECONCILE_INTERVAL_HOURS ile değiştirilebilir)
+const defaultCounterReconcileInterval = 24 * time.Hour
+
+// Raporda sayaç başına gösterilecek en fazla sapma örneği
+const maxCounterDriftSamples = 20
+
+// counterSources her sayacın kaynak tablolardan nasıl hesaplandığını tanımlar. actual, dış sorgudaki
+// satıra bağlı bir alt sorgudur; scope boş değilse sadece bu koşula uyan satırlar uzlaştırılır.
+var counterSources = []struct {
+	table  string
+	column string
+	scope  string
+	actual string
+}{
+	{"posts", likeCountColumn, "deleted_at IS NULL",
+		`SELECT COUNT(*) FROM likes WHERE likes.post_id = posts.id`},
+	{"posts", commentCountColumn, "deleted_at IS NULL",
+		`SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id
+			AND comments.parent_id IS NULL AND comments.deleted_at IS NULL`},
+	{"posts", shareCountColumn, "deleted_at IS NULL",
+		`SELECT COUNT(*) FROM post_shares WHERE post_shares.post_id = posts.id`},
+	{"reels", likeCountColumn, "deleted_at IS NULL",
+		`SELECT COUNT(*) FROM reel_likes WHERE reel_likes.reel_id = reels.id`},
+	{"reels", commentCountColumn, "deleted_at IS NULL",
+		`SELECT COUNT(*) FROM comments WHERE comments.reel_id = reels.id
+			AND comments.parent_id IS NULL AND comments.deleted_at IS NULL`},
+	{"reels", shareCountColumn, "deleted_at IS NULL",
+		`SELECT COUNT(*) FROM reel_shares WHERE reel_shares.reel_id = reels.id`},
+	{"reels", viewCountColumn, "deleted_at IS NULL",
+		`SELECT reels.legacy_view_count + COUNT(*) FROM reel_views WHERE reel_views.reel_id = reels.id`},
+	{"reels", watchSecondsColumn, "deleted_at IS NULL",
+		`SELECT COALESCE(SUM(watch_seconds), 0) FROM reel_views WHERE reel_views.reel_id = reels.id`},
+	{"reels", completedCountColumn, "deleted_at IS NULL",
+		`SELECT COUNT(*) FROM reel_views WHERE reel_views.reel_id = reels.id AND reel_views.completed = 1`},
+	{"comments", likeCountColumn, "deleted_at IS NULL",
+		`SELECT COUNT(*) FROM comment_likes WHERE comment_likes.comment_id = comments.id`},
+	{"stories", viewCountColumn, "",
+		`SELECT COUNT(*) FROM story_views WHERE story_views.story_id = stories.id`},
+	{"tags", postCountColumn, "deleted_at IS NULL",
+		`SELECT COUNT(*) FROM post_tags JOIN posts ON posts.id = post_tags.post_id
+			WHERE post_tags.tag_id = tags.id AND posts.deleted_at IS NULL`},
+	{"poll_options", voteCountColumn, "",
+		`SELECT COUNT(*) FROM poll_vote_options WHERE poll_vote_options.option_id = poll_options.id`},
+	{"polls", voterCountColumn, "",
+		`SELECT COUNT(*) FROM poll_votes WHERE poll_votes.poll_id = polls.id`},
+	{"media", mediaReferenceCountColumn, "",
+		`SELECT COUNT(*) FROM media_references WHERE media_references.media_id = media.id`},
+}
+
+// CounterDrift kayıtlı sayaç değerinin kaynak tablolardan hesaplanan değerden sapması
+type CounterDrift struct {
+	ID     uint `json:"id"`
+	Stored int  `json:"stored"`
+	Actual int  `json:"actual"`
+}
+
+// CounterReconciliation tek bir sayacın uzlaştırma sonucu
+type CounterReconciliation struct {
+	Counter string         `json:"counter"` // tablo.sütun
+	Drifted int            `json:"drifted"` // Sapması olan kayıt sayısı
+	Samples []CounterDrift `json:"samples"` // İlk sapmalar
+}
+
+// CounterReport bir uzlaştırma çalışmasının özeti
+type CounterReport struct {
+	DryRun       bool                    `json:"dryRun"`
+	TotalDrifted int                     `json:"totalDrifted"`
+	Counters     []CounterReconciliation `json:"counters"`
+}
+
+// StartCounterReconciliation sayaç uzlaştırmasını zamanlanmış görev olarak başlatır
+func StartCounterReconciliation() {
+	interval := durationFromEnv("COUNTER_RECONCILE_INTERVAL_HOURS", defaultCounterReconcileInterval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			report, err := RunCounterReconciliation(false)
+			if err != nil {
+				fmt.Printf("Sayaç uzlaştırma hatası: %v\n", err)
+				continue
+			}
+			if report.TotalDrifted > 0 {
+				fmt.Printf("Sayaç uzlaştırma tamamlandı: %d kayıtta sapma düzeltildi\n", report.TotalDrifted)
+			}
+		}
+	}()
+}
+
+// ReconcileCounters - Sayaç uzlaştırmasını elle çalıştırır (sadece admin). ?dryRun=true ile sadece sapmalar raporlanır.
+func ReconcileCounters(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dryRun"))
+
+	report, err := RunCounterReconciliation(dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Message: "Sayaç uzlaştırma başarısız: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: fmt.Sprintf("%d kayıtta sayaç sapması bulundu", report.TotalDrifted),
+		Data:    report,
+	})
+}
+
+// RunCounterReconciliation tüm etkileşim sayaçlarını kaynak tablolardan yeniden hesaplar ve
+// sapmaları düzeltir. dryRun ise sapmalar sadece raporlanır, hiçbir sayaç güncellenmez.
+func RunCounterReconciliation(dryRun bool) (CounterReport, error) {
+	report := CounterReport{DryRun: dryRun, Counters: []CounterReconciliation{}}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, source := range counterSources {
+			condition := "COALESCE(" + source.column + ", 0) <> (" + source.actual + ")"
+			if source.scope != "" {
+				condition = source.scope + " AND " + condition
+			}
+
+			drifts := []CounterDrift{}
+			if err := tx.Raw("SELECT id, COALESCE(" + source.column + ", 0) AS stored, (" + source.actual + ") AS actual FROM " +
+				source.table + " WHERE " + condition + " ORDER BY id").Scan(&drifts).Error; err != nil {
+				return fmt.Errorf("%s.%s okunamadı: %w", source.table, source.column, err)
+			}
+
+			result := CounterReconciliation{
+				Counter: source.table + "." + source.column,
+				Drifted: len(drifts),
+				Samples: drifts,
+			}
+			if len(result.Samples) > maxCounterDriftSamples {
+				result.Samples = result.Samples[:maxCounterDriftSamples]
+			}
+			report.Counters = append(report.Counters, result)
+			report.TotalDrifted += len(drifts)
+
+			if dryRun || len(drifts) == 0 {
+				continue
+			}
+			if err := tx.Exec("UPDATE " + source.table + " SET " + source.column + " = (" + source.actual + ") WHERE " +
+				condition).Error; err != nil {
+				return fmt.Errorf("%s.%s güncellenemedi: %w", source.table, source.column, err)
+			}
+		}
+		return nil
+	})
+	return report, err
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+)
+
+// counterDriftFixture sayaçları bilerek yanlış bırakılmış kayıtlar oluşturur
+type counterDriftFixture struct {
+	post   models.Post
+	reel   models.Reels
+	option models.PollOption
+	poll   models.Poll
+}
+
+func createCounterDriftFixture(t *testing.T) counterDriftFixture {
+	t.Helper()
+	author := createTestUser(t, "yazar")
+	fan := createTestUser(t, "hayran")
+	other := createTestUser(t, "diger")
+
+	// İki beğenisi olan gönderi, sayaçta 5 görünüyor
+	post := models.Post{UserID: author.ID, Content: "merhaba", LikeCount: 5}
+	database.DB.Create(&post)
+	database.DB.Create(&models.Like{UserID: fan.ID, PostID: post.ID, CreatedAt: time.Now()})
+	database.DB.Create(&models.Like{UserID: other.ID, PostID: post.ID, CreatedAt: time.Now()})
+
+	// Oturum tablosundan önce sayılmış 3 görüntülenme ve 2 oturum: toplam 5, sayaçta 0
+	reel := models.Reels{UserID: author.ID, VideoURL: "/uploads/videos/a.mp4", Status: models.ReelStatusReady}
+	database.DB.Create(&reel)
+	database.DB.Model(&reel).UpdateColumns(map[string]interface{}{"legacy_view_count": 3, "view_count": 0})
+	for _, viewer := range []models.User{fan, other} {
+		database.DB.Create(&models.ReelView{ReelID: reel.ID, ViewerID: viewer.ID, StartedAt: time.Now(), LastEventAt: time.Now()})
+	}
+
+	// Bir oy almış anket seçeneği; seçenek ve oy veren sayaçları sıfırda kalmış
+	_, poll := createTestPoll(t, author.ID, false, "Evet", "Hayır")
+	option := poll.Options[0]
+	vote := models.PollVote{PollID: poll.ID, UserID: fan.ID, CreatedAt: time.Now()}
+	database.DB.Create(&vote)
+	database.DB.Create(&models.PollVoteOption{VoteID: vote.ID, OptionID: option.ID})
+
+	return counterDriftFixture{post: post, reel: reel, option: option, poll: poll}
+}
+
+// driftedCounters raporda sapması olan sayaçları tablo.sütun → sapan kayıt sayısı olarak döndürür
+func driftedCounters(report CounterReport) map[string]int {
+	drifted := make(map[string]int)
+	for _, counter := range report.Counters {
+		if counter.Drifted > 0 {
+			drifted[counter.Counter] = counter.Drifted
+		}
+	}
+	return drifted
+}
+
+func TestRunCounterReconciliationDryRun(t *testing.T) {
+	setupTestDatabase(t)
+	fixture := createCounterDriftFixture(t)
+
+	report, err := RunCounterReconciliation(true)
+	if err != nil {
+		t.Fatalf("Uzlaştırma başarısız: %v", err)
+	}
+	want := map[string]int{
+		"posts.like_count":        1,
+		"reels.view_count":        1,
+		"poll_options.vote_count": 1,
+		"polls.voter_count":       1,
+		"reels.watch_seconds":     0,
+		"media.reference_count":   0,
+		"stories.view_count":      0,
+		"comments.like_count":     0,
+		"reels.completed_count":   0,
+		"posts.comment_count":     0,
+		"tags.post_count":         0,
+		"reels.like_count":        0,
+		"posts.share_count":       0,
+		"reels.share_count":       0,
+		"reels.comment_count":     0,
+	}
+	drifted := driftedCounters(report)
+	for counter, count := range want {
+		if drifted[counter] != count {
+			t.Errorf("%s: %d sapma raporlandı, beklenen %d", counter, drifted[counter], count)
+		}
+	}
+	if report.TotalDrifted != 4 {
+		t.Errorf("toplam sapma %d, beklenen 4", report.TotalDrifted)
+	}
+
+	// Kuru çalıştırma sayaçlara dokunmaz
+	if got := counterValue(database.DB, &models.Post{}, likeCountColumn, fixture.post.ID); got != 5 {
+		t.Errorf("kuru çalıştırmada beğeni sayacı %d oldu, 5 kalmalı", got)
+	}
+}
+
+func TestRunCounterReconciliationAppliesOnFirstRun(t *testing.T) {
+	setupTestDatabase(t)
+	fixture := createCounterDriftFixture(t)
+
+	report, err := RunCounterReconciliation(false)
+	if err != nil {
+		t.Fatalf("Uzlaştırma başarısız: %v", err)
+	}
+	if report.TotalDrifted != 4 {
+		t.Errorf("toplam sapma %d, beklenen 4", report.TotalDrifted)
+	}
+
+	checks := []struct {
+		name   string
+		model  interface{}
+		column string
+		id     uint
+		want   int
+	}{
+		{"gönderi beğenileri", &models.Post{}, likeCountColumn, fixture.post.ID, 2},
+		{"eski görüntülenmeler dahil reel görüntülenmeleri", &models.Reels{}, viewCountColumn, fixture.reel.ID, 5},
+		{"seçenek oyları", &models.PollOption{}, voteCountColumn, fixture.option.ID, 1},
+		{"oy veren sayısı", &models.Poll{}, voterCountColumn, fixture.poll.ID, 1},
+	}
+	for _, check := range checks {
+		if got := counterValue(database.DB, check.model, check.column, check.id); got != check.want {
+			t.Errorf("%s: %d, beklenen %d", check.name, got, check.want)
+		}
+	}
+
+	// Düzeltilen sayaçlar bir sonraki çalışmada sapma göstermez
+	report, err = RunCounterReconciliation(false)
+	if err != nil {
+		t.Fatalf("Uzlaştırma başarısız: %v", err)
+	}
+	if report.TotalDrifted != 0 {
+		t.Errorf("ikinci çalışmada %d sapma kaldı: %v", report.TotalDrifted, driftedCounters(report))
+	}
+}
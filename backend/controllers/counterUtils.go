@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+)
+
+// Etkileşim sayacı sütunları
+const (
+	likeCountColumn    = "like_count"
+	commentCountColumn = "comment_count"
+	shareCountColumn   = "share_count"
+	viewCountColumn    = "view_count"
+	postCountColumn    = "post_count"
+
+	watchSecondsColumn   = "watch_seconds"
+	completedCountColumn = "completed_count"
+
+	voteCountColumn  = "vote_count"
+	voterCountColumn = "voter_count"
+
+	mediaReferenceCountColumn = "reference_count"
+)
+
+// adjustCounter verilen kayıtların sayaç sütununu veritabanında atomik olarak delta kadar değiştirir.
+// Okunan eski değer kullanılmadığı için eşzamanlı istekler birbirinin artışını ezmez; sayaç sıfırın
+// altına düşmez. Sayacın kaynağı olan etkileşim kaydıyla aynı transaction içinde çağrılmalıdır.
+func adjustCounter(tx *gorm.DB, model interface{}, column string, delta int, ids ...uint) error {
+	if delta == 0 || len(ids) == 0 {
+		return nil
+	}
+	return tx.Model(model).Where("id IN ?", ids).
+		UpdateColumn(column, gorm.Expr("MAX(COALESCE("+column+", 0) + ?, 0)", delta)).Error
+}
+
+// counterValue sayacın güncel değerini okur (atomik güncellemeden sonra yanıtta göstermek için)
+func counterValue(db *gorm.DB, model interface{}, column string, id uint) int {
+	var value int
+	db.Model(model).Where("id = ?", id).Select("COALESCE(" + column + ", 0)").Scan(&value)
+	return value
+}
@@ -264,3 +264,103 @@ func RequestDataDeletion(c *gin.Context) {
 		Message: "Veri silme talebiniz alındı. İşlem tamamlandığında size bildirim göndereceğiz.",
 	})
 }
+
+// ExportUserData kullanıcının verilerini JSON dosyası olarak indirir
+func ExportUserData(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, Response{Success: false, Message: "Oturum bilgisi bulunamadı"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Kullanıcı bulunamadı"})
+		return
+	}
+
+	var posts []models.Post
+	database.DB.Preload("Images").Where("user_id = ?", userID).Order("created_at desc").Find(&posts)
+
+	var reels []models.Reels
+	database.DB.Where("user_id = ?", userID).Order("created_at desc").Find(&reels)
+
+	var comments []models.Comment
+	database.DB.Where("user_id = ?", userID).Order("created_at desc").Find(&comments)
+
+	var following []string
+	database.DB.Table("follows").Joins("JOIN users ON users.id = follows.following_id").
+		Where("follows.follower_id = ?", userID).Pluck("users.username", &following)
+
+	var followers []string
+	database.DB.Table("follows").Joins("JOIN users ON users.id = follows.follower_id").
+		Where("follows.following_id = ?", userID).Pluck("users.username", &followers)
+
+	var searchHistory []models.SearchHistoryEntry
+	database.DB.Where("user_id = ?", userID).Order("created_at desc").Find(&searchHistory)
+
+	postData := []gin.H{}
+	for _, post := range posts {
+		imageURLs := []string{}
+		for _, image := range post.Images {
+			imageURLs = append(imageURLs, image.URL)
+		}
+		postData = append(postData, gin.H{
+			"id":        post.ID,
+			"content":   post.Content,
+			"caption":   post.Caption,
+			"tags":      splitTagsString(post.TagsString),
+			"images":    imageURLs,
+			"createdAt": post.CreatedAt,
+		})
+	}
+
+	reelData := []gin.H{}
+	for _, reel := range reels {
+		reelData = append(reelData, gin.H{
+			"id":        reel.ID,
+			"caption":   reel.Caption,
+			"videoURL":  reel.VideoURL,
+			"music":     reel.Music,
+			"createdAt": reel.CreatedAt,
+		})
+	}
+
+	commentData := []gin.H{}
+	for _, comment := range comments {
+		commentData = append(commentData, gin.H{
+			"id":        comment.ID,
+			"postId":    comment.PostID,
+			"reelId":    comment.ReelID,
+			"parentId":  comment.ParentID,
+			"content":   comment.Content,
+			"createdAt": comment.CreatedAt,
+		})
+	}
+
+	export := gin.H{
+		"exportedAt": time.Now(),
+		"profile": gin.H{
+			"id":           user.ID,
+			"username":     user.Username,
+			"email":        user.Email,
+			"fullName":     user.FullName,
+			"phone":        user.Phone,
+			"bio":          user.Bio,
+			"location":     user.Location,
+			"website":      user.Website,
+			"profileImage": user.ProfileImage,
+			"isPrivate":    user.IsPrivate,
+			"createdAt":    user.CreatedAt,
+		},
+		"posts":         postData,
+		"reels":         reelData,
+		"comments":      commentData,
+		"following":     following,
+		"followers":     followers,
+		"searchHistory": searchHistory,
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-veriler.json\"", user.Username))
+	c.JSON(http.StatusOK, export)
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"social-media-app/backend/auth"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Varyant üretimi için en fazla bekleme süresi
+const imageVariantTimeout = 2 * time.Minute
+
+// storeProcessedImage yüklenen görseli yönünü düzelterek ve meta verilerini silerek depoya yazar,
+// ardından boyut varyantlarını arka planda üretir. GIF'ler animasyon korunsun diye olduğu gibi saklanır.
+func storeProcessedImage(ctx context.Context, key string, data []byte) error {
+	decoded, err := services.DecodeImage(data)
+	if err != nil {
+		return err
+	}
+
+	if decoded.Format == services.ImageFormatGIF {
+		_, err := blobStore.Put(ctx, key, bytes.NewReader(data), int64(len(data)), services.ImageContentType(decoded.Format))
+		return err
+	}
+
+	// Orijinal boyut, EXIF/GPS bilgisi olmadan yeniden kodlanır
+	format := decoded.FallbackFormat()
+	sanitized, err := services.EncodeImage(decoded.Image, format)
+	if err != nil {
+		return err
+	}
+	if _, err := blobStore.Put(ctx, key, bytes.NewReader(sanitized), int64(len(sanitized)), services.ImageContentType(format)); err != nil {
+		return err
+	}
+
+	go generateImageVariants(key, decoded)
+	return nil
+}
+
+// generateImageVariants görselin thumb/feed/full varyantlarını üretip depoya ve image_variants tablosuna yazar
+func generateImageVariants(sourceKey string, decoded *services.DecodedImage) {
+	ctx, cancel := context.WithTimeout(context.Background(), imageVariantTimeout)
+	defer cancel()
+
+	variants, err := services.BuildImageVariants(ctx, decoded)
+	if err != nil {
+		fmt.Printf("Görsel varyantları üretilemedi (%s): %v\n", sourceKey, err)
+	}
+
+	base := strings.TrimSuffix(sourceKey, path.Ext(sourceKey))
+	var rows []models.ImageVariant
+	for _, variant := range variants {
+		variantKey := base + "_" + variant.Variant + services.ImageFileExt(variant.Format)
+		info, err := blobStore.Put(ctx, variantKey, bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.ContentType)
+		if err != nil {
+			fmt.Printf("Görsel varyantı kaydedilemedi (%s): %v\n", variantKey, err)
+			continue
+		}
+		rows = append(rows, models.ImageVariant{
+			SourceKey:  sourceKey,
+			Variant:    variant.Variant,
+			Format:     variant.Format,
+			Width:      variant.Width,
+			Height:     variant.Height,
+			Size:       info.Size,
+			StorageKey: variantKey,
+			CreatedAt:  time.Now(),
+		})
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	database.DB.Where("source_key = ?", sourceKey).Delete(&models.ImageVariant{})
+	if err := database.DB.Create(&rows).Error; err != nil {
+		fmt.Printf("Görsel varyant kayıtları oluşturulamadı (%s): %v\n", sourceKey, err)
+	}
+}
+
+// deleteImageVariants bir görselin tüm varyantlarını depodan ve veritabanından siler
+func deleteImageVariants(ctx context.Context, sourceKey string) {
+	var variants []models.ImageVariant
+	database.DB.Where("source_key = ?", sourceKey).Find(&variants)
+	for _, variant := range variants {
+		if err := blobStore.Delete(ctx, variant.StorageKey); err != nil {
+			fmt.Printf("Görsel varyantı silinemedi: %s - %s\n", variant.StorageKey, err.Error())
+		}
+	}
+	database.DB.Where("source_key = ?", sourceKey).Delete(&models.ImageVariant{})
+}
+
+// imageSaveDataRequested istemcinin veri tasarrufu isteyip istemediğini belirler. Öncelik sırası:
+// Save-Data başlığı, saveData sorgu parametresi, oturumdaki kullanıcının SaveDataMode ayarı.
+// Karar kullanıcı ayarından verildiyse perUser true döner; yanıt bu durumda kullanıcıya özeldir.
+func imageSaveDataRequested(c *gin.Context) (saveData bool, perUser bool) {
+	if header := c.GetHeader("Save-Data"); header != "" {
+		return strings.EqualFold(header, "on"), false
+	}
+	if saveData, err := strconv.ParseBool(c.Query("saveData")); err == nil {
+		return saveData, false
+	}
+
+	tokenString := c.GetHeader("Authorization")
+	if tokenString == "" {
+		return false, false
+	}
+	userID, err := auth.VerifyToken(tokenString)
+	if err != nil {
+		return false, false
+	}
+	var settings models.AppSettings
+	if err := database.DB.Select("save_data_mode").Where("user_id = ?", userID).First(&settings).Error; err != nil {
+		return false, true
+	}
+	return settings.SaveDataMode, true
+}
+
+// selectImageVariant ?w= parametresi, Accept başlığı ve veri tasarrufu tercihine göre en uygun varyantı seçer.
+// Varyant yoksa (GIF veya henüz işlenmemiş görsel) false döner ve orijinal servis edilir.
+func selectImageVariant(c *gin.Context, sourceKey string, saveData bool) (models.ImageVariant, bool) {
+	var variants []models.ImageVariant
+	database.DB.Where("source_key = ?", sourceKey).Find(&variants)
+	if len(variants) == 0 {
+		return models.ImageVariant{}, false
+	}
+
+	// Hedef boyut: istenen genişliği karşılayan en küçük varyant, yoksa en büyüğü
+	target := len(services.ImageVariantSpecs) - 1
+	if width, err := strconv.Atoi(c.Query("w")); err == nil && width > 0 {
+		for i, spec := range services.ImageVariantSpecs {
+			if spec.MaxWidth >= width {
+				target = i
+				break
+			}
+		}
+	}
+	if target > 0 && saveData {
+		target--
+	}
+
+	// Tercih edilen format sırası
+	accept := c.GetHeader("Accept")
+	var formats []string
+	if strings.Contains(accept, "image/avif") {
+		formats = append(formats, services.ImageFormatAVIF)
+	}
+	if strings.Contains(accept, "image/webp") {
+		formats = append(formats, services.ImageFormatWebP)
+	}
+	formats = append(formats, services.ImageFormatJPEG, services.ImageFormatPNG)
+
+	byKey := make(map[string]models.ImageVariant, len(variants))
+	for _, variant := range variants {
+		byKey[variant.Variant+"/"+variant.Format] = variant
+	}
+
+	// Küçük kaynaklarda büyük varyantlar üretilmediği için hedeften aşağı, sonra yukarı doğru aranır
+	var order []int
+	for i := target; i >= 0; i-- {
+		order = append(order, i)
+	}
+	for i := target + 1; i < len(services.ImageVariantSpecs); i++ {
+		order = append(order, i)
+	}
+	for _, i := range order {
+		for _, format := range formats {
+			if variant, ok := byKey[services.ImageVariantSpecs[i].Name+"/"+format]; ok {
+				return variant, true
+			}
+		}
+	}
+	return models.ImageVariant{}, false
+}
@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"social-media-app/backend/auth"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// saveDataTestToken veri tasarrufu ayarı verilen değerde olan bir kullanıcı oluşturup token'ını döndürür
+func saveDataTestToken(t *testing.T, username string, saveDataMode bool) string {
+	t.Helper()
+	user := createTestUser(t, username)
+	settings := models.AppSettings{UserID: user.ID, SaveDataMode: saveDataMode}
+	if err := database.DB.Create(&settings).Error; err != nil {
+		t.Fatalf("Uygulama ayarları oluşturulamadı: %v", err)
+	}
+	token, err := auth.GenerateToken(user.ID)
+	if err != nil {
+		t.Fatalf("Token üretilemedi: %v", err)
+	}
+	return "Bearer " + token
+}
+
+func TestImageSaveDataRequestedPrecedence(t *testing.T) {
+	setupTestDatabase(t)
+	saverToken := saveDataTestToken(t, "tasarrufcu", true)
+	regularToken := saveDataTestToken(t, "normal", false)
+
+	tests := []struct {
+		name        string
+		header      string
+		query       string
+		token       string
+		wantSave    bool
+		wantPerUser bool
+	}{
+		{"tercih yok", "", "", "", false, false},
+		{"Save-Data başlığı", "on", "", "", true, false},
+		{"başlık sorgudan önce gelir", "on", "saveData=false", "", true, false},
+		{"başlık kullanıcı ayarından önce gelir", "off", "", saverToken, false, false},
+		{"sorgu parametresi", "", "saveData=1", "", true, false},
+		{"sorgu kullanıcı ayarından önce gelir", "", "saveData=false", saverToken, false, false},
+		{"geçersiz sorgu yok sayılır", "", "saveData=belki", saverToken, true, true},
+		{"kullanıcı ayarı açık", "", "", saverToken, true, true},
+		{"kullanıcı ayarı kapalı", "", "", regularToken, false, true},
+		{"geçersiz token", "", "", "Bearer bozuk", false, false},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodGet, "/uploads/images/a.jpg?"+tt.query, nil)
+			if tt.header != "" {
+				c.Request.Header.Set("Save-Data", tt.header)
+			}
+			if tt.token != "" {
+				c.Request.Header.Set("Authorization", tt.token)
+			}
+
+			saveData, perUser := imageSaveDataRequested(c)
+			if saveData != tt.wantSave || perUser != tt.wantPerUser {
+				t.Errorf("imageSaveDataRequested = (%v, %v), beklenen (%v, %v)", saveData, perUser, tt.wantSave, tt.wantPerUser)
+			}
+		})
+	}
+}
+
+func TestServeImageUsesSaveDataSetting(t *testing.T) {
+	setupTestDatabase(t)
+	router := newMediaTestRouter(t, "images/a.jpg", "original")
+
+	ctx := context.Background()
+	var variants []models.ImageVariant
+	for _, spec := range services.ImageVariantSpecs {
+		key := "images/a_" + spec.Name + ".jpg"
+		if _, err := blobStore.Put(ctx, key, strings.NewReader(spec.Name), int64(len(spec.Name)), "image/jpeg"); err != nil {
+			t.Fatalf("Varyant yazılamadı: %v", err)
+		}
+		variants = append(variants, models.ImageVariant{
+			SourceKey:  "images/a.jpg",
+			Variant:    spec.Name,
+			Format:     services.ImageFormatJPEG,
+			Width:      spec.MaxWidth,
+			StorageKey: key,
+			CreatedAt:  time.Now(),
+		})
+	}
+	if err := database.DB.Create(&variants).Error; err != nil {
+		t.Fatalf("Varyant kayıtları oluşturulamadı: %v", err)
+	}
+	saverToken := saveDataTestToken(t, "tasarrufcu", true)
+
+	serve := func(token string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/uploads/images/a.jpg", nil)
+		if token != "" {
+			request.Header.Set("Authorization", token)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	anonymous := serve("")
+	if anonymous.Body.String() != services.ImageVariantFull {
+		t.Errorf("anonim istekte %q servis edildi, beklenen %q", anonymous.Body.String(), services.ImageVariantFull)
+	}
+	if strings.HasPrefix(anonymous.Header().Get("Cache-Control"), "private") {
+		t.Errorf("anonim yanıt özel önbellekli: %q", anonymous.Header().Get("Cache-Control"))
+	}
+
+	saver := serve(saverToken)
+	if saver.Body.String() != services.ImageVariantFeed {
+		t.Errorf("veri tasarrufu açık kullanıcıya %q servis edildi, beklenen %q", saver.Body.String(), services.ImageVariantFeed)
+	}
+	if got := saver.Header().Get("Cache-Control"); got != personalizedCacheControl {
+		t.Errorf("Cache-Control %q, beklenen %q", got, personalizedCacheControl)
+	}
+	if got := saver.Header().Get("Vary"); !strings.Contains(got, "Authorization") {
+		t.Errorf("Vary %q, Authorization içermeli", got)
+	}
+}
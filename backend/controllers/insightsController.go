@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// İçgörü sorgu varsayılanları
+const (
+	defaultInsightsDays   = 30
+	maxInsightsDays       = 365
+	maxImpressionsPerCall = 50
+)
+
+// InsightTotals içgörü metriklerinin dönem toplamı
+type InsightTotals struct {
+	Impressions int `json:"impressions"`
+	Reach       int `json:"reach"` // Günlük tekil erişimlerin toplamı
+	Likes       int `json:"likes"`
+	Saves       int `json:"saves"`
+	Comments    int `json:"comments"`
+	Shares      int `json:"shares"`
+}
+
+// insightsSince ?days parametresine göre dönemin ilk gününü döndürür (varsayılan 30, en fazla 365 gün)
+func insightsSince(c *gin.Context) (string, int) {
+	days := defaultInsightsDays
+	if value, err := strconv.Atoi(c.Query("days")); err == nil && value > 0 {
+		days = value
+	}
+	if days > maxInsightsDays {
+		days = maxInsightsDays
+	}
+	_, since := insightDay(time.Now().AddDate(0, 0, -(days - 1)))
+	return since, days
+}
+
+// RecordPostImpressions - İstemcinin ekranda gösterdiği gönderileri gösterim olarak kaydeder
+func RecordPostImpressions(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		PostIDs []uint `json:"postIds" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz gösterim verisi: " + err.Error()})
+		return
+	}
+	if len(request.PostIDs) > maxImpressionsPerCall {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Tek istekte en fazla " + strconv.Itoa(maxImpressionsPerCall) + " gösterim kaydedilebilir",
+		})
+		return
+	}
+
+	// Sadece kullanıcının görebildiği gönderiler kaydedilir
+	var posts []models.Post
+	if len(request.PostIDs) > 0 {
+		visibilitySQL, visibilityArgs := contentVisibilityClause("posts", userID)
+		if err := database.DB.Select("id, user_id").Where("id IN ?", request.PostIDs).
+			Where(visibilitySQL, visibilityArgs...).Find(&posts).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Gösterimler kaydedilemedi"})
+			return
+		}
+	}
+	recordPostImpressions(userID, posts...)
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Gösterimler kaydedildi", Data: gin.H{"recorded": len(posts)}})
+}
+
+// GetInsightsOverview - Hesabın takipçi gelişimi, profil ziyaretleri ve tüm içeriklerin günlük toplamları
+func GetInsightsOverview(c *gin.Context) {
+	userID := c.GetUint("userID")
+	since, days := insightsSince(c)
+
+	var account []models.DailyAccountInsight
+	if err := database.DB.Where("user_id = ? AND day >= ?", userID, since).Order("day").Find(&account).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "İçgörüler alınamadı"})
+		return
+	}
+
+	var content []struct {
+		Day string `json:"day"`
+		InsightTotals
+	}
+	if err := database.DB.Model(&models.DailyContentInsight{}).
+		Select(`day, SUM(impressions) AS impressions, SUM(reach) AS reach, SUM(likes) AS likes,
+			SUM(saves) AS saves, SUM(comments) AS comments, SUM(shares) AS shares`).
+		Where("owner_id = ? AND day >= ?", userID, since).Group("day").Order("day").Scan(&content).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "İçgörüler alınamadı"})
+		return
+	}
+
+	var totals InsightTotals
+	newFollowers, profileVisits := 0, 0
+	for _, row := range content {
+		totals.Impressions += row.Impressions
+		totals.Reach += row.Reach
+		totals.Likes += row.Likes
+		totals.Saves += row.Saves
+		totals.Comments += row.Comments
+		totals.Shares += row.Shares
+	}
+	for _, row := range account {
+		newFollowers += row.NewFollowers
+		profileVisits += row.ProfileVisits
+	}
+
+	var followerCount int64
+	database.DB.Model(&models.Follow{}).Where("following_id = ?", userID).Count(&followerCount)
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "İçgörüler getirildi",
+		Data: gin.H{
+			"days":          days,
+			"since":         since,
+			"followerCount": followerCount,
+			"newFollowers":  newFollowers,
+			"profileVisits": profileVisits,
+			"totals":        totals,
+			"account":       account, // Günlük takipçi sayısı, yeni takipçi ve profil ziyareti
+			"content":       content, // Tüm gönderi ve reellerin günlük toplamları
+		},
+	})
+}
+
+// GetContentInsights - Kullanıcının gönderi ve reellerinin dönem toplamları (?type=post|reel)
+func GetContentInsights(c *gin.Context) {
+	userID := c.GetUint("userID")
+	since, days := insightsSince(c)
+
+	query := database.DB.Model(&models.DailyContentInsight{}).
+		Select(`content_type, content_id, SUM(impressions) AS impressions, SUM(reach) AS reach, SUM(likes) AS likes,
+			SUM(saves) AS saves, SUM(comments) AS comments, SUM(shares) AS shares`).
+		Where("owner_id = ? AND day >= ?", userID, since)
+	switch contentType := c.Query("type"); contentType {
+	case "":
+	case models.ContentTypePost, models.ContentTypeReel:
+		query = query.Where("content_type = ?", contentType)
+	default:
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz içerik türü: post veya reel olmalı"})
+		return
+	}
+
+	var items []struct {
+		ContentType string `json:"contentType"`
+		ContentID   uint   `json:"contentId"`
+		InsightTotals
+	}
+	if err := query.Group("content_type, content_id").
+		Order("impressions DESC, likes DESC, content_id DESC").Limit(100).Scan(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "İçerik içgörüleri alınamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "İçerik içgörüleri getirildi",
+		Data:    gin.H{"days": days, "since": since, "items": items},
+	})
+}
+
+// GetPostInsights - Gönderinin günlük içgörüleri (sadece sahibi)
+func GetPostInsights(c *gin.Context) {
+	var post models.Post
+	if err := database.DB.Select("id, user_id").First(&post, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Gönderi bulunamadı"})
+		return
+	}
+	respondContentInsights(c, models.ContentTypePost, post.ID, post.UserID)
+}
+
+// GetReelInsights - Reelin günlük içgörüleri ve izlenme istatistikleri (sadece sahibi)
+func GetReelInsights(c *gin.Context) {
+	var reel models.Reels
+	if err := database.DB.First(&reel, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Reel bulunamadı"})
+		return
+	}
+	respondContentInsights(c, models.ContentTypeReel, reel.ID, reel.UserID, reelViewStats(reel))
+}
+
+// respondContentInsights bir içeriğin günlük içgörü serisini ve dönem toplamını döndürür
+func respondContentInsights(c *gin.Context, contentType string, contentID, ownerID uint, extra ...gin.H) {
+	if ownerID != c.GetUint("userID") {
+		c.JSON(http.StatusForbidden, Response{Success: false, Message: "Sadece kendi içeriklerinizin içgörülerini görebilirsiniz"})
+		return
+	}
+	since, days := insightsSince(c)
+
+	var daily []models.DailyContentInsight
+	if err := database.DB.Where("content_type = ? AND content_id = ? AND day >= ?", contentType, contentID, since).
+		Order("day").Find(&daily).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "İçgörüler alınamadı"})
+		return
+	}
+
+	var totals InsightTotals
+	for _, row := range daily {
+		totals.Impressions += row.Impressions
+		totals.Reach += row.Reach
+		totals.Likes += row.Likes
+		totals.Saves += row.Saves
+		totals.Comments += row.Comments
+		totals.Shares += row.Shares
+	}
+
+	data := gin.H{
+		"contentType": contentType,
+		"contentId":   contentID,
+		"days":        days,
+		"since":       since,
+		"totals":      totals,
+		"daily":       daily,
+	}
+	for _, fields := range extra {
+		for key, value := range fields {
+			data[key] = value
+		}
+	}
+	c.JSON(http.StatusOK, Response{Success: true, Message: "İçgörüler getirildi", Data: data})
+}
+
+// GetAudienceInsights - Hesabın en son özetlenen kitle dağılımı (konum, ilgi alanları, takipçi/takipçi olmayan erişim)
+func GetAudienceInsights(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var latest string
+	database.DB.Model(&models.DailyAudienceInsight{}).Where("user_id = ?", userID).
+		Select("COALESCE(MAX(day), '')").Scan(&latest)
+
+	var rows []models.DailyAudienceInsight
+	if latest != "" {
+		if err := database.DB.Where("user_id = ? AND day = ?", userID, latest).
+			Order("dimension, count DESC, value").Find(&rows).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Kitle içgörüleri alınamadı"})
+			return
+		}
+	}
+
+	breakdown := gin.H{
+		models.AudienceDimensionLocation:     []gin.H{},
+		models.AudienceDimensionInterest:     []gin.H{},
+		models.AudienceDimensionRelationship: []gin.H{},
+	}
+	for _, row := range rows {
+		values, _ := breakdown[row.Dimension].([]gin.H)
+		breakdown[row.Dimension] = append(values, gin.H{"value": row.Value, "count": row.Count})
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Kitle içgörüleri getirildi",
+		Data:    gin.H{"day": latest, "breakdown": breakdown},
+	})
+}
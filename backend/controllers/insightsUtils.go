@@ -0,0 +1,374 @@
+package controllers
+
+import (
+	"fmt"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// İçgörü işi varsayılanları (INSIGHTS_INTERVAL_HOURS ve INSIGHTS_RAW_RETENTION_DAYS ile değiştirilebilir)
+const (
+	defaultInsightsInterval         = time.Hour
+	defaultInsightsRawRetentionDays = 90
+	insightsDayLayout               = "2006-01-02"
+	maxAudienceBreakdownValues      = 10 // Boyut başına saklanan en fazla değer
+)
+
+// insightContentSources içerik metriklerinin ham tablolardan günlük olarak nasıl sayıldığını tanımlar.
+// Her sorgu content_id ve value sütunlarını döndürür; iki parametre günün başı ve sonudur.
+var insightContentSources = []struct {
+	contentType string
+	metric      string
+	query       string
+}{
+	{models.ContentTypePost, "impressions", `SELECT content_id, SUM(count) AS value FROM content_impressions
+		WHERE content_type = 'post' AND created_at >= ? AND created_at < ? GROUP BY content_id`},
+	{models.ContentTypePost, "reach", `SELECT content_id, COUNT(DISTINCT viewer_id) AS value FROM content_impressions
+		WHERE content_type = 'post' AND created_at >= ? AND created_at < ? GROUP BY content_id`},
+	{models.ContentTypePost, "likes", `SELECT post_id AS content_id, COUNT(*) AS value FROM likes
+		WHERE created_at >= ? AND created_at < ? GROUP BY post_id`},
+	{models.ContentTypePost, "saves", `SELECT post_id AS content_id, COUNT(*) AS value FROM saved_posts
+		WHERE created_at >= ? AND created_at < ? GROUP BY post_id`},
+	{models.ContentTypePost, "comments", `SELECT post_id AS content_id, COUNT(*) AS value FROM comments
+		WHERE post_id IS NOT NULL AND deleted_at IS NULL AND created_at >= ? AND created_at < ? GROUP BY post_id`},
+	{models.ContentTypePost, "shares", `SELECT post_id AS content_id, COUNT(*) AS value FROM post_shares
+		WHERE created_at >= ? AND created_at < ? GROUP BY post_id`},
+	{models.ContentTypeReel, "impressions", `SELECT reel_id AS content_id, COUNT(*) AS value FROM reel_views
+		WHERE started_at >= ? AND started_at < ? GROUP BY reel_id`},
+	{models.ContentTypeReel, "reach", `SELECT reel_id AS content_id, COUNT(DISTINCT viewer_id) AS value FROM reel_views
+		WHERE started_at >= ? AND started_at < ? GROUP BY reel_id`},
+	{models.ContentTypeReel, "likes", `SELECT reel_id AS content_id, COUNT(*) AS value FROM reel_likes
+		WHERE created_at >= ? AND created_at < ? GROUP BY reel_id`},
+	{models.ContentTypeReel, "saves", `SELECT reel_id AS content_id, COUNT(*) AS value FROM saved_reels
+		WHERE created_at >= ? AND created_at < ? GROUP BY reel_id`},
+	{models.ContentTypeReel, "comments", `SELECT reel_id AS content_id, COUNT(*) AS value FROM comments
+		WHERE reel_id IS NOT NULL AND deleted_at IS NULL AND created_at >= ? AND created_at < ? GROUP BY reel_id`},
+	{models.ContentTypeReel, "shares", `SELECT reel_id AS content_id, COUNT(*) AS value FROM reel_shares
+		WHERE created_at >= ? AND created_at < ? GROUP BY reel_id`},
+}
+
+// InsightsReport bir günün içgörü özetleme sonucu
+type InsightsReport struct {
+	Day          string `json:"day"`
+	ContentRows  int    `json:"contentRows"`
+	AccountRows  int    `json:"accountRows"`
+	AudienceRows int    `json:"audienceRows"`
+}
+
+// insightDay verilen zamanın gün başlangıcını ve gün anahtarını döndürür
+func insightDay(t time.Time) (time.Time, string) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return start, start.Format(insightsDayLayout)
+}
+
+// recordPostImpressions gönderilerin kullanıcıya gösterildiğini kaydeder; sahibinin kendi gönderileri
+// sayılmaz. Aynı gün tekrarlanan gösterimler kullanıcının o günkü satırındaki sayacı artırır; böylece
+// gösterim sayısı her gösterimi, erişim ise tekil kullanıcıları sayar.
+func recordPostImpressions(viewerID uint, posts ...models.Post) {
+	now := time.Now()
+	_, day := insightDay(now)
+
+	var impressions []models.ContentImpression
+	for _, post := range posts {
+		if viewerID == 0 || post.UserID == viewerID {
+			continue
+		}
+		impressions = append(impressions, models.ContentImpression{
+			ContentType: models.ContentTypePost,
+			ContentID:   post.ID,
+			ViewerID:    viewerID,
+			Day:         day,
+			Count:       1,
+			CreatedAt:   now,
+		})
+	}
+	if len(impressions) == 0 {
+		return
+	}
+	if err := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "content_type"}, {Name: "content_id"}, {Name: "viewer_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("content_impressions.count + 1")}),
+	}).Create(&impressions).Error; err != nil {
+		fmt.Printf("Gönderi gösterimleri kaydedilemedi: %v\n", err)
+	}
+}
+
+// recordProfileVisit profil ziyaretini kaydeder; kullanıcının kendi profili sayılmaz
+func recordProfileVisit(visitorID, profileUserID uint) {
+	if visitorID == 0 || visitorID == profileUserID {
+		return
+	}
+	visit := models.ProfileVisit{ProfileUserID: profileUserID, VisitorID: visitorID, CreatedAt: time.Now()}
+	if err := database.DB.Create(&visit).Error; err != nil {
+		fmt.Printf("Profil ziyareti kaydedilemedi: %v\n", err)
+	}
+}
+
+// StartInsightsAggregation günlük içgörü özetlerini zamanlanmış görev olarak günceller. İlk çalışma
+// açılışta yapılır; her çalışmada bugün ve dün yeniden özetlenir, böylece gün bittikten sonra gelen
+// olaylar da dünün özetine girer.
+func StartInsightsAggregation() {
+	interval := durationFromEnv("INSIGHTS_INTERVAL_HOURS", defaultInsightsInterval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			now := time.Now()
+			for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+				if _, err := RunInsightsAggregation(day); err != nil {
+					fmt.Printf("İçgörü özetleme hatası: %v\n", err)
+				}
+			}
+			if purged, err := PurgeRawInsightEvents(); err != nil {
+				fmt.Printf("Ham içgörü olayları temizlenemedi: %v\n", err)
+			} else if purged > 0 {
+				fmt.Printf("Ham içgörü olayları temizlendi: %d kayıt\n", purged)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// PurgeRawInsightEvents saklama süresini (INSIGHTS_RAW_RETENTION_DAYS) aşan ham gösterim ve ziyaret
+// kayıtlarını siler; bu günlerin özetleri korunur
+func PurgeRawInsightEvents() (int64, error) {
+	cutoff, _ := insightDay(time.Now().AddDate(0, 0, -intFromEnv("INSIGHTS_RAW_RETENTION_DAYS", defaultInsightsRawRetentionDays)))
+
+	impressions := database.DB.Where("created_at < ?", cutoff).Delete(&models.ContentImpression{})
+	if impressions.Error != nil {
+		return 0, impressions.Error
+	}
+	visits := database.DB.Where("created_at < ?", cutoff).Delete(&models.ProfileVisit{})
+	if visits.Error != nil {
+		return impressions.RowsAffected, visits.Error
+	}
+	return impressions.RowsAffected + visits.RowsAffected, nil
+}
+
+// RunInsightsAggregation verilen günün içerik, hesap ve kitle özetlerini ham tablolardan yeniden
+// hesaplar. Günün önceki özetleri tek transaction içinde yenileriyle değiştirilir.
+func RunInsightsAggregation(day time.Time) (InsightsReport, error) {
+	start, key := insightDay(day)
+	end := start.AddDate(0, 0, 1)
+	report := InsightsReport{Day: key}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		contentRows, err := aggregateContentInsights(tx, key, start, end)
+		if err != nil {
+			return err
+		}
+		accountRows, err := aggregateAccountInsights(tx, key, start, end)
+		if err != nil {
+			return err
+		}
+		audienceRows, err := aggregateAudienceInsights(tx, key, start, end)
+		if err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{&models.DailyContentInsight{}, &models.DailyAccountInsight{}, &models.DailyAudienceInsight{}} {
+			if err := tx.Where("day = ?", key).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if len(contentRows) > 0 {
+			if err := tx.CreateInBatches(contentRows, 200).Error; err != nil {
+				return err
+			}
+		}
+		if len(accountRows) > 0 {
+			if err := tx.CreateInBatches(accountRows, 200).Error; err != nil {
+				return err
+			}
+		}
+		if len(audienceRows) > 0 {
+			if err := tx.CreateInBatches(audienceRows, 200).Error; err != nil {
+				return err
+			}
+		}
+
+		report.ContentRows, report.AccountRows, report.AudienceRows = len(contentRows), len(accountRows), len(audienceRows)
+		return nil
+	})
+	return report, err
+}
+
+// aggregateContentInsights gönderi ve reellerin günlük metriklerini hesaplar. Silinmiş içerikler atlanır.
+func aggregateContentInsights(tx *gorm.DB, key string, start, end time.Time) ([]models.DailyContentInsight, error) {
+	type contentKey struct {
+		contentType string
+		contentID   uint
+	}
+	rows := make(map[contentKey]*models.DailyContentInsight)
+	ids := map[string][]uint{}
+
+	for _, source := range insightContentSources {
+		var values []struct {
+			ContentID uint
+			Value     int
+		}
+		if err := tx.Raw(source.query, start, end).Scan(&values).Error; err != nil {
+			return nil, fmt.Errorf("%s %s sayılamadı: %w", source.contentType, source.metric, err)
+		}
+		for _, value := range values {
+			k := contentKey{source.contentType, value.ContentID}
+			row, ok := rows[k]
+			if !ok {
+				row = &models.DailyContentInsight{ContentType: source.contentType, ContentID: value.ContentID, Day: key}
+				rows[k] = row
+				ids[source.contentType] = append(ids[source.contentType], value.ContentID)
+			}
+			switch source.metric {
+			case "impressions":
+				row.Impressions = value.Value
+			case "reach":
+				row.Reach = value.Value
+			case "likes":
+				row.Likes = value.Value
+			case "saves":
+				row.Saves = value.Value
+			case "comments":
+				row.Comments = value.Value
+			case "shares":
+				row.Shares = value.Value
+			}
+		}
+	}
+
+	var result []models.DailyContentInsight
+	for contentType, table := range map[string]string{models.ContentTypePost: "posts", models.ContentTypeReel: "reels"} {
+		if len(ids[contentType]) == 0 {
+			continue
+		}
+		var owners []struct {
+			ID     uint
+			UserID uint
+		}
+		if err := tx.Table(table).Select("id, user_id").
+			Where("id IN ? AND deleted_at IS NULL", ids[contentType]).Scan(&owners).Error; err != nil {
+			return nil, err
+		}
+		for _, owner := range owners {
+			row := rows[contentKey{contentType, owner.ID}]
+			row.OwnerID = owner.UserID
+			result = append(result, *row)
+		}
+	}
+	return result, nil
+}
+
+// aggregateAccountInsights takipçisi veya profil ziyareti olan hesapların günlük özetini hesaplar.
+// Takipten çıkmalar silindiği için takipçi sayısı gün sonunda var olan takip kayıtlarından hesaplanır.
+func aggregateAccountInsights(tx *gorm.DB, key string, start, end time.Time) ([]models.DailyAccountInsight, error) {
+	rows := make(map[uint]*models.DailyAccountInsight)
+	row := func(userID uint) *models.DailyAccountInsight {
+		if rows[userID] == nil {
+			rows[userID] = &models.DailyAccountInsight{UserID: userID, Day: key}
+		}
+		return rows[userID]
+	}
+
+	var values []struct {
+		UserID uint
+		Value  int
+		Unique int
+	}
+	if err := tx.Raw(`SELECT following_id AS user_id, COUNT(*) AS value FROM follows
+		WHERE created_at < ? GROUP BY following_id`, end).Scan(&values).Error; err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		row(value.UserID).FollowerCount = value.Value
+	}
+
+	values = nil
+	if err := tx.Raw(`SELECT following_id AS user_id, COUNT(*) AS value FROM follows
+		WHERE created_at >= ? AND created_at < ? GROUP BY following_id`, start, end).Scan(&values).Error; err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		row(value.UserID).NewFollowers = value.Value
+	}
+
+	values = nil
+	if err := tx.Raw(`SELECT profile_user_id AS user_id, COUNT(*) AS value, COUNT(DISTINCT visitor_id) AS "unique"
+		FROM profile_visits WHERE created_at >= ? AND created_at < ? GROUP BY profile_user_id`, start, end).Scan(&values).Error; err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		row(value.UserID).ProfileVisits = value.Value
+		row(value.UserID).UniqueVisits = value.Unique
+	}
+
+	result := make([]models.DailyAccountInsight, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, *r)
+	}
+	return result, nil
+}
+
+// aggregateAudienceInsights hesapların kitle dağılımını mevcut tablolardan hesaplar: takipçilerin konumu,
+// takipçilerin ilgi alanları (kullanıcı etiketleri) ve gün içinde erişilen kullanıcıların takipçi olup olmadığı
+func aggregateAudienceInsights(tx *gorm.DB, key string, start, end time.Time) ([]models.DailyAudienceInsight, error) {
+	sources := []struct {
+		dimension string
+		query     string
+		args      []interface{}
+	}{
+		{models.AudienceDimensionLocation, `SELECT follows.following_id AS user_id, users.location AS value, COUNT(*) AS count
+			FROM follows JOIN users ON users.id = follows.follower_id
+			WHERE follows.created_at < ? AND users.location <> '' AND users.deleted_at IS NULL
+			GROUP BY follows.following_id, users.location`, []interface{}{end}},
+		{models.AudienceDimensionInterest, `SELECT follows.following_id AS user_id, user_tags.tag_name AS value,
+			COUNT(DISTINCT follows.follower_id) AS count
+			FROM follows JOIN user_tags ON user_tags.user_id = follows.follower_id
+			WHERE follows.created_at < ?
+			GROUP BY follows.following_id, user_tags.tag_name`, []interface{}{end}},
+		{models.AudienceDimensionRelationship, `SELECT reached.owner_id AS user_id,
+			CASE WHEN EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = reached.viewer_id AND follows.following_id = reached.owner_id)
+				THEN 'follower' ELSE 'non_follower' END AS value,
+			COUNT(DISTINCT reached.viewer_id) AS count
+			FROM (
+				SELECT posts.user_id AS owner_id, content_impressions.viewer_id AS viewer_id
+				FROM content_impressions JOIN posts ON posts.id = content_impressions.content_id
+				WHERE content_impressions.content_type = ? AND content_impressions.created_at >= ? AND content_impressions.created_at < ?
+				UNION ALL
+				SELECT reels.user_id AS owner_id, reel_views.viewer_id AS viewer_id
+				FROM reel_views JOIN reels ON reels.id = reel_views.reel_id
+				WHERE reel_views.started_at >= ? AND reel_views.started_at < ?
+			) reached
+			GROUP BY reached.owner_id, value`, []interface{}{models.ContentTypePost, start, end, start, end}},
+	}
+
+	var result []models.DailyAudienceInsight
+	for _, source := range sources {
+		var values []struct {
+			UserID uint
+			Value  string
+			Count  int
+		}
+		if err := tx.Raw(source.query+" ORDER BY user_id, count DESC, value", source.args...).Scan(&values).Error; err != nil {
+			return nil, fmt.Errorf("%s dağılımı hesaplanamadı: %w", source.dimension, err)
+		}
+
+		perUser := make(map[uint]int)
+		for _, value := range values {
+			if perUser[value.UserID] >= maxAudienceBreakdownValues {
+				continue
+			}
+			perUser[value.UserID]++
+			result = append(result, models.DailyAudienceInsight{
+				UserID:    value.UserID,
+				Day:       key,
+				Dimension: source.dimension,
+				Value:     value.Value,
+				Count:     value.Count,
+			})
+		}
+	}
+	return result, nil
+}
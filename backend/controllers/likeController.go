@@ -55,8 +55,13 @@ func LikePost(c *gin.Context) {
 		PostID: post.ID,
 	}
 
-	// Veritabanına kaydet
-	if err := database.DB.Create(&like).Error; err != nil {
+	// Beğeniyi ve beğeni sayacını aynı transaction içinde kaydet
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&like).Error; err != nil {
+			return err
+		}
+		return adjustCounter(tx, &models.Post{}, likeCountColumn, 1, post.ID)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Beğeni kaydedilirken hata oluştu"})
 		return
 	}
@@ -226,9 +231,6 @@ func LikePost(c *gin.Context) {
 		}
 	}
 
-	// Beğeni sayısını güncelle
-	database.DB.Model(&post).Update("likes_count", gorm.Expr("likes_count + ?", 1))
-
 	c.JSON(http.StatusOK, gin.H{"message": "Gönderi beğenildi", "like": like})
 }
 
@@ -281,5 +283,5 @@ func PrintUserTagStats(userID uint) {
 		fmt.Println("Auxiliary etiket bulunamadı.")
 	}
 
-	fmt.Println("\n***************************************************\n")
+	fmt.Print("\n***************************************************\n\n")
 }
@@ -0,0 +1,273 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"social-media-app/backend/services"
+	"social-media-app/backend/storage"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Medya önbellek politikaları
+const (
+	// İçerik adresli dosyalar (adında UUID veya SHA-256 özeti olanlar) hiç değişmez
+	immutableCacheControl = "public, max-age=31536000, immutable"
+	// Diğer dosyalar her istekte ETag/Last-Modified ile doğrulanır
+	revalidateCacheControl = "public, no-cache"
+	// Varyantları henüz üretilmemiş görsellerin orijinali kısa süre önbellekte tutulur; böylece
+	// varyantlar hazır olduğunda istemciler aynı adresten küçük/modern formatlı sürümü alır
+	pendingVariantsCacheControl = "public, max-age=60"
+	// İmzalı bağlantılar paylaşılan önbelleklerde tutulmaz
+	privateCacheControl = "private, no-cache"
+	// Oturumdaki kullanıcının ayarına göre seçilen varyantlar sadece tarayıcı önbelleğinde tutulur
+	personalizedCacheControl = "private, max-age=3600"
+)
+
+// Anahtar bileşeni: harf, rakam, nokta, alt çizgi ve tire; nokta ile başlayamaz
+var mediaKeySegmentPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]{0,199}$`)
+
+// İçerik adresli dosya adlarını tanır
+var contentAddressedPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-f]{64}`)
+
+// En fazla anahtar derinliği (ör. videos/hls/12/master.m3u8)
+const maxMediaKeyDepth = 6
+
+// mediaContentTypes http.DetectContentType'ın tanımadığı formatlar için uzantıya göre Content-Type değerleri.
+// Uzantı sadece dosyanın ilk baytları bu formatın imzasıyla eşleşirse kullanılır (bkz. mediaSignatureMatches).
+var mediaContentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".avif": "image/avif",
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+	".mov":  "video/quicktime",
+	".avi":  "video/x-msvideo",
+	".m3u8": "application/vnd.apple.mpegurl",
+	".ts":   "video/mp2t",
+}
+
+// mediaNotFoundMessages anahtar önekine göre 404 mesajları
+var mediaNotFoundMessages = map[string]string{
+	imagesKeyPrefix:     "Görsel bulunamadı",
+	videosKeyPrefix:     "Video bulunamadı",
+	thumbnailsKeyPrefix: "Thumbnail bulunamadı",
+}
+
+// validateMediaKey tüm medya istekleri için tek dosya adı doğrulayıcısı.
+// Her yol bileşeni güvenli karakterlerden oluşmalı; "..", boş bileşen ve gizli dosyalar reddedilir.
+func validateMediaKey(key string) (string, bool) {
+	segments := strings.Split(key, "/")
+	if len(segments) == 0 || len(segments) > maxMediaKeyDepth {
+		return "", false
+	}
+	for _, segment := range segments {
+		if !mediaKeySegmentPattern.MatchString(segment) {
+			return "", false
+		}
+	}
+	return key, true
+}
+
+// mediaCacheControl anahtarın içerik adresli olup olmamasına göre Cache-Control değerini döndürür
+func mediaCacheControl(key string) string {
+	if contentAddressedPattern.MatchString(path.Base(key)) {
+		return immutableCacheControl
+	}
+	return revalidateCacheControl
+}
+
+// mediaContentType dosyanın ilk baytlarından Content-Type belirler. İçerik görsel/video olarak
+// tanınmazsa uzantıdaki tip ancak imza eşleşirse kullanılır; aksi halde dosya ham veri olarak gönderilir.
+func mediaContentType(key string, head []byte) string {
+	sniffed := http.DetectContentType(head)
+	if strings.HasPrefix(sniffed, "image/") || strings.HasPrefix(sniffed, "video/") || strings.HasPrefix(sniffed, "audio/") {
+		return sniffed
+	}
+	if contentType, ok := mediaContentTypes[strings.ToLower(path.Ext(key))]; ok && mediaSignatureMatches(contentType, head) {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// mediaSignatureMatches içeriğin uzantıdaki formatın imzasını taşıyıp taşımadığını kontrol eder
+func mediaSignatureMatches(contentType string, head []byte) bool {
+	switch contentType {
+	case "image/avif":
+		return len(head) >= 12 && string(head[4:8]) == "ftyp" && (string(head[8:12]) == "avif" || string(head[8:12]) == "avis")
+	case "video/mp4", "video/quicktime":
+		if len(head) < 8 {
+			return false
+		}
+		switch string(head[4:8]) {
+		case "ftyp", "moov", "mdat", "wide", "free":
+			return true
+		}
+		return false
+	case "application/vnd.apple.mpegurl":
+		return bytes.HasPrefix(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), []byte("#EXTM3U"))
+	case "video/mp2t":
+		return len(head) > 0 && head[0] == 0x47 // MPEG-TS senkronizasyon baytı
+	}
+	return false
+}
+
+// serveMedia tüm medya yolları için ortak işleyici: anahtarı doğrular, görsellerde uygun varyantı seçer,
+// Range/If-None-Match/If-Modified-Since isteklerini karşılar ve önbellek başlıklarını ayarlar
+func serveMedia(c *gin.Context, key string, cacheControl string) {
+	key, ok := validateMediaKey(key)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Geçersiz dosya adı",
+		})
+		return
+	}
+
+	notFoundMessage := "Dosya bulunamadı"
+	for prefix, message := range mediaNotFoundMessages {
+		if strings.HasPrefix(key, prefix) {
+			notFoundMessage = message
+		}
+	}
+
+	// Görsellerde istemciye uygun varyant servis edilir
+	if strings.HasPrefix(key, imagesKeyPrefix) {
+		saveData, perUser := imageSaveDataRequested(c)
+		c.Header("Vary", "Accept, Save-Data")
+		if variant, found := selectImageVariant(c, key, saveData); found {
+			if perUser {
+				// Varyant kullanıcının veri tasarrufu ayarına bağlı; paylaşılan önbellekler başka kullanıcıya vermemeli
+				c.Header("Vary", "Accept, Save-Data, Authorization")
+				if cacheControl == "" {
+					cacheControl = personalizedCacheControl
+				}
+			}
+			serveStoredObject(c, variant.StorageKey, services.ImageContentType(variant.Format), cacheControl, notFoundMessage)
+			return
+		}
+		// GIF'ler dışındaki görsellerin varyantları yükleme sonrası arka planda üretilir
+		if cacheControl == "" && !strings.EqualFold(path.Ext(key), ".gif") {
+			cacheControl = pendingVariantsCacheControl
+		}
+	}
+
+	serveStoredObject(c, key, "", cacheControl, notFoundMessage)
+}
+
+// serveStoredObject depodaki nesneyi yanıt olarak yazar. Okuyucu io.ReadSeeker ise
+// Range ve If-Modified-Since/If-None-Match istekleri http.ServeContent tarafından karşılanır.
+// cacheControl boşsa anahtara göre belirlenir.
+func serveStoredObject(c *gin.Context, key string, contentType string, cacheControl string, notFoundMessage string) {
+	reader, info, err := blobStore.Get(c.Request.Context(), key)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": notFoundMessage})
+		case errors.Is(err, storage.ErrInvalidKey):
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Geçersiz dosya adı"})
+		default:
+			fmt.Printf("Dosya depodan okunamadı: %s - %s\n", key, err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Dosya okunamadı"})
+		}
+		return
+	}
+	defer reader.Close()
+
+	// Content-Type uzantıya veya yükleyenin bildirdiği tipe değil, içeriğin kendisine göre belirlenir
+	var body io.Reader = reader
+	if contentType == "" {
+		head := make([]byte, 512)
+		n, err := io.ReadFull(reader, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			fmt.Printf("Dosya depodan okunamadı: %s - %s\n", key, err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Dosya okunamadı"})
+			return
+		}
+		head = head[:n]
+		contentType = mediaContentType(key, head)
+
+		if seeker, ok := reader.(io.ReadSeeker); ok {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Dosya okunamadı"})
+				return
+			}
+		} else {
+			body = io.MultiReader(bytes.NewReader(head), reader)
+		}
+	}
+	if cacheControl == "" {
+		cacheControl = mediaCacheControl(key)
+	}
+	if contentType != "" {
+		c.Header("Content-Type", contentType)
+	}
+	c.Header("Cache-Control", cacheControl)
+	c.Header("X-Content-Type-Options", "nosniff")
+	if info.ETag != "" {
+		c.Header("ETag", `"`+strings.Trim(info.ETag, `"`)+`"`)
+	}
+
+	if seeker, ok := reader.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime, seeker)
+		return
+	}
+
+	// Seek desteklemeyen depolarda sadece koşullu istekler karşılanır, tüm içerik gönderilir
+	if match := c.GetHeader("If-None-Match"); match != "" && info.ETag != "" && strings.Contains(match, strings.Trim(info.ETag, `"`)) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.DataFromReader(http.StatusOK, info.Size, contentType, body, nil)
+}
+
+// ServeUploadedImage - Yüklenen görselleri servis et
+func ServeUploadedImage(c *gin.Context) {
+	serveMedia(c, imagesKeyPrefix+c.Param("name"), "")
+}
+
+// ServeUploadedVideo - Yüklenen videoları servis et
+func ServeUploadedVideo(c *gin.Context) {
+	serveMedia(c, videosKeyPrefix+c.Param("name"), "")
+}
+
+// ServeUploadedThumbnail - Yüklenen thumbnail dosyalarını servis eder
+func ServeUploadedThumbnail(c *gin.Context) {
+	serveMedia(c, thumbnailsKeyPrefix+c.Param("name"), "")
+}
+
+// ServeStoredUpload - /uploads/... adreslerini (görseller, videolar, HLS dosyaları) servis eder.
+// Sadece herkese açık medya önekleri servis edilir; yarım yükleme parçaları gibi iç nesneler gizlidir.
+func ServeStoredUpload(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if _, public := mediaNotFoundMessages[key[:strings.Index(key, "/")+1]]; !public {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "Dosya bulunamadı"})
+		return
+	}
+	serveMedia(c, key, "")
+}
+
+// ServeSignedFile - Yerel depo için imzalı URL'leri doğrulayarak dosyayı servis eder
+func ServeSignedFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	localStore, ok := blobStore.(*storage.LocalStore)
+	if !ok || !localStore.VerifySignature(key, c.Query("expires"), c.Query("signature")) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": "Geçersiz veya süresi dolmuş bağlantı",
+		})
+		return
+	}
+
+	serveMedia(c, key, privateCacheControl)
+}
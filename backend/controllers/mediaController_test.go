@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newMediaTestRouter geçici dizindeki LocalStore ile /uploads yolunu servis eden bir router kurar
+func newMediaTestRouter(t *testing.T, key string, content string) *gin.Engine {
+	t.Helper()
+
+	store := setupTestBlobStore(t)
+	if _, err := store.Put(context.Background(), key, strings.NewReader(content), int64(len(content)), ""); err != nil {
+		t.Fatalf("Dosya yazılamadı: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/uploads/*key", ServeStoredUpload)
+	return router
+}
+
+// mp4Header 20 baytlık geçerli bir MP4 ftyp kutusu
+const mp4Header = "\x00\x00\x00\x14ftypmp42\x00\x00\x00\x00mp42"
+
+func TestServeStoredUploadRange(t *testing.T) {
+	content := mp4Header
+	router := newMediaTestRouter(t, "videos/clip.mp4", content)
+
+	request := httptest.NewRequest(http.MethodGet, "/uploads/videos/clip.mp4", nil)
+	request.Header.Set("Range", "bytes=0-9")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusPartialContent {
+		t.Fatalf("durum kodu %d, beklenen %d", recorder.Code, http.StatusPartialContent)
+	}
+	if got, want := recorder.Header().Get("Content-Range"), "bytes 0-9/20"; got != want {
+		t.Errorf("Content-Range %q, beklenen %q", got, want)
+	}
+	if got := recorder.Body.String(); got != content[:10] {
+		t.Errorf("gövde %q, beklenen %q", got, content[:10])
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "video/mp4" {
+		t.Errorf("Content-Type %q, beklenen video/mp4", got)
+	}
+}
+
+func TestServeStoredUploadNotModified(t *testing.T) {
+	router := newMediaTestRouter(t, "videos/clip.mp4", mp4Header)
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/uploads/videos/clip.mp4", nil))
+	if first.Code != http.StatusOK {
+		t.Fatalf("ilk istek durum kodu %d, beklenen %d", first.Code, http.StatusOK)
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("yanıtta ETag yok")
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/uploads/videos/clip.mp4", nil)
+	request.Header.Set("If-None-Match", etag)
+	second := httptest.NewRecorder()
+	router.ServeHTTP(second, request)
+
+	if second.Code != http.StatusNotModified {
+		t.Fatalf("durum kodu %d, beklenen %d", second.Code, http.StatusNotModified)
+	}
+	if second.Body.Len() != 0 {
+		t.Errorf("304 yanıtında gövde olmamalı, %d bayt geldi", second.Body.Len())
+	}
+}
+
+func TestServeStoredUploadRejectsPrivatePrefix(t *testing.T) {
+	router := newMediaTestRouter(t, "uploads-tmp/part", "secret")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/uploads/uploads-tmp/part", nil))
+
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("durum kodu %d, beklenen %d", recorder.Code, http.StatusNotFound)
+	}
+}
+
+func TestServeStoredUploadSniffsContentType(t *testing.T) {
+	router := newMediaTestRouter(t, "videos/page.mp4", "<html><script>alert(1)</script></html>")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/uploads/videos/page.mp4", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("durum kodu %d, beklenen %d", recorder.Code, http.StatusOK)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Content-Type %q, beklenen application/octet-stream", got)
+	}
+}
+
+func TestMediaContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		head string
+		want string
+	}{
+		{"mp4", "videos/a.mp4", mp4Header, "video/mp4"},
+		{"uzantısı yanlış png", "images/a.mp4", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", "image/png"},
+		{"avif", "images/a.avif", "\x00\x00\x00\x1cftypavif\x00\x00\x00\x00", "image/avif"},
+		{"hls listesi", "videos/hls/1/master.m3u8", "#EXTM3U\n#EXT-X-VERSION:3\n", "application/vnd.apple.mpegurl"},
+		{"mpeg-ts", "videos/hls/1/seg0.ts", "\x47\x40\x00\x10", "video/mp2t"},
+		{"görsel uzantılı html", "images/a.jpg", "<!DOCTYPE html><html></html>", "application/octet-stream"},
+		{"avif uzantılı metin", "images/a.avif", "not really an image", "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mediaContentType(tt.key, []byte(tt.head)); got != tt.want {
+				t.Errorf("mediaContentType(%q) = %q, beklenen %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Çöp toplama varsayılanları (MEDIA_GC_GRACE_HOURS ve MEDIA_GC_INTERVAL_HOURS ile değiştirilebilir)
+const (
+	defaultMediaGCGracePeriod = 24 * time.Hour
+	defaultMediaGCInterval    = 24 * time.Hour
+)
+
+// mediaReferenceSources medya URL'si tutan tablolar. Her sorgu ref_id, url ve owner_id sütunlarını döndürür.
+var mediaReferenceSources = []struct {
+	refType string
+	query   string
+}{
+	{models.MediaRefPost, `SELECT post_images.post_id AS ref_id, post_images.url AS url, posts.user_id AS owner_id
+		FROM post_images JOIN posts ON posts.id = post_images.post_id WHERE posts.deleted_at IS NULL`},
+	{models.MediaRefPostRevision, `SELECT post_revision_images.revision_id AS ref_id, post_revision_images.url AS url,
+		posts.user_id AS owner_id FROM post_revision_images
+		JOIN post_revisions ON post_revisions.id = post_revision_images.revision_id
+		JOIN posts ON posts.id = post_revisions.post_id WHERE posts.deleted_at IS NULL`},
+	{models.MediaRefReel, `SELECT id AS ref_id, video_url AS url, user_id AS owner_id FROM reels WHERE deleted_at IS NULL`},
+	{models.MediaRefReel, `SELECT id AS ref_id, thumbnail_url AS url, user_id AS owner_id FROM reels
+		WHERE deleted_at IS NULL AND thumbnail_url <> ''`},
+	{models.MediaRefMessage, `SELECT id AS ref_id, media_url AS url, sender_id AS owner_id FROM messages WHERE media_url <> ''`},
+	{models.MediaRefAvatar, `SELECT id AS ref_id, profile_image AS url, id AS owner_id FROM users
+		WHERE deleted_at IS NULL AND profile_image <> ''`},
+	{models.MediaRefStory, `SELECT id AS ref_id, media_url AS url, user_id AS owner_id FROM stories`},
+}
+
+// MediaGCReport bir çöp toplama çalışmasının özeti
+type MediaGCReport struct {
+	DryRun            bool     `json:"dryRun"`
+	Registered        int      `json:"registered"`        // Kayıt altına alınan eski dosyalar
+	ReferencesAdded   int      `json:"referencesAdded"`   // Tablolardan tamamlanan referanslar
+	ReferencesRemoved int      `json:"referencesRemoved"` // Artık var olmayan içeriklere ait referanslar
+	Deleted           int      `json:"deleted"`
+	DeletedBytes      int64    `json:"deletedBytes"`
+	DeletedKeys       []string `json:"deletedKeys"` // Kuru çalıştırmada silinecek dosyalar
+	DeletedHLSReels   int      `json:"deletedHlsReels"`
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	if hours, err := strconv.Atoi(os.Getenv(key)); err == nil && hours > 0 {
+		return time.Duration(hours) * time.Hour
+	}
+	return fallback
+}
+
+// StartMediaGarbageCollector çöp toplayıcıyı zamanlanmış görev olarak başlatır
+func StartMediaGarbageCollector() {
+	interval := durationFromEnv("MEDIA_GC_INTERVAL_HOURS", defaultMediaGCInterval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			report, err := RunMediaGarbageCollection(context.Background(), false)
+			if err != nil {
+				fmt.Printf("Medya çöp toplama hatası: %v\n", err)
+				continue
+			}
+			fmt.Printf("Medya çöp toplama tamamlandı: %d dosya silindi (%d bayt), %d dosya kayıt altına alındı\n",
+				report.Deleted, report.DeletedBytes, report.Registered)
+		}
+	}()
+}
+
+// CollectMediaGarbage - Çöp toplayıcıyı elle çalıştırır (sadece admin). ?dryRun=true ile hiçbir dosya silinmez.
+func CollectMediaGarbage(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dryRun"))
+
+	report, err := RunMediaGarbageCollection(c.Request.Context(), dryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Message: "Çöp toplama başarısız: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: fmt.Sprintf("%d kullanılmayan dosya temizlendi", report.Deleted),
+		Data:    report,
+	})
+}
+
+// RunMediaGarbageCollection medya referanslarını içerik tablolarıyla uzlaştırır, depoda kaydı olmayan
+// eski dosyaları kayıt altına alır ve bekleme süresi boyunca hiçbir içerikte kullanılmayan dosyaları siler.
+// dryRun yalnızca silme adımlarını atlar.
+func RunMediaGarbageCollection(ctx context.Context, dryRun bool) (MediaGCReport, error) {
+	start := time.Now()
+	report := MediaGCReport{DryRun: dryRun, DeletedKeys: []string{}}
+
+	// 1. İçerik tablolarındaki medya kullanımlarını topla
+	type sourceReference struct {
+		refType string
+		refID   uint
+		ownerID uint
+	}
+	referencesByKey := make(map[string][]sourceReference)
+	for _, source := range mediaReferenceSources {
+		var rows []struct {
+			RefID   uint
+			URL     string
+			OwnerID uint
+		}
+		if err := database.DB.Raw(source.query).Scan(&rows).Error; err != nil {
+			return report, fmt.Errorf("%s referansları okunamadı: %w", source.refType, err)
+		}
+		for _, row := range rows {
+			if key := storageKeyFromURL(row.URL); key != "" {
+				referencesByKey[key] = append(referencesByKey[key], sourceReference{source.refType, row.RefID, row.OwnerID})
+			}
+		}
+	}
+
+	// 2. Depoda olup kaydı bulunmayan dosyaları (sahiplik kaydı öncesinden kalanlar) kaydet.
+	// Görsel varyantları ve HLS çıktıları kaynak dosyaya bağlı olduğundan ayrıca kaydedilmez.
+	known := make(map[string]bool)
+	var knownKeys, variantKeys []string
+	database.DB.Model(&models.Media{}).Pluck("storage_key", &knownKeys)
+	database.DB.Model(&models.ImageVariant{}).Pluck("storage_key", &variantKeys)
+	for _, key := range append(knownKeys, variantKeys...) {
+		known[key] = true
+	}
+	for _, prefix := range []string{imagesKeyPrefix, videosKeyPrefix, thumbnailsKeyPrefix} {
+		objects, err := blobStore.List(ctx, prefix)
+		if err != nil {
+			return report, fmt.Errorf("depo listelenemedi (%s): %w", prefix, err)
+		}
+		for _, object := range objects {
+			if known[object.Key] || strings.HasPrefix(object.Key, hlsKeyPrefix) {
+				continue
+			}
+			sum, err := hashStoredObject(ctx, object.Key)
+			if err != nil {
+				fmt.Printf("Dosya özeti hesaplanamadı (%s): %v\n", object.Key, err)
+				continue
+			}
+			var ownerID uint
+			if refs := referencesByKey[object.Key]; len(refs) > 0 {
+				ownerID = refs[0].ownerID
+			}
+			registerMedia(ownerID, object.Key, "/uploads/"+object.Key, sum, object.Size, object.ContentType, object.ModTime)
+			report.Registered++
+		}
+	}
+
+	// 3. Referans tablosunu uzlaştır: eksikleri ekle, silinmiş içeriklere ait olanları kaldır.
+	// Bu çalışma başladıktan sonra eklenen referanslara dokunulmaz.
+	var mediaRows []models.Media
+	database.DB.Select("id", "storage_key").Find(&mediaRows)
+	mediaIDByKey := make(map[string]uint, len(mediaRows))
+	for _, media := range mediaRows {
+		mediaIDByKey[media.StorageKey] = media.ID
+	}
+	expected := make(map[string]bool)
+	for key, refs := range referencesByKey {
+		mediaID, ok := mediaIDByKey[key]
+		if !ok {
+			continue
+		}
+		for _, ref := range refs {
+			expected[fmt.Sprintf("%d/%s/%d", mediaID, ref.refType, ref.refID)] = true
+			if added, err := insertMediaReference(database.DB, mediaID, ref.refType, ref.refID); err == nil && added {
+				report.ReferencesAdded++
+			}
+		}
+	}
+	var existing []models.MediaReference
+	database.DB.Where("created_at < ?", start).Find(&existing)
+	for _, ref := range existing {
+		if expected[fmt.Sprintf("%d/%s/%d", ref.MediaID, ref.RefType, ref.RefID)] {
+			continue
+		}
+		if deleteMediaReference(database.DB, ref) {
+			report.ReferencesRemoved++
+		}
+	}
+
+	// 4. Bekleme süresi dolmuş, referansı olmayan dosyaları sil. Tamamlanmış ama henüz
+	// kullanılmamış devam ettirilebilir yüklemeler korunur.
+	protected := make(map[string]bool)
+	var pendingUploadURLs []string
+	database.DB.Model(&models.UploadSession{}).
+		Where("status = ? AND used_at IS NULL AND expires_at > ?", models.UploadStatusComplete, start).
+		Pluck("url", &pendingUploadURLs)
+	for _, url := range pendingUploadURLs {
+		protected[storageKeyFromURL(url)] = true
+	}
+
+	// Adaylar referans sayısından seçilir; silme anında referans tablosu ayrıca kontrol edilir, böylece
+	// sapmış bir sayaç kullanılan (başka kullanıcılarla paylaşılan) bir dosyayı sildirmez.
+	cutoff := start.Add(-durationFromEnv("MEDIA_GC_GRACE_HOURS", defaultMediaGCGracePeriod))
+	unreferenced := "updated_at < ? AND NOT EXISTS (SELECT 1 FROM media_references WHERE media_references.media_id = media.id)"
+	var candidates []models.Media
+	if err := database.DB.Where("reference_count <= 0 AND "+unreferenced, cutoff).Find(&candidates).Error; err != nil {
+		return report, err
+	}
+	for _, media := range candidates {
+		if protected[media.StorageKey] {
+			continue
+		}
+		if dryRun {
+			report.DeletedKeys = append(report.DeletedKeys, media.StorageKey)
+			continue
+		}
+		// Kontrol ile silme arasında yeni referans eklendiyse kayıt silinmez ve dosya korunur
+		result := database.DB.Where("id = ? AND "+unreferenced, media.ID, cutoff).Delete(&models.Media{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			continue
+		}
+		database.DB.Where("media_id = ?", media.ID).Delete(&models.MediaOwner{})
+		deleteStoredMedia(ctx, media.StorageKey)
+		report.Deleted++
+		report.DeletedBytes += media.Size
+		report.DeletedKeys = append(report.DeletedKeys, media.StorageKey)
+	}
+
+	// 5. Silinmiş reellere ait HLS çıktılarını temizle
+	deleted, err := collectOrphanedHLS(ctx, dryRun)
+	if err != nil {
+		return report, err
+	}
+	report.DeletedHLSReels = deleted
+
+	return report, nil
+}
+
+// collectOrphanedHLS reeli artık bulunmayan HLS dizinlerini siler ve silinen reel sayısını döndürür
+func collectOrphanedHLS(ctx context.Context, dryRun bool) (int, error) {
+	objects, err := blobStore.List(ctx, hlsKeyPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("HLS çıktıları listelenemedi: %w", err)
+	}
+	keysByReel := make(map[uint][]string)
+	for _, object := range objects {
+		dir := strings.SplitN(strings.TrimPrefix(object.Key, hlsKeyPrefix), "/", 2)[0]
+		if reelID, err := strconv.ParseUint(dir, 10, 32); err == nil {
+			keysByReel[uint(reelID)] = append(keysByReel[uint(reelID)], object.Key)
+		}
+	}
+	if len(keysByReel) == 0 {
+		return 0, nil
+	}
+
+	reelIDs := make([]uint, 0, len(keysByReel))
+	for reelID := range keysByReel {
+		reelIDs = append(reelIDs, reelID)
+	}
+	var existingIDs []uint
+	database.DB.Model(&models.Reels{}).Where("id IN ?", reelIDs).Pluck("id", &existingIDs)
+	for _, reelID := range existingIDs {
+		delete(keysByReel, reelID)
+	}
+	if !dryRun {
+		for _, keys := range keysByReel {
+			for _, key := range keys {
+				blobStore.Delete(ctx, key)
+			}
+		}
+	}
+	return len(keysByReel), nil
+}
+
+// deleteReelHLS bir reelin HLS çıktılarını depodan siler
+func deleteReelHLS(ctx context.Context, reelID uint) {
+	objects, err := blobStore.List(ctx, fmt.Sprintf("%s%d/", hlsKeyPrefix, reelID))
+	if err != nil {
+		fmt.Printf("Reel HLS dosyaları listelenemedi (ReelID: %d): %v\n", reelID, err)
+		return
+	}
+	for _, object := range objects {
+		blobStore.Delete(ctx, object.Key)
+	}
+}
+
+// hashStoredObject depodaki nesnenin SHA-256 özetini hesaplar
+func hashStoredObject(ctx context.Context, key string) (string, error) {
+	reader, _, err := blobStore.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
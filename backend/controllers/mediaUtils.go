@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// hashContent içeriğin SHA-256 özetini ve boyutunu hesaplar, ardından okuyucuyu başa sarar
+func hashContent(content io.ReadSeeker) (string, int64, error) {
+	hash := sha256.New()
+	size, err := io.Copy(hash, content)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// storeMedia yüklenen içeriği özetler. Aynı içerik herhangi bir kullanıcı tarafından daha önce yüklenmişse
+// dosya tekrar yazılmaz, mevcut dosya paylaşılır ve kullanıcıya sahiplik kaydı eklenir; değilse write ile
+// depoya yazılır ve içerik kaydı oluşturulur.
+func storeMedia(ctx context.Context, ownerID uint, key, url, mimeType string, content io.ReadSeeker, write func() error) (*models.Media, error) {
+	sum, size, err := hashContent(content)
+	if err != nil {
+		return nil, err
+	}
+	if existing, found := findDuplicateMedia(ctx, sum, size); found {
+		addMediaOwner(existing.ID, ownerID)
+		return existing, nil
+	}
+
+	if err := write(); err != nil {
+		return nil, err
+	}
+	return registerMedia(ownerID, key, url, sum, size, mimeType, time.Now()), nil
+}
+
+// findDuplicateMedia aynı özet ve boyuttaki dosyanın kaydını kimin yüklediğine bakmadan arar. Dosyanın depoda
+// hâlâ durduğu doğrulanır ve çöp toplama bekleme süresi yeniden başlatılır. Aynı içeriğin eşzamanlı ilk
+// yüklemeleri iki ayrı dosya oluşturabilir; sonraki yüklemeler bunlardan ilkini kullanır.
+func findDuplicateMedia(ctx context.Context, sum string, size int64) (*models.Media, bool) {
+	var candidates []models.Media
+	database.DB.Where("sha256 = ? AND size = ?", sum, size).Order("id").Find(&candidates)
+	for i := range candidates {
+		if _, err := blobStore.Stat(ctx, candidates[i].StorageKey); err != nil {
+			continue
+		}
+		touchMedia(database.DB, candidates[i].ID)
+		return &candidates[i], true
+	}
+	return nil, false
+}
+
+// ownedMedia URL'nin kullanıcının yüklediği bir dosyaya ait olup olmadığını kontrol eder ve kaydını döndürür
+func ownedMedia(userID uint, url string) (*models.Media, bool) {
+	key := storageKeyFromURL(url)
+	if key == "" {
+		return nil, false
+	}
+	var media models.Media
+	if err := database.DB.Where("storage_key = ? AND EXISTS (SELECT 1 FROM media_owners WHERE media_owners.media_id = media.id AND media_owners.user_id = ?)",
+		key, userID).First(&media).Error; err != nil {
+		return nil, false
+	}
+	return &media, true
+}
+
+// addMediaOwner kullanıcıyı dosyanın sahipleri arasına ekler; kayıt zaten varsa bir şey yapılmaz
+func addMediaOwner(mediaID, userID uint) {
+	if userID == 0 {
+		return
+	}
+	owner := models.MediaOwner{MediaID: mediaID, UserID: userID, CreatedAt: time.Now()}
+	if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&owner).Error; err != nil {
+		fmt.Printf("Medya sahipliği eklenemedi (MediaID: %d, UserID: %d): %v\n", mediaID, userID, err)
+	}
+}
+
+// registerMedia depoya yazılmış dosyanın içerik ve sahiplik kaydını oluşturur. Kayıt oluşturulamazsa
+// (ör. anahtar zaten kayıtlı) mevcut kayıt döndürülür.
+func registerMedia(ownerID uint, key, url, sum string, size int64, mimeType string, createdAt time.Time) *models.Media {
+	media := models.Media{
+		OwnerID:    ownerID,
+		StorageKey: key,
+		URL:        url,
+		SHA256:     sum,
+		Size:       size,
+		MimeType:   mimeType,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+	if err := database.DB.Create(&media).Error; err != nil {
+		var existing models.Media
+		if database.DB.Where("storage_key = ?", key).First(&existing).Error == nil {
+			addMediaOwner(existing.ID, ownerID)
+			return &existing
+		}
+		fmt.Printf("Medya kaydı oluşturulamadı (%s): %v\n", key, err)
+		return &media
+	}
+	addMediaOwner(media.ID, ownerID)
+	return &media
+}
+
+// touchMedia medya kaydının güncellenme zamanını yeniler (çöp toplama bekleme süresi buradan sayılır)
+func touchMedia(db *gorm.DB, mediaIDs ...uint) {
+	if len(mediaIDs) == 0 {
+		return
+	}
+	db.Model(&models.Media{}).Where("id IN ?", mediaIDs).Update("updated_at", time.Now())
+}
+
+// addMediaReferences URL'leri verilen medya dosyalarını içeriğe bağlar. Uygulama dışı URL'ler ve
+// kaydı olmayan dosyalar atlanır; eski dosyalar çöp toplayıcı tarafından kayıt altına alınır.
+func addMediaReferences(db *gorm.DB, refType string, refID uint, urls ...string) {
+	var keys []string
+	for _, url := range urls {
+		if key := storageKeyFromURL(url); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	var mediaIDs []uint
+	db.Model(&models.Media{}).Where("storage_key IN ?", keys).Pluck("id", &mediaIDs)
+	for _, mediaID := range mediaIDs {
+		if _, err := insertMediaReference(db, mediaID, refType, refID); err != nil {
+			fmt.Printf("Medya referansı eklenemedi (%s #%d): %v\n", refType, refID, err)
+		}
+	}
+	touchMedia(db, mediaIDs...)
+}
+
+// insertMediaReference referansı ekler ve yeni eklendiyse dosyanın referans sayısını artırır
+func insertMediaReference(db *gorm.DB, mediaID uint, refType string, refID uint) (bool, error) {
+	reference := models.MediaReference{MediaID: mediaID, RefType: refType, RefID: refID, CreatedAt: time.Now()}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&reference)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	return true, adjustCounter(db, &models.Media{}, mediaReferenceCountColumn, 1, mediaID)
+}
+
+// deleteMediaReference referansı siler ve silindiyse dosyanın referans sayısını azaltır
+func deleteMediaReference(db *gorm.DB, reference models.MediaReference) bool {
+	result := db.Delete(&models.MediaReference{}, reference.ID)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false
+	}
+	adjustCounter(db, &models.Media{}, mediaReferenceCountColumn, -1, reference.MediaID)
+	touchMedia(db, reference.MediaID)
+	return true
+}
+
+// removeMediaReferences içeriğin tüm medya referanslarını kaldırır. Başka referansı kalmayan
+// dosyalar bekleme süresinden sonra çöp toplayıcı tarafından silinir.
+func removeMediaReferences(db *gorm.DB, refType string, refID uint) {
+	var references []models.MediaReference
+	db.Where("ref_type = ? AND ref_id = ?", refType, refID).Find(&references)
+	for _, reference := range references {
+		deleteMediaReference(db, reference)
+	}
+}
+
+// setMediaReferences içeriğin medya referanslarını verilen URL'lerle değiştirir
+func setMediaReferences(db *gorm.DB, refType string, refID uint, urls ...string) {
+	removeMediaReferences(db, refType, refID)
+	addMediaReferences(db, refType, refID, urls...)
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+)
+
+// storeTestMedia içeriği verilen kullanıcı adına key anahtarıyla depoya yazar
+func storeTestMedia(t *testing.T, ownerID uint, key, content string) *models.Media {
+	t.Helper()
+	ctx := context.Background()
+	reader := strings.NewReader(content)
+	media, err := storeMedia(ctx, ownerID, key, "/uploads/"+key, "image/png", reader, func() error {
+		_, err := blobStore.Put(ctx, key, reader, int64(len(content)), "image/png")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Medya kaydedilemedi: %v", err)
+	}
+	return media
+}
+
+func TestStoreMediaSharesContentBetweenUsers(t *testing.T) {
+	setupTestDatabase(t)
+	store := setupTestBlobStore(t)
+	alice := createTestUser(t, "alice")
+	bob := createTestUser(t, "bob")
+
+	first := storeTestMedia(t, alice.ID, "images/a.png", pngHeader)
+	second := storeTestMedia(t, bob.ID, "images/b.png", pngHeader)
+	if second.ID != first.ID || second.StorageKey != "images/a.png" {
+		t.Fatalf("ikinci yükleme %q dosyasını döndürdü, paylaşılan %q beklenirdi", second.StorageKey, first.StorageKey)
+	}
+	if _, err := store.Stat(context.Background(), "images/b.png"); err == nil {
+		t.Error("aynı içerik depoya ikinci kez yazıldı")
+	}
+
+	// Dosya iki kullanıcıya da ait sayılır ve ikisinin kotasından düşer
+	for _, user := range []models.User{alice, bob} {
+		if _, ok := ownedMedia(user.ID, first.URL); !ok {
+			t.Errorf("%s paylaşılan dosyanın sahibi sayılmadı", user.Username)
+		}
+		if usage := userStorageUsage(user.ID); usage.UsedBytes != int64(len(pngHeader)) {
+			t.Errorf("%s kullanımı %d bayt, beklenen %d", user.Username, usage.UsedBytes, len(pngHeader))
+		}
+	}
+	if _, ok := ownedMedia(createTestUser(t, "carol").ID, first.URL); ok {
+		t.Error("dosyayı yüklemeyen kullanıcı sahip sayıldı")
+	}
+}
+
+func TestSharedMediaSurvivesWhileReferenced(t *testing.T) {
+	setupTestDatabase(t)
+	store := setupTestBlobStore(t)
+	t.Setenv("MEDIA_GC_GRACE_HOURS", "1")
+	alice := createTestUser(t, "alice")
+	bob := createTestUser(t, "bob")
+
+	media := storeTestMedia(t, alice.ID, "images/a.png", pngHeader)
+	storeTestMedia(t, bob.ID, "images/b.png", pngHeader)
+	stories := []models.Story{
+		{UserID: alice.ID, MediaURL: media.URL, MediaType: "image", ExpiresAt: time.Now().Add(time.Hour)},
+		{UserID: bob.ID, MediaURL: media.URL, MediaType: "image", ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	database.DB.Create(&stories)
+	addMediaReferences(database.DB, models.MediaRefStory, stories[0].ID, media.URL)
+	addMediaReferences(database.DB, models.MediaRefStory, stories[1].ID, media.URL)
+	addMediaReferences(database.DB, models.MediaRefStory, stories[1].ID, media.URL) // tekrar eklenen referans sayılmaz
+	if got := counterValue(database.DB, &models.Media{}, mediaReferenceCountColumn, media.ID); got != 2 {
+		t.Fatalf("referans sayısı %d, beklenen 2", got)
+	}
+
+	// İlk kullanıcının içeriği silinse de dosya ikinci içerikte kullanıldığı için korunur
+	database.DB.Delete(&stories[0])
+	removeMediaReferences(database.DB, models.MediaRefStory, stories[0].ID)
+	if got := counterValue(database.DB, &models.Media{}, mediaReferenceCountColumn, media.ID); got != 1 {
+		t.Fatalf("referans sayısı %d, beklenen 1", got)
+	}
+	expireMediaGracePeriod(t)
+	report, err := RunMediaGarbageCollection(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Çöp toplama başarısız: %v", err)
+	}
+	if report.Deleted != 0 {
+		t.Fatalf("%d dosya silindi, referansı olan paylaşılan dosya korunmalı", report.Deleted)
+	}
+
+	// Son referans da kalkınca dosya, içerik ve sahiplik kayıtlarıyla birlikte silinir
+	database.DB.Delete(&stories[1])
+	removeMediaReferences(database.DB, models.MediaRefStory, stories[1].ID)
+	expireMediaGracePeriod(t)
+	if _, err := RunMediaGarbageCollection(context.Background(), false); err != nil {
+		t.Fatalf("Çöp toplama başarısız: %v", err)
+	}
+	if _, err := store.Stat(context.Background(), media.StorageKey); err == nil {
+		t.Error("referansı kalmayan dosya depodan silinmedi")
+	}
+	var owners int64
+	database.DB.Model(&models.MediaOwner{}).Where("media_id = ?", media.ID).Count(&owners)
+	if owners != 0 {
+		t.Errorf("%d sahiplik kaydı kaldı", owners)
+	}
+}
+
+// expireMediaGracePeriod tüm medya kayıtlarının çöp toplama bekleme süresini doldurur
+func expireMediaGracePeriod(t *testing.T) {
+	t.Helper()
+	database.DB.Model(&models.Media{}).Where("1 = 1").UpdateColumn("updated_at", time.Now().Add(-2*time.Hour))
+}
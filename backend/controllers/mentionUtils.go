@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/services"
+	"social-media-app/backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// buildTextEntities metindeki varlıkları çıkarır ve mention'ları kullanıcı ID'lerine çözümler.
+// Birden fazla metin içeren listelerde mentionUserIDs ile tek sorgu yapılıp textEntities kullanılmalı.
+func buildTextEntities(text string) []models.TextEntity {
+	return textEntities(text, mentionUserIDs(text))
+}
+
+// mentionUserIDs verilen metinlerdeki tüm mention'ları tek sorguda kullanıcı ID'lerine çözümler
+// (anahtarlar küçük harfli kullanıcı adlarıdır)
+func mentionUserIDs(texts ...string) map[string]uint {
+	userIDs := make(map[string]uint)
+	usernames := utils.ExtractMentions(texts...)
+	if len(usernames) == 0 {
+		return userIDs
+	}
+
+	var users []models.User
+	database.DB.Select("id, username").Where("LOWER(username) IN ?", usernameKeys(usernames)).Find(&users)
+	for _, user := range users {
+		userIDs[strings.ToLower(user.Username)] = user.ID
+	}
+	return userIDs
+}
+
+// usernameKeys kullanıcı adlarını büyük/küçük harf duyarsız eşleştirme için küçük harfe çevirir;
+// "@Alice" yazılan bir mention "alice" kullanıcısına çözümlenir
+func usernameKeys(usernames []string) []string {
+	keys := make([]string, len(usernames))
+	for i, username := range usernames {
+		keys[i] = strings.ToLower(username)
+	}
+	return keys
+}
+
+// postTexts gönderilerin mention içerebilen metinlerini toplar
+func postTexts(posts []models.Post) []string {
+	texts := make([]string, 0, len(posts)*2)
+	for _, post := range posts {
+		texts = append(texts, post.Content, post.Caption)
+	}
+	return texts
+}
+
+// textEntities metnin varlıklarını önceden çözümlenmiş mention'larla oluşturur
+func textEntities(text string, userIDs map[string]uint) []models.TextEntity {
+	entities := utils.ParseTextEntities(text)
+	for i := range entities {
+		if entities[i].Type != utils.EntityTypeMention {
+			continue
+		}
+		if id, ok := userIDs[strings.ToLower(entities[i].Value)]; ok {
+			userID := id
+			entities[i].UserID = &userID
+		}
+	}
+	return entities
+}
+
+// fillCommentEntities yorum ve yanıtlarının metin varlıklarını tek mention sorgusuyla doldurur
+func fillCommentEntities(comments []models.Comment) {
+	var texts []string
+	var collect func(comments []models.Comment)
+	collect = func(comments []models.Comment) {
+		for _, comment := range comments {
+			texts = append(texts, comment.Content)
+			collect(comment.Replies)
+		}
+	}
+	collect(comments)
+
+	userIDs := mentionUserIDs(texts...)
+	var fill func(comments []models.Comment)
+	fill = func(comments []models.Comment) {
+		for i := range comments {
+			comments[i].Entities = textEntities(comments[i].Content, userIDs)
+			fill(comments[i].Replies)
+		}
+	}
+	fill(comments)
+}
+
+// canMentionUser yazarın hedef kullanıcıyı TagPermission ayarına göre etiketleyip etiketleyemeyeceğini kontrol eder
+func canMentionUser(authorID uint, target models.User) bool {
+	if authorID == target.ID {
+		return true
+	}
+	if isBlockedBetween(authorID, target.ID) {
+		return false
+	}
+
+	switch target.TagPermission {
+	case "none":
+		return false
+	case "followers":
+		// Sadece hedef kullanıcıyı takip edenler etiketleyebilir
+		var count int64
+		database.DB.Model(&models.Follow{}).
+			Where("follower_id = ? AND following_id = ?", authorID, target.ID).
+			Count(&count)
+		return count > 0
+	default: // "all"
+		return true
+	}
+}
+
+// saveMentions metinlerdeki @mention'ları kaydeder ve bahsedilen kullanıcılara bildirim gönderir.
+// target şablonunda PostID, ReelID veya CommentID alanlarından biri dolu olmalıdır.
+// İzin vermeyen kullanıcılar atlanır; bildirim hatası ana işlemi etkilemez.
+func saveMentions(ctx context.Context, author models.User, target models.Mention, texts ...string) []models.Mention {
+	usernames := utils.ExtractMentions(texts...)
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	var users []models.User
+	if err := database.DB.Select("id, username, tag_permission").
+		Where("LOWER(username) IN ?", usernameKeys(usernames)).
+		Find(&users).Error; err != nil {
+		log.Printf("Bahsedilen kullanıcılar alınamadı: %v", err)
+		return nil
+	}
+
+	entityType, entityID := mentionEntity(target)
+
+	var saved []models.Mention
+	for _, user := range users {
+		if user.ID == author.ID || !canMentionUser(author.ID, user) {
+			continue
+		}
+
+		// Aynı içerikte aynı kullanıcı iki kez kaydedilmesin
+		var existing int64
+		mentionScope(database.DB.Model(&models.Mention{}), target).
+			Where("mentioned_user_id = ?", user.ID).
+			Count(&existing)
+		if existing > 0 {
+			continue
+		}
+
+		mention := target
+		mention.ID = 0
+		mention.MentionedUserID = user.ID
+		mention.FromUserID = author.ID
+		mention.CreatedAt = time.Now()
+		if err := database.DB.Create(&mention).Error; err != nil {
+			log.Printf("Mention kaydedilemedi: %v", err)
+			continue
+		}
+		saved = append(saved, mention)
+
+		sendMentionNotification(ctx, author, user.ID, entityType, entityID)
+	}
+
+	return saved
+}
+
+// mentionEntity mention hedefinin bildirimde kullanılacak tür ve ID bilgisini döndürür
+func mentionEntity(target models.Mention) (string, uint) {
+	switch {
+	case target.CommentID != nil:
+		return "comment", *target.CommentID
+	case target.ReelID != nil:
+		return "reel", *target.ReelID
+	case target.PostID != nil:
+		return "post", *target.PostID
+	}
+	return "", 0
+}
+
+// mentionScope sorguyu mention hedefinin içeriğiyle sınırlar
+func mentionScope(query *gorm.DB, target models.Mention) *gorm.DB {
+	switch {
+	case target.CommentID != nil:
+		return query.Where("comment_id = ?", *target.CommentID)
+	case target.ReelID != nil:
+		return query.Where("reel_id = ? AND comment_id IS NULL", *target.ReelID)
+	case target.PostID != nil:
+		return query.Where("post_id = ? AND comment_id IS NULL", *target.PostID)
+	}
+	return query.Where("1 = 0")
+}
+
+// sendMentionNotification bahsedilen kullanıcıya veritabanı ve WebSocket bildirimi gönderir
+func sendMentionNotification(ctx context.Context, author models.User, toUserID uint, entityType string, entityID uint) {
+	// Kullanıcı mention bildirimlerini kapattıysa gönderme
+	var settings models.NotificationSettings
+	if database.DB.Where("user_id = ?", toUserID).First(&settings).Error == nil && !settings.MentionsEnabled {
+		return
+	}
+
+	var message string
+	switch entityType {
+	case "comment":
+		message = fmt.Sprintf("%s bir yorumda sizden bahsetti", author.Username)
+	case "reel":
+		message = fmt.Sprintf("%s bir reelde sizden bahsetti", author.Username)
+	default:
+		message = fmt.Sprintf("%s bir gönderide sizden bahsetti", author.Username)
+	}
+
+	notification := models.Notification{
+		ToUserID:   toUserID,
+		FromUserID: author.ID,
+		Type:       "mention",
+		Message:    message,
+		IsRead:     false,
+		CreatedAt:  time.Now(),
+	}
+	if err := database.DB.Create(&notification).Error; err != nil {
+		log.Printf("Mention bildirimi oluşturulamadı: %v", err)
+		return
+	}
+
+	if notifService != nil {
+		wsNotification := services.Notification{
+			ID:                fmt.Sprintf("%d", notification.ID),
+			UserID:            fmt.Sprintf("%d", toUserID),
+			ActorID:           fmt.Sprintf("%d", author.ID),
+			ActorName:         author.FullName,
+			ActorUsername:     author.Username,
+			ActorProfileImage: author.ProfileImage,
+			Type:              services.NotificationTypeMention,
+			EntityID:          fmt.Sprintf("%d", entityID),
+			EntityType:        entityType,
+			Content:           message,
+			IsRead:            false,
+			CreatedAt:         notification.CreatedAt,
+		}
+		if err := notifService.SendNotification(ctx, wsNotification); err != nil {
+			log.Printf("WebSocket mention bildirimi gönderilemedi: %v", err)
+		}
+	}
+}
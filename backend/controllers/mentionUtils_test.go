@@ -0,0 +1,22 @@
+package controllers
+
+import "testing"
+
+func TestBuildTextEntitiesResolvesMentionsCaseInsensitively(t *testing.T) {
+	setupTestDatabase(t)
+	alice := createTestUser(t, "alice")
+	ayse := createTestUser(t, "ayşe")
+
+	entities := buildTextEntities("@Alice ve @ayşe, @kimse")
+	if len(entities) != 3 {
+		t.Fatalf("%d varlık bulundu, 3 bekleniyordu", len(entities))
+	}
+	for i, want := range []uint{alice.ID, ayse.ID} {
+		if entities[i].UserID == nil || *entities[i].UserID != want {
+			t.Errorf("%q kullanıcı %d olarak çözümlenmedi (UserID=%v)", entities[i].Text, want, entities[i].UserID)
+		}
+	}
+	if entities[2].UserID != nil {
+		t.Errorf("var olmayan kullanıcı çözümlendi: %d", *entities[2].UserID)
+	}
+}
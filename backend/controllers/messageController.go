@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Message yapısı istemcilerden gelen mesaj formatı
@@ -32,9 +33,13 @@ type MessageResponse struct {
 	Content    string    `json:"content"`
 	MediaURL   string    `json:"mediaUrl"`
 	MediaType  string    `json:"mediaType"`
+	StoryID    *uint     `json:"storyId,omitempty"` // Hikaye yanıtlarında yanıtlanan hikaye
 	SentAt     time.Time `json:"sentAt"`
 	IsRead     bool      `json:"isRead"`
 	SenderInfo UserInfo  `json:"senderInfo"`
+
+	// Paylaşılan gönderi, reel veya profilin okuyana göre önizlemesi
+	SharedContent gin.H `json:"sharedContent,omitempty"`
 }
 
 // UserInfo mesaj yanıtında kullanıcı bilgisi
@@ -74,6 +79,7 @@ func GetConversations(c *gin.Context) {
 		ProfileImage  string    `json:"profileImage"`
 		LastMessageID uint      `json:"lastMessageId"`
 		LastContent   string    `json:"lastContent"`
+		LastShared    string    `json:"lastSharedType,omitempty"` // Son mesaj paylaşılan içerikse türü
 		LastTimestamp time.Time `json:"lastTimestamp"`
 		UnreadCount   int       `json:"unreadCount"`
 	}
@@ -90,6 +96,7 @@ func GetConversations(c *gin.Context) {
 		u.profile_image,
 		MAX(m.id) as last_message_id,
 		m2.content as last_content,
+		m2.shared_type as last_shared,
 		m2.sent_at as last_timestamp,
 		SUM(CASE WHEN m.is_read = false AND m.receiver_id = ? THEN 1 ELSE 0 END) as unread_count
 	FROM messages m
@@ -172,8 +179,9 @@ func GetConversation(c *gin.Context) {
 	// Kullanıcı bilgilerini ekle
 	var formattedMessages []MessageResponse
 	var senderUser models.User
+	sharedPreviews := sharedContentPreviews(userID.(uint), messages)
 
-	for _, message := range messages {
+	for i, message := range messages {
 		// Gönderen bilgilerini al
 		sender := targetUser // Varsayılan olarak hedef kullanıcı
 		if message.SenderID == userID.(uint) {
@@ -191,6 +199,7 @@ func GetConversation(c *gin.Context) {
 			Content:    message.Content,
 			MediaURL:   message.MediaURL,
 			MediaType:  message.MediaType,
+			StoryID:    message.StoryID,
 			SentAt:     message.SentAt,
 			IsRead:     message.IsRead,
 			SenderInfo: UserInfo{
@@ -199,6 +208,7 @@ func GetConversation(c *gin.Context) {
 				FullName:     sender.FullName,
 				ProfileImage: sender.ProfileImage,
 			},
+			SharedContent: sharedPreviews[i],
 		}
 
 		formattedMessages = append(formattedMessages, formattedMessage)
@@ -269,8 +279,8 @@ func SendMessage(c *gin.Context) {
 		IsRead:     false,
 	}
 
-	// Mesajı kaydet
-	if err := database.DB.Create(&message).Error; err != nil {
+	response, err := sendDirectMessage(&message)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
 			Message: "Mesaj kaydedilirken bir hata oluştu: " + err.Error(),
@@ -278,29 +288,61 @@ func SendMessage(c *gin.Context) {
 		return
 	}
 
-	// Bildirim oluştur
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// sendDirectMessage mesajı kaydeder, alıcıya bildirim oluşturur ve istemciye dönülecek yanıtı hazırlar.
+// SendMessage ve hikaye yanıtları bu fonksiyonu kullanır; mesajla birlikte başka kayıt oluşturulacaksa
+// (ör. içerik paylaşımları) createDirectMessage transaction içinde çağrılmalıdır.
+func sendDirectMessage(message *models.Message) (MessageResponse, error) {
+	if err := createDirectMessage(database.DB, message); err != nil {
+		return MessageResponse{}, err
+	}
+	return directMessageResponse(*message), nil
+}
+
+// createDirectMessage mesajı, medya referansını ve alıcının bildirimini verilen bağlantıda oluşturur
+func createDirectMessage(tx *gorm.DB, message *models.Message) error {
+	// Mesajı kaydet
+	if err := tx.Create(message).Error; err != nil {
+		return err
+	}
+	addMediaReferences(tx, models.MediaRefMessage, message.ID, message.MediaURL)
+
+	// Bildirim oluştur; notsuz paylaşımlarda paylaşılan içerik türü gösterilir
+	notificationText := message.Content
+	if notificationText == "" {
+		notificationText = sharedMessageSummary(*message)
+	}
 	notification := models.Notification{
 		ToUserID:   message.ReceiverID,
 		FromUserID: message.SenderID,
 		Type:       "message",
-		Message:    message.Content,
+		Message:    notificationText,
 		IsRead:     false,
 		CreatedAt:  time.Now(),
 	}
-	database.DB.Create(&notification)
+	return tx.Create(&notification).Error
+}
 
+// directMessageResponse kaydedilen mesajı gönderen bilgisi ve paylaşılan içerik önizlemesiyle döndürür
+func directMessageResponse(message models.Message) MessageResponse {
 	// Kullanıcı bilgilerini getir
 	var sender models.User
-	database.DB.Select("id, username, full_name, profile_image").First(&sender, userID)
+	database.DB.Select("id, username, full_name, profile_image").First(&sender, message.SenderID)
 
 	// Yanıt oluştur
-	response := MessageResponse{
+	return MessageResponse{
 		ID:         message.ID,
 		SenderID:   message.SenderID,
 		ReceiverID: message.ReceiverID,
 		Content:    message.Content,
 		MediaURL:   message.MediaURL,
 		MediaType:  message.MediaType,
+		StoryID:    message.StoryID,
 		SentAt:     message.SentAt,
 		IsRead:     message.IsRead,
 		SenderInfo: UserInfo{
@@ -309,12 +351,8 @@ func SendMessage(c *gin.Context) {
 			FullName:     sender.FullName,
 			ProfileImage: sender.ProfileImage,
 		},
+		SharedContent: sharedContentPreview(message.SenderID, message),
 	}
-
-	c.JSON(http.StatusOK, Response{
-		Success: true,
-		Data:    response,
-	})
 }
 
 // SendTypingStatus yazma durumunu karşı tarafa bildirir
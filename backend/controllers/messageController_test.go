@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// sendSharedMessage sender'dan receiver'a içerik paylaşan bir mesaj kaydeder
+func sendSharedMessage(t *testing.T, senderID, receiverID uint, sharedType string, sharedID uint) {
+	t.Helper()
+	message := models.Message{
+		SenderID:   senderID,
+		ReceiverID: receiverID,
+		MediaType:  models.MessageMediaTypeShared,
+		SharedType: sharedType,
+		SharedID:   &sharedID,
+		SentAt:     time.Now(),
+	}
+	if err := database.DB.Create(&message).Error; err != nil {
+		t.Fatalf("Mesaj oluşturulamadı: %v", err)
+	}
+}
+
+// getConversationShares konuşmayı getirir, mesajların paylaşım önizlemelerini ve yapılan sorgu sayısını döndürür
+func getConversationShares(t *testing.T, viewerID, otherID uint) ([]map[string]interface{}, int64) {
+	t.Helper()
+	var queries int64
+	countQuery := func(*gorm.DB) { atomic.AddInt64(&queries, 1) }
+	database.DB.Callback().Query().After("gorm:query").Register("test:count_queries", countQuery)
+	database.DB.Callback().Row().After("gorm:row").Register("test:count_rows", countQuery)
+	defer database.DB.Callback().Query().Remove("test:count_queries")
+	defer database.DB.Callback().Row().Remove("test:count_rows")
+
+	router := gin.New()
+	router.GET("/messages/:userId", func(c *gin.Context) { c.Set("userID", viewerID) }, GetConversation)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/messages/%d", otherID), nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("durum %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var response struct {
+		Data struct {
+			Messages []struct {
+				SharedContent map[string]interface{} `json:"sharedContent"`
+			} `json:"messages"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Yanıt çözümlenemedi: %v", err)
+	}
+	shares := make([]map[string]interface{}, 0, len(response.Data.Messages))
+	for _, message := range response.Data.Messages {
+		shares = append(shares, message.SharedContent)
+	}
+	return shares, atomic.LoadInt64(&queries)
+}
+
+func TestGetConversationSharedContentPreviews(t *testing.T) {
+	setupTestDatabase(t)
+	gin.SetMode(gin.TestMode)
+	viewer := createTestUser(t, "okuyucu")
+	friend := createTestUser(t, "arkadas")
+	author := createTestUser(t, "yazar")
+	blocked := createTestUser(t, "engelli")
+	database.DB.Create(&models.Block{BlockerID: viewer.ID, BlockedID: blocked.ID})
+
+	published := models.Post{UserID: author.ID, Content: "yayında", PublishStatus: models.PublishStatusPublished}
+	draft := models.Post{UserID: author.ID, Content: "taslak", PublishStatus: models.PublishStatusDraft}
+	database.DB.Create(&published)
+	database.DB.Create(&draft)
+	reel := createTestReel(t, author.ID, 15)
+
+	sendSharedMessage(t, friend.ID, viewer.ID, models.SharedContentPost, published.ID)
+	sendSharedMessage(t, friend.ID, viewer.ID, models.SharedContentPost, draft.ID)
+	sendSharedMessage(t, friend.ID, viewer.ID, models.SharedContentReel, reel.ID)
+	sendSharedMessage(t, friend.ID, viewer.ID, models.SharedContentProfile, author.ID)
+	sendSharedMessage(t, friend.ID, viewer.ID, models.SharedContentProfile, blocked.ID)
+	sendSharedMessage(t, friend.ID, viewer.ID, models.SharedContentPost, 9999)
+
+	shares, queries := getConversationShares(t, viewer.ID, friend.ID)
+	want := []bool{true, false, true, true, false, false}
+	if len(shares) != len(want) {
+		t.Fatalf("%d mesaj döndü, beklenen %d", len(shares), len(want))
+	}
+	for i, available := range want {
+		if shares[i]["available"] != available {
+			t.Errorf("%d. paylaşım (%v %v) available=%v, beklenen %v", i, shares[i]["type"], shares[i]["id"], shares[i]["available"], available)
+		}
+	}
+	if post, _ := shares[0]["post"].(map[string]interface{}); post["content"] != "yayında" {
+		t.Errorf("gönderi önizlemesi %v", shares[0]["post"])
+	}
+
+	// Önizlemeler tür başına toplu yüklenir: paylaşım sayısı arttıkça sorgu sayısı artmaz
+	for i := 0; i < 10; i++ {
+		post := models.Post{UserID: author.ID, Content: fmt.Sprintf("gönderi %d", i), PublishStatus: models.PublishStatusPublished}
+		database.DB.Create(&post)
+		sendSharedMessage(t, friend.ID, viewer.ID, models.SharedContentPost, post.ID)
+		sendSharedMessage(t, friend.ID, viewer.ID, models.SharedContentReel, reel.ID)
+	}
+	if _, more := getConversationShares(t, viewer.ID, friend.ID); more != queries {
+		t.Errorf("20 paylaşım daha eklenince sorgu sayısı %d'den %d'e çıktı", queries, more)
+	}
+}
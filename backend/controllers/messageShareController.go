@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxShareRecipients tek seferde içerik paylaşılabilecek en fazla konuşma sayısı
+const maxShareRecipients = 20
+
+// shareMessageRequest gönderi, reel veya profili bir ya da birden fazla konuşmaya gönderme isteği
+type shareMessageRequest struct {
+	Type    string `json:"type"` // post, reel, profile
+	ID      uint   `json:"id"`
+	UserIDs []uint `json:"userIds"`
+	Content string `json:"content"` // Paylaşımla birlikte gönderilen isteğe bağlı not
+}
+
+// shareFailure paylaşımın gönderilemediği alıcı ve nedeni
+type shareFailure struct {
+	UserID uint   `json:"userId"`
+	Reason string `json:"reason"`
+}
+
+// ShareToMessages - Gönderi, reel veya profili direkt mesajla bir ya da birden fazla kullanıcıya gönderir
+func ShareToMessages(c *gin.Context) {
+	var request shareMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+		return
+	}
+
+	shareToConversations(c, request)
+}
+
+// shareToConversations içeriği alıcıların her biriyle olan konuşmaya paylaşılan içerik mesajı olarak gönderir.
+// Gönderenin içeriği görebilmesi gönderim anında, alıcının görebilmesi ise mesaj okunurken kontrol edilir.
+func shareToConversations(c *gin.Context, request shareMessageRequest) {
+	userID := c.GetUint("userID")
+
+	if request.Type != models.SharedContentPost && request.Type != models.SharedContentReel &&
+		request.Type != models.SharedContentProfile {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Paylaşım türü post, reel veya profile olmalı"})
+		return
+	}
+	if request.ID == 0 {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Paylaşılacak içerik belirtilmeli"})
+		return
+	}
+
+	recipients := uniqueUints(request.UserIDs)
+	if len(recipients) == 0 {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "En az bir alıcı seçilmeli"})
+		return
+	}
+	if len(recipients) > maxShareRecipients {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: fmt.Sprintf("Tek seferde en fazla %d kişiye gönderilebilir", maxShareRecipients),
+		})
+		return
+	}
+
+	contentID, ok := resolveSharedContent(userID, request.Type, request.ID)
+	if !ok {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Paylaşılacak içerik bulunamadı"})
+		return
+	}
+
+	// Her alıcının mesajı ve paylaşım kaydı tek transaction içinde oluşturulur; paylaşım sayılamazsa
+	// mesaj da gönderilmez. Gönderi ve reel paylaşımları her alıcı için ayrı paylaşım olarak sayılır.
+	sent := []MessageResponse{}
+	failed := []shareFailure{}
+	var sendErr error
+	now := time.Now()
+	for _, recipientID := range recipients {
+		if reason := shareRecipientBlockReason(userID, recipientID); reason != "" {
+			failed = append(failed, shareFailure{UserID: recipientID, Reason: reason})
+			continue
+		}
+
+		sharedID := contentID
+		message := models.Message{
+			SenderID:   userID,
+			ReceiverID: recipientID,
+			Content:    strings.TrimSpace(request.Content),
+			MediaType:  models.MessageMediaTypeShared,
+			SharedType: request.Type,
+			SharedID:   &sharedID,
+			SentAt:     now,
+			IsRead:     false,
+		}
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := createDirectMessage(tx, &message); err != nil {
+				return err
+			}
+			if request.Type == models.SharedContentProfile {
+				return nil
+			}
+			return recordShare(tx, request.Type, contentID, userID, models.ShareChannelMessage, nil)
+		})
+		if err != nil {
+			log.Printf("Mesajla paylaşım gönderilemedi (%s %d -> %d): %v", request.Type, contentID, recipientID, err)
+			sendErr = err
+			failed = append(failed, shareFailure{UserID: recipientID, Reason: "Mesaj gönderilemedi"})
+			continue
+		}
+		sent = append(sent, directMessageResponse(message))
+	}
+
+	if len(sent) == 0 {
+		status := http.StatusBadRequest
+		if sendErr != nil {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, Response{
+			Success: false,
+			Message: "İçerik hiçbir alıcıya gönderilemedi",
+			Data:    gin.H{"sent": sent, "failed": failed},
+		})
+		return
+	}
+
+	data := gin.H{"sent": sent, "failed": failed}
+	if request.Type != models.SharedContentProfile {
+		if request.Type == models.SharedContentReel {
+			data["shareCount"] = counterValue(database.DB, &models.Reels{}, shareCountColumn, contentID)
+		} else {
+			data["shareCount"] = counterValue(database.DB, &models.Post{}, shareCountColumn, contentID)
+		}
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: fmt.Sprintf("İçerik %d konuşmaya gönderildi", len(sent)),
+		Data:    data,
+	})
+}
+
+// resolveSharedContent paylaşılacak içeriğin gönderen tarafından görülebildiğini doğrular ve mesajda
+// saklanacak kimliği döndürür. Metinsiz yeniden paylaşımlar yerine orijinal içerik paylaşılır.
+func resolveSharedContent(userID uint, contentType string, contentID uint) (uint, bool) {
+	switch contentType {
+	case models.SharedContentProfile:
+		var user models.User
+		if err := database.DB.Select("id").First(&user, contentID).Error; err != nil || isBlockedBetween(userID, user.ID) {
+			return 0, false
+		}
+		return user.ID, true
+	case models.SharedContentReel:
+		return contentID, reelPreview(userID, contentID) != nil
+	}
+
+	var post models.Post
+	if err := database.DB.First(&post, contentID).Error; err != nil {
+		return 0, false
+	}
+	if isPlainRepost(post) && post.RepostOfType == models.ContentTypePost {
+		return resolveSharedContent(userID, models.SharedContentPost, *post.RepostOfID)
+	}
+	return post.ID, postPreview(userID, post.ID) != nil
+}
+
+// shareRecipientBlockReason içeriğin alıcıya gönderilememe nedenini döndürür (gönderilebiliyorsa boş)
+func shareRecipientBlockReason(senderID, recipientID uint) string {
+	if senderID == recipientID {
+		return "Kendinize içerik gönderemezsiniz"
+	}
+	var recipient models.User
+	if err := database.DB.Select("id").First(&recipient, recipientID).Error; err != nil {
+		return "Kullanıcı bulunamadı"
+	}
+	if isBlockedBetween(senderID, recipientID) {
+		return "Bu kullanıcıya mesaj gönderilemez"
+	}
+	return ""
+}
+
+// sharedContentPreview mesajda paylaşılan içeriğin izleyiciye göre önizlemesini döndürür (paylaşım yoksa nil).
+// Görünürlük okuma anında kontrol edilir; izleyici içeriği göremiyorsa sadece "available: false" döner.
+func sharedContentPreview(viewerID uint, message models.Message) gin.H {
+	return sharedContentPreviews(viewerID, []models.Message{message})[0]
+}
+
+// sharedContentPreviews mesajlarda paylaşılan içeriklerin önizlemelerini mesajlarla aynı sırada döndürür.
+// Paylaşılan gönderi, reel ve profiller tür başına tek sorguda yüklenir; hesap görünürlüğü ve engel
+// kontrolleri de içerik sahibi başına bir kez yapılır.
+func sharedContentPreviews(viewerID uint, messages []models.Message) []gin.H {
+	ids := map[string][]uint{}
+	for _, message := range messages {
+		if message.SharedID != nil && message.SharedType != "" {
+			ids[message.SharedType] = append(ids[message.SharedType], *message.SharedID)
+		}
+	}
+
+	posts := map[uint]models.Post{}
+	if len(ids[models.SharedContentPost]) > 0 {
+		var loaded []models.Post
+		database.DB.Preload("User").Preload("Images", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id")
+		}).Where("id IN ?", ids[models.SharedContentPost]).Find(&loaded)
+		for _, post := range loaded {
+			posts[post.ID] = post
+		}
+	}
+	reels := map[uint]models.Reels{}
+	if len(ids[models.SharedContentReel]) > 0 {
+		var loaded []models.Reels
+		database.DB.Preload("User").Where("id IN ?", ids[models.SharedContentReel]).Find(&loaded)
+		for _, reel := range loaded {
+			reels[reel.ID] = reel
+		}
+	}
+	profiles := map[uint]models.User{}
+	if len(ids[models.SharedContentProfile]) > 0 {
+		var loaded []models.User
+		database.DB.Select("id, username, full_name, profile_image, bio, is_private, is_verified").
+			Where("id IN ?", ids[models.SharedContentProfile]).Find(&loaded)
+		for _, user := range loaded {
+			profiles[user.ID] = user
+		}
+	}
+
+	accountVisible := map[uint]bool{}
+	canViewAccount := func(ownerID uint) bool {
+		visible, ok := accountVisible[ownerID]
+		if !ok {
+			visible = canViewAccountContent(viewerID, ownerID)
+			accountVisible[ownerID] = visible
+		}
+		return visible
+	}
+	blocked := map[uint]bool{}
+	isBlocked := func(userID uint) bool {
+		result, ok := blocked[userID]
+		if !ok {
+			result = isBlockedBetween(viewerID, userID)
+			blocked[userID] = result
+		}
+		return result
+	}
+
+	previews := make([]gin.H, len(messages))
+	for i, message := range messages {
+		if message.SharedID == nil || message.SharedType == "" {
+			continue
+		}
+		shared := gin.H{
+			"type":      message.SharedType,
+			"id":        *message.SharedID,
+			"available": false,
+		}
+
+		var preview gin.H
+		switch message.SharedType {
+		case models.SharedContentPost:
+			if post, ok := posts[*message.SharedID]; ok && CanViewPost(viewerID, post) &&
+				isPublished(post.PublishStatus) && canViewAccount(post.UserID) {
+				preview = formatPostPreview(post)
+			}
+		case models.SharedContentReel:
+			if reel, ok := reels[*message.SharedID]; ok && reel.Status == models.ReelStatusReady &&
+				canViewReel(viewerID, reel) && isPublished(reel.PublishStatus) && canViewAccount(reel.UserID) {
+				preview = formatReelPreview(reel)
+			}
+		case models.SharedContentProfile:
+			if user, ok := profiles[*message.SharedID]; ok && !isBlocked(user.ID) {
+				preview = formatProfilePreview(user)
+			}
+		}
+		if preview != nil {
+			shared["available"] = true
+			shared[message.SharedType] = preview
+		}
+		previews[i] = shared
+	}
+	return previews
+}
+
+// formatProfilePreview paylaşılan profilin özet bilgisini oluşturur
+func formatProfilePreview(user models.User) gin.H {
+	return gin.H{
+		"id":           user.ID,
+		"username":     user.Username,
+		"fullName":     user.FullName,
+		"profileImage": user.ProfileImage,
+		"bio":          user.Bio,
+		"isPrivate":    user.IsPrivate,
+		"isVerified":   user.IsVerified,
+	}
+}
+
+// sharedMessageSummary paylaşılan içerik mesajı için bildirimlerde gösterilecek metni döndürür
+func sharedMessageSummary(message models.Message) string {
+	switch message.SharedType {
+	case models.SharedContentPost:
+		return "Bir gönderi paylaştı"
+	case models.SharedContentReel:
+		return "Bir reel paylaştı"
+	case models.SharedContentProfile:
+		return "Bir profil paylaştı"
+	}
+	return message.Content
+}
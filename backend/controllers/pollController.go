@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errPollAlreadyVoted kullanıcı ankete daha önce oy verdiyse döner
+var errPollAlreadyVoted = errors.New("Bu ankete zaten oy verdiniz")
+
+// findPollPost anketli gönderiyi kullanıcının görebildiği yayınlanmış gönderiler arasından bulur
+func findPollPost(userID uint, postID string) (models.Post, bool) {
+	var post models.Post
+	if err := database.DB.First(&post, postID).Error; err != nil || !post.HasPoll ||
+		!CanViewPost(userID, post) || !isPublished(post.PublishStatus) || !canViewAccountContent(userID, post.UserID) {
+		return post, false
+	}
+	return post, true
+}
+
+// GetPostPoll - Gönderideki anketi getirir; sonuçlar oy verilene veya anket kapanana kadar gizlidir
+func GetPostPoll(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	post, ok := findPollPost(userID, c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Anket bulunamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data:    gin.H{"poll": postPoll(userID, post)},
+	})
+}
+
+// VotePoll - Gönderideki ankete oy verir. Her kullanıcı bir kez oy verebilir; tek seçimli anketlerde
+// sadece bir seçenek seçilebilir. Oy ve sayaçlar aynı transaction içinde atomik olarak güncellenir.
+func VotePoll(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		OptionIDs []uint `json:"optionIds"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+		return
+	}
+
+	post, ok := findPollPost(userID, c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Anket bulunamadı"})
+		return
+	}
+	var poll models.Poll
+	if err := database.DB.Preload("Options").Where("post_id = ?", post.ID).First(&poll).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Anket bulunamadı"})
+		return
+	}
+	if isPollClosed(poll, time.Now()) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: errPollClosed.Error()})
+		return
+	}
+
+	optionIDs := uniqueUints(request.OptionIDs)
+	if len(optionIDs) == 0 {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "En az bir seçenek seçilmeli"})
+		return
+	}
+	if !poll.MultipleChoice && len(optionIDs) > 1 {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Bu ankette sadece bir seçenek seçilebilir"})
+		return
+	}
+	validOptions := make(map[uint]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		validOptions[option.ID] = true
+	}
+	choices := make([]models.PollVoteOption, 0, len(optionIDs))
+	for _, optionID := range optionIDs {
+		if !validOptions[optionID] {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz anket seçeneği"})
+			return
+		}
+		choices = append(choices, models.PollVoteOption{OptionID: optionID})
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Benzersiz indeks eşzamanlı isteklerde de tek oy kaydı oluşmasını sağlar
+		vote := models.PollVote{PollID: poll.ID, UserID: userID, CreatedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("Options").Create(&vote)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errPollAlreadyVoted
+		}
+
+		// Süre kontrolü güncellemeyle birlikte yapılır; kapanış anına denk gelen oylar sayılmaz
+		result = tx.Model(&models.Poll{}).
+			Where("id = ? AND closed_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", poll.ID, time.Now()).
+			UpdateColumn(voterCountColumn, gorm.Expr("COALESCE("+voterCountColumn+", 0) + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errPollClosed
+		}
+		for i := range choices {
+			choices[i].VoteID = vote.ID
+		}
+		if err := tx.Create(&choices).Error; err != nil {
+			return err
+		}
+		return adjustCounter(tx, &models.PollOption{}, voteCountColumn, 1, optionIDs...)
+	})
+	if errors.Is(err, errPollAlreadyVoted) {
+		c.JSON(http.StatusConflict, Response{Success: false, Message: err.Error()})
+		return
+	}
+	if errors.Is(err, errPollClosed) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Oy kaydedilemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Oyunuz kaydedildi",
+		Data:    gin.H{"poll": postPoll(userID, post)},
+	})
+}
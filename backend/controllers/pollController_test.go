@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createTestPoll verilen kullanıcı için yayınlanmış, anketli bir gönderi oluşturur ve anketi döndürür
+func createTestPoll(t *testing.T, userID uint, multipleChoice bool, options ...string) (models.Post, models.Poll) {
+	t.Helper()
+	post := models.Post{UserID: userID, Content: "Hangisi?", HasPoll: true, PublishStatus: models.PublishStatusPublished}
+	if err := database.DB.Create(&post).Error; err != nil {
+		t.Fatalf("Gönderi oluşturulamadı: %v", err)
+	}
+	request := pollRequest{Options: options, MultipleChoice: multipleChoice}
+	if err := validatePollRequest(&request); err != nil {
+		t.Fatalf("Anket geçersiz: %v", err)
+	}
+	if err := createPoll(database.DB, post, request); err != nil {
+		t.Fatalf("Anket oluşturulamadı: %v", err)
+	}
+	var poll models.Poll
+	database.DB.Preload("Options").Where("post_id = ?", post.ID).First(&poll)
+	return post, poll
+}
+
+// votePoll kullanıcı adına verilen seçeneklere oy verir ve yanıt durumunu döndürür
+func votePoll(userID, postID uint, optionIDs ...uint) int {
+	ids := make([]string, 0, len(optionIDs))
+	for _, id := range optionIDs {
+		ids = append(ids, fmt.Sprint(id))
+	}
+	router := gin.New()
+	router.POST("/posts/:id/poll/vote", func(c *gin.Context) { c.Set("userID", userID) }, VotePoll)
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/posts/%d/poll/vote", postID),
+		strings.NewReader(`{"optionIds":[`+strings.Join(ids, ",")+`]}`))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+// pollTallies seçeneklerin oy sayılarını ve anketin oy veren sayısını okur
+func pollTallies(pollID uint) (map[uint]int, int) {
+	var options []models.PollOption
+	database.DB.Where("poll_id = ?", pollID).Find(&options)
+	tallies := make(map[uint]int, len(options))
+	for _, option := range options {
+		tallies[option.ID] = option.VoteCount
+	}
+	var poll models.Poll
+	database.DB.First(&poll, pollID)
+	return tallies, poll.VoterCount
+}
+
+func TestVotePollChoiceLimits(t *testing.T) {
+	setupTestDatabase(t)
+	gin.SetMode(gin.TestMode)
+	owner := createTestUser(t, "anketci")
+	voter := createTestUser(t, "secmen")
+
+	single, singlePoll := createTestPoll(t, owner.ID, false, "Çay", "Kahve", "Su")
+	multi, multiPoll := createTestPoll(t, owner.ID, true, "Kırmızı", "Mavi", "Yeşil")
+	a, b := singlePoll.Options[0].ID, singlePoll.Options[1].ID
+	x, y := multiPoll.Options[0].ID, multiPoll.Options[1].ID
+
+	tests := []struct {
+		name    string
+		postID  uint
+		options []uint
+		want    int
+	}{
+		{"seçenek yok", single.ID, nil, http.StatusBadRequest},
+		{"tek seçimli ankette iki seçenek", single.ID, []uint{a, b}, http.StatusBadRequest},
+		{"başka anketin seçeneği", single.ID, []uint{x}, http.StatusBadRequest},
+		{"tek seçimli ankette tekrarlanan seçenek tek sayılır", single.ID, []uint{a, a}, http.StatusOK},
+		{"ikinci oy reddedilir", single.ID, []uint{b}, http.StatusConflict},
+		{"çoklu seçimli ankette iki seçenek", multi.ID, []uint{x, y}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if got := votePoll(voter.ID, tt.postID, tt.options...); got != tt.want {
+			t.Errorf("%s: durum %d, beklenen %d", tt.name, got, tt.want)
+		}
+	}
+
+	tallies, voters := pollTallies(singlePoll.ID)
+	if tallies[a] != 1 || tallies[b] != 0 || voters != 1 {
+		t.Errorf("tek seçimli anket sonuçları %v, %d oy veren", tallies, voters)
+	}
+	tallies, voters = pollTallies(multiPoll.ID)
+	if tallies[x] != 1 || tallies[y] != 1 || voters != 1 {
+		t.Errorf("çoklu seçimli anket sonuçları %v, %d oy veren", tallies, voters)
+	}
+	if choices := postPoll(voter.ID, multi)["myChoices"].([]uint); len(choices) != 2 {
+		t.Errorf("kullanıcının seçimleri %v, iki seçenek beklenirdi", choices)
+	}
+}
+
+func TestVotePollConcurrentVotes(t *testing.T) {
+	setupTestDatabase(t)
+	gin.SetMode(gin.TestMode)
+	owner := createTestUser(t, "anketci")
+	post, poll := createTestPoll(t, owner.ID, true, "A", "B")
+	optionA, optionB := poll.Options[0].ID, poll.Options[1].ID
+
+	voters := make([]models.User, 8)
+	for i := range voters {
+		voters[i] = createTestUser(t, fmt.Sprintf("secmen%d", i))
+	}
+
+	// Her kullanıcı aynı oyu eşzamanlı olarak üç kez göndermeye çalışır
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	statuses := make(map[int]int)
+	for _, voter := range voters {
+		for attempt := 0; attempt < 3; attempt++ {
+			wg.Add(1)
+			go func(userID uint) {
+				defer wg.Done()
+				status := votePoll(userID, post.ID, optionA, optionB)
+				mu.Lock()
+				statuses[status]++
+				mu.Unlock()
+			}(voter.ID)
+		}
+	}
+	wg.Wait()
+
+	if statuses[http.StatusOK] != len(voters) {
+		t.Errorf("yanıt durumları %v, her kullanıcı için bir başarılı oy beklenirdi", statuses)
+	}
+	tallies, voterCount := pollTallies(poll.ID)
+	if voterCount != len(voters) || tallies[optionA] != len(voters) || tallies[optionB] != len(voters) {
+		t.Errorf("sonuçlar %v, %d oy veren; her biri %d olmalı", tallies, voterCount, len(voters))
+	}
+	var selections int64
+	database.DB.Model(&models.PollVoteOption{}).Count(&selections)
+	if selections != int64(2*len(voters)) {
+		t.Errorf("%d seçim kaydı, beklenen %d", selections, 2*len(voters))
+	}
+}
+
+func TestEditPostKeepsPollQuestionAfterVotes(t *testing.T) {
+	setupTestDatabase(t)
+	gin.SetMode(gin.TestMode)
+	owner := createTestUser(t, "anketci")
+	voter := createTestUser(t, "secmen")
+	post, poll := createTestPoll(t, owner.ID, false, "Evet", "Hayır")
+
+	edit := func(body string) int {
+		router := gin.New()
+		router.PUT("/posts/:id", func(c *gin.Context) { c.Set("userID", owner.ID) }, EditPost)
+		request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/posts/%d", post.ID), strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	if code := edit(`{"content":"Hangisi? (düzeltme)"}`); code != http.StatusOK {
+		t.Fatalf("oy yokken soru düzenlenemedi: durum %d", code)
+	}
+	if code := votePoll(voter.ID, post.ID, poll.Options[0].ID); code != http.StatusOK {
+		t.Fatalf("oy verilemedi: durum %d", code)
+	}
+	if code := edit(`{"content":"Başka bir soru"}`); code != http.StatusConflict {
+		t.Errorf("oy verilmiş anketin sorusu düzenlendi: durum %d, beklenen %d", code, http.StatusConflict)
+	}
+	if code := edit(`{"caption":"açıklama"}`); code != http.StatusOK {
+		t.Errorf("soru değişmeden yapılan düzenleme reddedildi: durum %d", code)
+	}
+
+	var stored models.Post
+	database.DB.First(&stored, post.ID)
+	if stored.Content != "Hangisi? (düzeltme)" {
+		t.Errorf("gönderi metni %q, oy sonrası değişmemeli", stored.Content)
+	}
+}
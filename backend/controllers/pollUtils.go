@@ -0,0 +1,291 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// pollCloseInterval süresi dolan anketlerin kontrol edilme sıklığı
+const pollCloseInterval = time.Minute
+
+// defaultPollDuration süre belirtilmeyen anketlerin açık kalma süresi
+const defaultPollDuration = 24 * time.Hour
+
+// errPollClosed oy verilmek istenen anketin süresi dolduğunda döner
+var errPollClosed = errors.New("Anket sona erdi")
+
+// errPollQuestionLocked oy almış anketin sorusu (gönderi metni) değiştirilmek istendiğinde döner
+var errPollQuestionLocked = errors.New("Oy verilmiş bir anketin sorusu değiştirilemez")
+
+// pollRequest gönderi oluşturulurken eklenen anket
+type pollRequest struct {
+	Options         []string `json:"options"`
+	MultipleChoice  bool     `json:"multipleChoice"`
+	DurationMinutes int      `json:"durationMinutes"` // Boşsa 24 saat
+}
+
+// validatePollRequest seçenekleri temizler ve anket sınırlarını doğrular
+func validatePollRequest(request *pollRequest) error {
+	seen := make(map[string]bool, len(request.Options))
+	options := make([]string, 0, len(request.Options))
+	for _, option := range request.Options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return errors.New("Anket seçenekleri boş olamaz")
+		}
+		if len([]rune(option)) > 100 {
+			return errors.New("Anket seçenekleri en fazla 100 karakter olabilir")
+		}
+		key := strings.ToLower(option)
+		if seen[key] {
+			return errors.New("Anket seçenekleri birbirinden farklı olmalı")
+		}
+		seen[key] = true
+		options = append(options, option)
+	}
+	if len(options) < models.PollMinOptions || len(options) > models.PollMaxOptions {
+		return fmt.Errorf("Anket %d ile %d arasında seçenek içermeli", models.PollMinOptions, models.PollMaxOptions)
+	}
+	request.Options = options
+
+	if request.DurationMinutes == 0 {
+		request.DurationMinutes = int(defaultPollDuration / time.Minute)
+	}
+	duration := time.Duration(request.DurationMinutes) * time.Minute
+	if duration < models.PollMinDuration || duration > models.PollMaxDuration {
+		return fmt.Errorf("Anket süresi %d dakika ile %d gün arasında olmalı",
+			int(models.PollMinDuration/time.Minute), int(models.PollMaxDuration/(24*time.Hour)))
+	}
+	return nil
+}
+
+// createPoll gönderinin anketini seçenekleriyle birlikte oluşturur. Süre sadece yayınlanmış
+// gönderilerde hemen başlar; diğerlerinde startPollClock ile yayın anında başlatılır.
+func createPoll(tx *gorm.DB, post models.Post, request pollRequest) error {
+	poll := models.Poll{
+		PostID:          post.ID,
+		MultipleChoice:  request.MultipleChoice,
+		DurationMinutes: request.DurationMinutes,
+	}
+	if isPublished(post.PublishStatus) {
+		expiresAt := time.Now().Add(time.Duration(request.DurationMinutes) * time.Minute)
+		poll.ExpiresAt = &expiresAt
+	}
+	for i, option := range request.Options {
+		poll.Options = append(poll.Options, models.PollOption{Text: option, Position: i})
+	}
+	return tx.Create(&poll).Error
+}
+
+// startPollClock taslak veya zamanlanmış gönderi yayınlandığında anketin süresini başlatır
+func startPollClock(db *gorm.DB, postID uint, publishedAt time.Time) error {
+	var poll models.Poll
+	if err := db.Where("post_id = ? AND expires_at IS NULL", postID).First(&poll).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	expiresAt := publishedAt.Add(time.Duration(poll.DurationMinutes) * time.Minute)
+	return db.Model(&poll).Where("expires_at IS NULL").Update("expires_at", expiresAt).Error
+}
+
+// isPollClosed anketin süresinin dolup dolmadığını belirler (kapanış bildirimi henüz işlenmemiş olsa bile)
+func isPollClosed(poll models.Poll, now time.Time) bool {
+	return poll.ClosedAt != nil || (poll.ExpiresAt != nil && !now.Before(*poll.ExpiresAt))
+}
+
+// pollVoteOptionIDs oyda seçilen seçeneklerin kimliklerini döndürür
+func pollVoteOptionIDs(voteID uint) []uint {
+	optionIDs := []uint{}
+	database.DB.Model(&models.PollVoteOption{}).Where("vote_id = ?", voteID).Order("option_id").Pluck("option_id", &optionIDs)
+	return optionIDs
+}
+
+// pollHasVotes gönderideki ankete oy verilip verilmediğini kontrol eder
+func pollHasVotes(db *gorm.DB, postID uint) bool {
+	var count int64
+	db.Model(&models.PollVote{}).
+		Where("poll_id IN (SELECT id FROM polls WHERE post_id = ?)", postID).
+		Limit(1).Count(&count)
+	return count > 0
+}
+
+// postPoll gönderideki anketi izleyiciye göre döndürür (anket yoksa nil). Sonuçlar izleyici oy verene
+// veya anket kapanana kadar gizlenir; gönderi sahibi sonuçları her zaman görür.
+func postPoll(viewerID uint, post models.Post) gin.H {
+	if !post.HasPoll {
+		return nil
+	}
+	var poll models.Poll
+	if err := database.DB.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, id")
+	}).Where("post_id = ?", post.ID).First(&poll).Error; err != nil {
+		return nil
+	}
+
+	var vote models.PollVote
+	voted := viewerID != 0 && database.DB.Where("poll_id = ? AND user_id = ?", poll.ID, viewerID).
+		First(&vote).Error == nil
+	myChoices := []uint{}
+	if voted {
+		myChoices = pollVoteOptionIDs(vote.ID)
+	}
+	closed := isPollClosed(poll, time.Now())
+	resultsVisible := voted || closed || viewerID == post.UserID
+
+	// Çoklu seçimli anketlerde yüzdeler oy veren kullanıcı sayısına göre hesaplanır
+	totalVotes := 0
+	for _, option := range poll.Options {
+		totalVotes += option.VoteCount
+	}
+	if poll.MultipleChoice {
+		totalVotes = poll.VoterCount
+	}
+	options := make([]gin.H, 0, len(poll.Options))
+	for _, option := range poll.Options {
+		item := gin.H{"id": option.ID, "text": option.Text}
+		if resultsVisible {
+			percentage := 0.0
+			if totalVotes > 0 {
+				percentage = float64(option.VoteCount) * 100 / float64(totalVotes)
+			}
+			item["votes"] = option.VoteCount
+			item["percentage"] = percentage
+		}
+		options = append(options, item)
+	}
+
+	return gin.H{
+		"id":              poll.ID,
+		"options":         options,
+		"multipleChoice":  poll.MultipleChoice,
+		"durationMinutes": poll.DurationMinutes,
+		"expiresAt":       poll.ExpiresAt,
+		"closed":          closed,
+		"voterCount":      poll.VoterCount,
+		"voted":           voted,
+		"myChoices":       myChoices,
+		"resultsVisible":  resultsVisible,
+	}
+}
+
+// deletePostPoll silinen gönderinin anketini, seçeneklerini ve oylarını siler
+func deletePostPoll(tx *gorm.DB, postID uint) error {
+	var pollIDs []uint
+	if err := tx.Model(&models.Poll{}).Where("post_id = ?", postID).Pluck("id", &pollIDs).Error; err != nil {
+		return err
+	}
+	if len(pollIDs) == 0 {
+		return nil
+	}
+	if err := tx.Where("vote_id IN (SELECT id FROM poll_votes WHERE poll_id IN ?)", pollIDs).Delete(&models.PollVoteOption{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("poll_id IN ?", pollIDs).Delete(&models.PollVote{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("poll_id IN ?", pollIDs).Delete(&models.PollOption{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", pollIDs).Delete(&models.Poll{}).Error
+}
+
+// StartPollCloser süresi dolan anketleri kapatıp sahiplerine bildiren zamanlayıcıyı başlatır
+func StartPollCloser() {
+	go func() {
+		ticker := time.NewTicker(pollCloseInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if closed, err := CloseExpiredPolls(context.Background()); err != nil {
+				fmt.Printf("Anket kapatma hatası: %v\n", err)
+			} else if closed > 0 {
+				fmt.Printf("%d anket kapatıldı\n", closed)
+			}
+		}
+	}()
+}
+
+// CloseExpiredPolls süresi dolmuş anketleri kapatır ve gönderi sahibine bildirim gönderir.
+// Kapanış koşullu güncellemeyle işaretlendiğinden her anket için tek bir bildirim gönderilir.
+func CloseExpiredPolls(ctx context.Context) (int, error) {
+	now := time.Now()
+	var polls []models.Poll
+	if err := database.DB.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, id")
+	}).Where("closed_at IS NULL AND expires_at <= ?", now).Find(&polls).Error; err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, poll := range polls {
+		result := database.DB.Model(&models.Poll{}).Where("id = ? AND closed_at IS NULL", poll.ID).
+			Update("closed_at", now)
+		if result.Error != nil {
+			log.Printf("Anket kapatılamadı (ID: %d): %v", poll.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		closed++
+
+		var post models.Post
+		if err := database.DB.Select("id, user_id").First(&post, poll.PostID).Error; err != nil {
+			continue
+		}
+		notifyPollClosed(ctx, post, poll)
+	}
+	return closed, nil
+}
+
+// notifyPollClosed gönderi sahibine anketin sona erdiğini ve en çok oy alan seçeneği bildirir
+func notifyPollClosed(ctx context.Context, post models.Post, poll models.Poll) {
+	message := "Anketin sona erdi"
+	var leader *models.PollOption
+	tied := false
+	for i := range poll.Options {
+		option := &poll.Options[i]
+		if leader == nil || option.VoteCount > leader.VoteCount {
+			leader = option
+			tied = false
+		} else if option.VoteCount == leader.VoteCount {
+			tied = true
+		}
+	}
+	if leader != nil && leader.VoteCount > 0 && !tied {
+		message = fmt.Sprintf("Anketin sona erdi: \"%s\" %d oyla önde", leader.Text, leader.VoteCount)
+	}
+
+	notification := models.Notification{
+		ToUserID:   post.UserID,
+		FromUserID: post.UserID,
+		Type:       "poll_closed",
+		Message:    message,
+		IsRead:     false,
+		CreatedAt:  time.Now(),
+	}
+	if err := database.DB.Create(&notification).Error; err != nil {
+		log.Printf("Anket bildirimi oluşturulamadı: %v", err)
+		return
+	}
+
+	if notifService != nil {
+		if err := notifService.CreatePollClosedNotification(ctx,
+			fmt.Sprintf("%d", post.UserID),
+			fmt.Sprintf("%d", post.ID),
+			message,
+		); err != nil {
+			log.Printf("WebSocket anket bildirimi gönderilemedi: %v", err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findOwnPost isteği yapan kullanıcının gönderisini bulur; gönderi yoksa veya başkasına aitse yanıtı yazar
+func findOwnPost(c *gin.Context) (models.Post, bool) {
+	userID := c.GetUint("userID")
+
+	var post models.Post
+	if err := database.DB.First(&post, c.Param("id")).Error; err != nil || !CanViewPost(userID, post) {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Gönderi bulunamadı"})
+		return post, false
+	}
+	if post.UserID != userID {
+		c.JSON(http.StatusForbidden, Response{Success: false, Message: "Bu gönderi üzerinde işlem yapma yetkiniz yok"})
+		return post, false
+	}
+	return post, true
+}
+
+// ArchivePost - Gönderiyi arşivler; arşivlenen gönderiyi sadece sahibi görür ve sabitlemesi kaldırılır
+func ArchivePost(c *gin.Context) {
+	post, ok := findOwnPost(c)
+	if !ok {
+		return
+	}
+	if !isPublished(post.PublishStatus) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Yayınlanmamış gönderiler arşivlenemez"})
+		return
+	}
+	if post.ArchivedAt != nil {
+		c.JSON(http.StatusOK, Response{
+			Success: true,
+			Message: "Gönderi zaten arşivde",
+			Data:    gin.H{"id": post.ID, "archived": true, "archivedAt": post.ArchivedAt},
+		})
+		return
+	}
+
+	now := time.Now()
+	if err := database.DB.Model(&post).Updates(map[string]interface{}{"archived_at": now, "pinned_at": nil}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Gönderi arşivlenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Gönderi arşivlendi",
+		Data:    gin.H{"id": post.ID, "archived": true, "archivedAt": now},
+	})
+}
+
+// RestorePost - Arşivlenen gönderiyi profile ve akışlara geri yükler
+func RestorePost(c *gin.Context) {
+	post, ok := findOwnPost(c)
+	if !ok {
+		return
+	}
+	if post.ArchivedAt == nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Gönderi arşivde değil"})
+		return
+	}
+
+	if err := database.DB.Model(&post).Update("archived_at", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Gönderi geri yüklenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Gönderi arşivden geri yüklendi",
+		Data:    gin.H{"id": post.ID, "archived": false},
+	})
+}
+
+// GetArchivedPosts - Kullanıcının arşivlediği gönderileri en son arşivlenen önde olacak şekilde listeler
+func GetArchivedPosts(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	var posts []models.Post
+	if err := database.DB.Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("user_id = ? AND archived_at IS NOT NULL", userID).
+		Order("archived_at DESC").Offset((page - 1) * limit).Limit(limit + 1).
+		Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Arşiv alınamadı"})
+		return
+	}
+
+	hasMore := len(posts) > limit
+	if hasMore {
+		posts = posts[:limit]
+	}
+
+	postsResponse := []gin.H{}
+	for _, post := range posts {
+		postsResponse = append(postsResponse, gin.H{
+			"id":         post.ID,
+			"content":    post.Content,
+			"caption":    post.Caption,
+			"tags":       splitTagsString(post.TagsString),
+			"images":     postImageURLs(post),
+			"likes":      post.LikeCount,
+			"comments":   post.CommentCount,
+			"shares":     post.ShareCount,
+			"repostOf":   repostReference(userID, post),
+			"poll":       postPoll(userID, post),
+			"audience":   post.Audience,
+			"createdAt":  formatTimeAgo(post.CreatedAt),
+			"archivedAt": post.ArchivedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Data: gin.H{
+			"posts":   postsResponse,
+			"page":    page,
+			"limit":   limit,
+			"hasMore": hasMore,
+		},
+	})
+}
+
+// PinPost - Gönderiyi profilin üstüne sabitler (en fazla models.MaxPinnedPosts gönderi)
+func PinPost(c *gin.Context) {
+	post, ok := findOwnPost(c)
+	if !ok {
+		return
+	}
+	if !isPublished(post.PublishStatus) || post.ArchivedAt != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Sadece profilde görünen gönderiler sabitlenebilir"})
+		return
+	}
+	if post.PinnedAt != nil {
+		c.JSON(http.StatusOK, Response{
+			Success: true,
+			Message: "Gönderi zaten sabitlenmiş",
+			Data:    gin.H{"id": post.ID, "pinned": true, "pinnedAt": post.PinnedAt},
+		})
+		return
+	}
+
+	// Sınır kontrolü güncellemeyle aynı sorguda yapılır; eşzamanlı isteklerde de sınır aşılmaz
+	now := time.Now()
+	result := database.DB.Model(&post).
+		Where("pinned_at IS NULL AND (SELECT COUNT(*) FROM posts AS pinned WHERE pinned.user_id = ? AND pinned.pinned_at IS NOT NULL AND pinned.deleted_at IS NULL) < ?",
+			post.UserID, models.MaxPinnedPosts).
+		Update("pinned_at", now)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Gönderi sabitlenemedi: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: fmt.Sprintf("En fazla %d gönderi sabitlenebilir", models.MaxPinnedPosts),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Gönderi profile sabitlendi",
+		Data:    gin.H{"id": post.ID, "pinned": true, "pinnedAt": now},
+	})
+}
+
+// UnpinPost - Gönderinin profildeki sabitlemesini kaldırır
+func UnpinPost(c *gin.Context) {
+	post, ok := findOwnPost(c)
+	if !ok {
+		return
+	}
+	if post.PinnedAt == nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Gönderi sabitlenmemiş"})
+		return
+	}
+
+	if err := database.DB.Model(&post).Update("pinned_at", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Sabitleme kaldırılamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Gönderinin sabitlemesi kaldırıldı",
+		Data:    gin.H{"id": post.ID, "pinned": false},
+	})
+}
@@ -1,14 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"path/filepath"
 	"social-media-app/backend/database"
 	"social-media-app/backend/models"
-	"social-media-app/backend/services"
+	"social-media-app/backend/utils"
 	"sort"
 	"strconv"
 	"strings"
@@ -95,7 +94,11 @@ func GetPosts(c *gin.Context) {
 		query = database.DB.Order("created_at DESC")
 	}
 
-	// Gönderi verilerini yükle - Images ve User ilişkilerini de preload et
+	// Taslaklar akışta yer almaz, yakın arkadaşlara özel gönderiler sahibine ve listedekilere gösterilir
+	visibilitySQL, visibilityArgs := contentVisibilityClause("posts", c.GetUint("userID"))
+	query = query.Where(visibilitySQL, visibilityArgs...)
+
+	// Gönderi verilerini yükle - Images ve User ilişkilerini de preload et
 	result := query.Preload("User").Preload("Images").Find(&posts)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, Response{
@@ -143,8 +146,9 @@ func GetPosts(c *gin.Context) {
 		}
 	}
 
-	// Yanıtı hazırla
+	// Yanıtı hazırla; sayfadaki mention'lar tek sorguda çözümlenir
 	responsePosts := make([]map[string]interface{}, 0)
+	mentionIDs := mentionUserIDs(postTexts(posts)...)
 
 	for _, post := range posts {
 		// Kullanıcının bu gönderiyi beğenip beğenmediğini kontrol et
@@ -167,16 +171,24 @@ func GetPosts(c *gin.Context) {
 
 		// Gönderi yanıtını oluştur
 		responsePost := map[string]interface{}{
-			"id":        post.ID,
-			"content":   post.Content,
-			"caption":   post.Caption,
-			"tags":      strings.Split(post.TagsString, ","),
-			"likes":     post.LikeCount,
-			"comments":  post.CommentCount,
-			"createdAt": formatTimeAgo(post.CreatedAt),
-			"liked":     likedCount > 0,
-			"saved":     savedCount > 0,
-			"images":    imageURLs,
+			"id":              post.ID,
+			"content":         post.Content,
+			"caption":         post.Caption,
+			"contentEntities": textEntities(post.Content, mentionIDs),
+			"captionEntities": textEntities(post.Caption, mentionIDs),
+			"tags":            strings.Split(post.TagsString, ","),
+			"likes":           post.LikeCount,
+			"comments":        post.CommentCount,
+			"shares":          post.ShareCount,
+			"repostOf":        repostReference(c.GetUint("userID"), post),
+			"poll":            postPoll(c.GetUint("userID"), post),
+			"createdAt":       formatTimeAgo(post.CreatedAt),
+			"liked":           likedCount > 0,
+			"saved":           savedCount > 0,
+			"images":          imageURLs,
+			"audience":        post.Audience,
+			"edited":          post.EditedAt != nil,
+			"editedAt":        post.EditedAt,
 			"user": map[string]interface{}{
 				"id":           post.User.ID,
 				"username":     post.User.Username,
@@ -211,12 +223,21 @@ func CreatePost(c *gin.Context) {
 	userID, _ := c.Get("userID")
 
 	var request struct {
-		Content        string   `json:"content"`
-		Caption        string   `json:"caption"` // Başlık alanı
-		Tags           string   `json:"tags"`    // Virgülle ayrılmış etiketler
-		Images         []string `json:"images"`
-		ImageUrl       string   `json:"imageUrl"`       // Cloudinary'den gelen tek URL için
-		GeminiResponse string   `json:"geminiResponse"` // Gemini'den gelen etiketler
+		Content  string   `json:"content"`
+		Caption  string   `json:"caption"` // Başlık alanı
+		Tags     string   `json:"tags"`    // Virgülle ayrılmış etiketler
+		Images   []string `json:"images"`
+		ImageUrl string   `json:"imageUrl"` // Cloudinary'den gelen tek URL için
+		// Devam ettirilebilir yükleme ile tamamlanmış görsellerin kimlikleri
+		UploadIDs []string `json:"uploadIds"`
+		Audience  string   `json:"audience"` // everyone (varsayılan), close_friends
+		// Gönderiye özel yorum izni (all, followers, none); boşsa hesap ayarı geçerli
+		CommentPermission string `json:"commentPermission"`
+		// Taslak olarak kaydet veya ileri bir zamanda yayınla (ikisi de verilmezse hemen yayınlanır)
+		Draft       bool       `json:"draft"`
+		ScheduledAt *time.Time `json:"scheduledAt"`
+		// İsteğe bağlı anket; gönderi metni anket sorusu olarak kullanılır
+		Poll *pollRequest `json:"poll"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -226,6 +247,71 @@ func CreatePost(c *gin.Context) {
 		})
 		return
 	}
+	if request.Poll != nil {
+		if strings.TrimSpace(request.Content) == "" {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Message: "Anketli gönderilerde soru olarak gönderi metni gereklidir",
+			})
+			return
+		}
+		if err := validatePollRequest(request.Poll); err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	if request.Audience == "" {
+		request.Audience = models.AudienceEveryone
+	}
+	if request.Audience != models.AudienceEveryone && request.Audience != models.AudienceCloseFriends {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Kitle everyone veya close_friends olmalı",
+		})
+		return
+	}
+	if request.CommentPermission != "" && !isValidCommentPermission(request.CommentPermission) {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Yorum izni all, followers veya none olmalı",
+		})
+		return
+	}
+	publishStatus, scheduledAt, err := resolvePublishStatus(request.Draft, request.ScheduledAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Yüklemeleri görsel URL'lerine çevir; gönderi oluşturulamazsa yüklemeler serbest bırakılır
+	var consumedUploads []*models.UploadSession
+	committed := false
+	defer func() {
+		if !committed {
+			for _, session := range consumedUploads {
+				releaseUpload(session)
+			}
+		}
+	}()
+	for _, uploadID := range request.UploadIDs {
+		session, err := consumeUpload(userID.(uint), uploadID, "image")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Message: "Yüklenen görsel kullanılamıyor: " + err.Error(),
+			})
+			return
+		}
+		consumedUploads = append(consumedUploads, session)
+		request.Images = append(request.Images, session.URL)
+	}
 
 	// En az bir içerik veya görsel olmalı
 	if request.Content == "" && len(request.Images) == 0 && request.ImageUrl == "" {
@@ -238,12 +324,17 @@ func CreatePost(c *gin.Context) {
 
 	// Yeni gönderi oluştur
 	post := models.Post{
-		UserID:     userID.(uint),
-		Content:    request.Content,
-		Caption:    request.Caption,
-		TagsString: request.Tags, // Veritabanında string olarak saklıyoruz
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		UserID:            userID.(uint),
+		Content:           request.Content,
+		Caption:           request.Caption,
+		TagsString:        request.Tags, // Veritabanında string olarak saklıyoruz
+		Audience:          request.Audience,
+		CreatedAt:         time.Now(),
+		CommentPermission: request.CommentPermission,
+		PublishStatus:     publishStatus,
+		ScheduledAt:       scheduledAt,
+		HasPoll:           request.Poll != nil,
+		UpdatedAt:         time.Now(),
 	}
 
 	// Tags alanını doldur - view'da kullanmak için
@@ -268,67 +359,26 @@ func CreatePost(c *gin.Context) {
 		return
 	}
 
-	// Gemini yanıtını işle ve etiketleri ekle
-	if request.GeminiResponse != "" {
-		// Virgülle ayrılmış etiketleri parçala
-		geminiTags := strings.Split(request.GeminiResponse, ",")
-
-		// Etiket sayısını kontrol et ve maksimum 6 etiket kullan
-		tagCount := len(geminiTags)
-		if tagCount > 6 {
-			tagCount = 6
-		}
-
-		// Ana ve yardımcı etiket sayıları
-		primaryTagCount := 4
-		if tagCount < 4 {
-			primaryTagCount = tagCount
+	// Anketi seçenekleriyle birlikte kaydet
+	if request.Poll != nil {
+		if err := createPoll(tx, post, *request.Poll); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, Response{
+				Success: false,
+				Message: "Anket oluşturulurken bir hata oluştu: " + err.Error(),
+			})
+			return
 		}
+	}
 
-		for i := 0; i < tagCount; i++ {
-			tagName := strings.TrimSpace(geminiTags[i])
-			if tagName == "" {
-				continue // Boş etiketleri atla
-			}
-
-			// Etiket türünü belirle
-			tagType := "primary"
-			if i >= primaryTagCount {
-				tagType = "auxiliary"
-			}
-
-			// Etiket var mı kontrol et, yoksa oluştur (findOrCreate mantığı)
-			var tag models.Tag
-			if err := tx.Where("name = ?", tagName).FirstOrCreate(&tag, models.Tag{
-				Name:      tagName,
-				Type:      tagType, // Etiket türünü kaydet
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
-			}).Error; err != nil {
-				fmt.Printf("Etiket oluşturulurken hata: %v\n", err)
-				continue // Hata olsa bile diğer etiketlerle devam et
-			}
-
-			// Post ve tag arasında ilişki oluştur
-			postTag := models.PostTag{
-				PostID:    post.ID,
-				TagID:     tag.ID,
-				TagType:   tagType, // İlişkide de etiket türünü kaydet
-				CreatedAt: time.Now(),
-			}
-
-			if err := tx.Create(&postTag).Error; err != nil {
-				fmt.Printf("Post-Tag ilişkisi oluşturulurken hata: %v\n", err)
-				// Hata olsa bile diğer etiketlerle devam et
-			}
-		}
+	// Metindeki #hashtag'leri gönderi etiketlerine ekle
+	if hashtags := utils.ExtractHashtags(post.Content, post.Caption); len(hashtags) > 0 {
+		attachPostTags(tx, post.ID, hashtags, "primary")
 
-		// Gemini etiketlerini TagsString'e ekle (eğer daha önce kullanıcı eklemediyse)
-		if post.TagsString == "" {
-			post.TagsString = request.GeminiResponse
-			if err := tx.Model(&post).Update("tags_string", post.TagsString).Error; err != nil {
-				fmt.Printf("TagsString güncellenirken hata: %v\n", err)
-			}
+		post.Tags = mergeTags(splitTagsString(post.TagsString), hashtags)
+		post.TagsString = strings.Join(post.Tags, ",")
+		if err := tx.Model(&post).Update("tags_string", post.TagsString).Error; err != nil {
+			fmt.Printf("TagsString güncellenirken hata: %v\n", err)
 		}
 	}
 
@@ -381,6 +431,9 @@ func CreatePost(c *gin.Context) {
 		imageURLs = append(imageURLs, imageURL)
 	}
 
+	// Görsel dosyalarını gönderiye bağla
+	addMediaReferences(tx, models.MediaRefPost, post.ID, imageURLs...)
+
 	// Transaction'ı commit et
 	if err := tx.Commit().Error; err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
@@ -389,81 +442,51 @@ func CreatePost(c *gin.Context) {
 		})
 		return
 	}
+	committed = true
 
 	// Kullanıcı bilgisini al
 	var user models.User
 	database.DB.First(&user, userID)
 
-	// Gönderi oluşturulduğunda takipçilere bildirim gönder
-	type Follower struct {
-		ID uint
+	// Yapay zeka etiketlerini sunucu tarafında arka planda üret
+	tagPostAsync(post, imageURLs)
+
+	// Bahsedilen kullanıcılara ve takipçilere bildirim gönderilir; taslak ve zamanlanmış gönderilerde
+	// bildirimler gönderi yayınlandığında gönderilir
+	message := "Gönderi başarıyla oluşturuldu"
+	switch post.PublishStatus {
+	case models.PublishStatusDraft:
+		message = "Gönderi taslak olarak kaydedildi"
+	case models.PublishStatusScheduled:
+		message = "Gönderi zamanlandı"
+	default:
+		announcePost(c.Request.Context(), user, post)
 	}
-	var followers []Follower
-
-	// Kullanıcının takipçilerini bul
-	if err := database.DB.Table("follows").
-		Select("follower_id as id").
-		Where("following_id = ? AND status = ?", userID, "active").
-		Find(&followers).Error; err != nil {
-		fmt.Printf("Takipçiler aranırken hata: %v\n", err)
-		// Hata olsa bile gönderi oluşturma işlemi tamamlanmalı
-	} else {
-		// Bildirimleri oluştur
-		for _, follower := range followers {
-			notification := models.Notification{
-				ToUserID:   follower.ID,   // Takipçiye bildirim gönder
-				FromUserID: userID.(uint), // Gönderiyi oluşturan kullanıcı
-				Type:       "post",
-				Message:    fmt.Sprintf("%s yeni bir gönderi paylaştı", user.FullName),
-				IsRead:     false,
-				CreatedAt:  time.Now(),
-			}
-
-			if err := database.DB.Create(&notification).Error; err != nil {
-				fmt.Printf("Gönderi bildirimi oluşturulurken hata: %v\n", err)
-				// Bildirimin oluşturulamaması gönderi işlemini engellememelidir
-			} else {
-				fmt.Printf("Gönderi bildirimi oluşturuldu. Gönderi %d -> Kullanıcı %d\n", post.ID, follower.ID)
-				// WebSocket üzerinden bildirim gönder (opsiyonel)
-				if notifService != nil {
-					wsNotification := services.Notification{
-						ID:            fmt.Sprintf("%d", notification.ID),
-						UserID:        fmt.Sprintf("%d", notification.ToUserID),
-						ActorID:       fmt.Sprintf("%d", notification.FromUserID),
-						ActorName:     user.FullName,
-						ActorUsername: user.Username,
-						Type:          services.NotificationTypePost,
-						Content:       notification.Message,
-						EntityID:      fmt.Sprintf("%d", post.ID),
-						EntityType:    "post",
-						IsRead:        notification.IsRead,
-						CreatedAt:     notification.CreatedAt,
-					}
 
-					if err := notifService.SendNotification(c.Request.Context(), wsNotification); err != nil {
-						fmt.Printf("WebSocket bildirimi gönderilemedi: %v\n", err)
-					}
-				}
-			}
-		}
-	}
-
-	// Yanıt oluştur
+	// Yanıt oluştur
 	c.JSON(http.StatusCreated, Response{
 		Success: true,
-		Message: "Gönderi başarıyla oluşturuldu",
+		Message: message,
 		Data: map[string]interface{}{
 			"post": map[string]interface{}{
-				"id":        post.ID,
-				"content":   post.Content,
-				"caption":   post.Caption,
-				"tags":      post.Tags, // Burada Tags dizisini doğrudan kullanabiliriz
-				"likes":     0,
-				"comments":  0,
-				"createdAt": "Şimdi",
-				"liked":     false,
-				"saved":     false,
-				"images":    imageURLs,
+				"id":              post.ID,
+				"content":         post.Content,
+				"caption":         post.Caption,
+				"contentEntities": buildTextEntities(post.Content),
+				"captionEntities": buildTextEntities(post.Caption),
+				"tags":            post.Tags, // Burada Tags dizisini doğrudan kullanabiliriz
+				"likes":           0,
+				"comments":        0,
+				"createdAt":       "Şimdi",
+				"liked":           false,
+				"saved":           false,
+				"images":          imageURLs,
+				"audience":        post.Audience,
+				"publishStatus":   post.PublishStatus,
+				"scheduledAt":     post.ScheduledAt,
+				"poll":            postPoll(post.UserID, post),
+				"edited":          post.EditedAt != nil,
+				"editedAt":        post.EditedAt,
 				"user": map[string]interface{}{
 					"id":           user.ID,
 					"username":     user.Username,
@@ -480,15 +503,18 @@ func GetPostById(c *gin.Context) {
 	postID := c.Param("id")
 
 	var post models.Post
-	if err := database.DB.Preload("User").Preload("Images").First(&post, postID).Error; err != nil {
+	if err := database.DB.Preload("User").Preload("Images").First(&post, postID).Error; err != nil || !CanViewPost(c.GetUint("userID"), post) {
 		c.JSON(http.StatusNotFound, Response{
 			Success: false,
-			Message: "Gönderi bulunamadı",
+			Message: "Gönderi bulunamadı",
 		})
 		return
 	}
 
-	// Kullanıcının gönderiyi beğenip beğenmediğini kontrol et
+	// Gönderinin açılması içgörüler için gösterim sayılır
+	recordPostImpressions(c.GetUint("userID"), post)
+
+	// Kullanıcının gönderiyi beğenip beğenmediğini kontrol et
 	var likeCount int64
 	database.DB.Model(&models.Like{}).
 		Where("user_id = ? AND post_id = ?", userID, post.ID).
@@ -510,16 +536,26 @@ func GetPostById(c *gin.Context) {
 		Success: true,
 		Data: map[string]interface{}{
 			"post": map[string]interface{}{
-				"id":        post.ID,
-				"content":   post.Content,
-				"caption":   post.Caption,
-				"tags":      strings.Split(post.TagsString, ","),
-				"likes":     post.LikeCount,
-				"comments":  post.CommentCount,
-				"createdAt": formatTimeAgo(post.CreatedAt),
-				"liked":     likeCount > 0,
-				"saved":     saveCount > 0,
-				"images":    imageURLs,
+				"id":              post.ID,
+				"content":         post.Content,
+				"caption":         post.Caption,
+				"contentEntities": buildTextEntities(post.Content),
+				"captionEntities": buildTextEntities(post.Caption),
+				"tags":            strings.Split(post.TagsString, ","),
+				"likes":           post.LikeCount,
+				"comments":        post.CommentCount,
+				"shares":          post.ShareCount,
+				"repostOf":        repostReference(c.GetUint("userID"), post),
+				"poll":            postPoll(c.GetUint("userID"), post),
+				"createdAt":       formatTimeAgo(post.CreatedAt),
+				"liked":           likeCount > 0,
+				"saved":           saveCount > 0,
+				"images":          imageURLs,
+				"audience":        post.Audience,
+				"archived":        post.ArchivedAt != nil,
+				"pinned":          post.PinnedAt != nil,
+				"edited":          post.EditedAt != nil,
+				"editedAt":        post.EditedAt,
 				"user": map[string]interface{}{
 					"id":           post.User.ID,
 					"username":     post.User.Username,
@@ -576,7 +612,7 @@ func ToggleLike(c *gin.Context) {
 		}
 
 		// Gönderi like count güncelleme
-		if err := tx.Model(&post).Update("like_count", post.LikeCount-1).Error; err != nil {
+		if err := adjustCounter(tx, &models.Post{}, likeCountColumn, -1, post.ID); err != nil {
 			tx.Rollback()
 			fmt.Printf("HATA: Like count güncellenirken hata oluştu: %v\n", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Like count güncellenirken hata oluştu"})
@@ -735,8 +771,15 @@ func ToggleLike(c *gin.Context) {
 			UserID: userID.(uint),
 			PostID: uint(postID),
 		}
-		database.DB.Create(&newLike)
-		database.DB.Model(&post).Update("like_count", post.LikeCount+1)
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&newLike).Error; err != nil {
+				return err
+			}
+			return adjustCounter(tx, &models.Post{}, likeCountColumn, 1, post.ID)
+		}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Beğeni kaydedilirken hata oluştu"})
+			return
+		}
 
 		// Kullanıcı kendi postunu beğenmiyorsa bildirim oluştur
 		if post.UserID != userID.(uint) {
@@ -790,19 +833,28 @@ func UnlikePost(c *gin.Context) {
 		return
 	}
 
-	// Beğeniyi sil
-	result := database.DB.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&models.Like{})
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusBadRequest, Response{
+	// Beğeniyi sil ve gönderi beğeni sayısını aynı transaction içinde azalt
+	var removed int64
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&models.Like{})
+		if result.Error != nil {
+			return result.Error
+		}
+		removed = result.RowsAffected
+		return adjustCounter(tx, &models.Post{}, likeCountColumn, -int(removed), post.ID)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
-			Message: "Bu gönderi beğenilmemiş",
+			Message: "Beğeni kaldırılırken hata oluştu",
 		})
 		return
 	}
-
-	// Gönderi beğeni sayısını azalt
-	if post.LikeCount > 0 {
-		database.DB.Model(&post).Update("like_count", post.LikeCount-1)
+	if removed == 0 {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Bu gönderi beğenilmemiş",
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, Response{
@@ -819,7 +871,7 @@ func SavePost(c *gin.Context) {
 
 	// Gönderiyi kontrol et
 	var post models.Post
-	if err := database.DB.First(&post, postID).Error; err != nil {
+	if err := database.DB.First(&post, postID).Error; err != nil || !CanViewPost(userID.(uint), post) {
 		c.JSON(http.StatusNotFound, Response{
 			Success: false,
 			Message: "Gönderi bulunamadı",
@@ -827,10 +879,36 @@ func SavePost(c *gin.Context) {
 		return
 	}
 
+	// İstenirse gönderi belirli koleksiyonlara da eklenir (her kayıt varsayılan koleksiyona girer)
+	collectionIDs, err := bindCollectionIDs(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Geçersiz istek: " + err.Error(),
+		})
+		return
+	}
+
 	// Kaydı kontrol et (zaten kaydedilmiş mi?)
-	var existingSave models.SavedPost
-	result := database.DB.Where("user_id = ? AND post_id = ?", userID, postID).First(&existingSave)
-	if result.RowsAffected > 0 {
+	var existingCount int64
+	database.DB.Model(&models.SavedPost{}).Where("user_id = ? AND post_id = ?", userID, postID).Count(&existingCount)
+
+	// Kaydı ve koleksiyon kayıtlarını ekle
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return saveContent(tx, userID.(uint), models.SavedItemPost, uint(postID), collectionIDs...)
+	}); err != nil {
+		if errors.Is(err, errCollectionNotFound) {
+			respondCollectionError(c, err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Message: "Gönderi kaydedilirken bir hata oluştu: " + err.Error(),
+		})
+		return
+	}
+
+	if existingCount > 0 {
 		// Zaten kaydedilmiş durumunu başarısız değil, özel bir durum olarak döndür
 		c.JSON(http.StatusOK, Response{
 			Success: true,
@@ -843,21 +921,6 @@ func SavePost(c *gin.Context) {
 		return
 	}
 
-	// Kayıt oluştur - created_at sütunu olmadığı için field list'ten çıkarıyoruz
-	savedPost := models.SavedPost{
-		UserID: userID.(uint),
-		PostID: uint(postID),
-	}
-
-	// Kaydı ekle
-	if err := database.DB.Omit("created_at").Create(&savedPost).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
-			Success: false,
-			Message: "Gönderi kaydedilirken bir hata oluştu: " + err.Error(),
-		})
-		return
-	}
-
 	c.JSON(http.StatusOK, Response{
 		Success: true,
 		Message: "Gönderi başarıyla kaydedildi",
@@ -883,9 +946,20 @@ func UnsavePost(c *gin.Context) {
 		return
 	}
 
-	// Kaydı sil
-	result := database.DB.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&models.SavedPost{})
-	if result.RowsAffected == 0 {
+	// Kaydı ve tüm koleksiyonlardaki kopyalarını sil
+	var removed bool
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		removed, err = unsaveItem(tx, userID.(uint), models.SavedItemPost, uint(postID))
+		return err
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Message: "Gönderi kaydı kaldırılırken bir hata oluştu: " + err.Error(),
+		})
+		return
+	}
+	if !removed {
 		// Kaydedilmemiş gönderi durumunu başarısız değil, özel bir durum olarak döndür
 		c.JSON(http.StatusOK, Response{
 			Success: true,
@@ -927,10 +1001,12 @@ func GetSavedPosts(c *gin.Context) {
 		return
 	}
 
-	// Gönderileri getir
+	// Gönderileri getir (kaydedildikten sonra yakın arkadaş listesinden çıkarılanlar gösterilmez)
+	visibilitySQL, visibilityArgs := contentVisibilityClause("posts", userID.(uint))
 	var posts []models.Post
 	if err := database.DB.Preload("User").Preload("Images").
 		Where("id IN ?", savedPostIDs).
+		Where(visibilitySQL, visibilityArgs...).
 		Order("created_at DESC").
 		Find(&posts).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
@@ -940,8 +1016,9 @@ func GetSavedPosts(c *gin.Context) {
 		return
 	}
 
-	// Yanıtı hazırla
+	// Yanıtı hazırla; sayfadaki mention'lar tek sorguda çözümlenir
 	var responsePosts []map[string]interface{}
+	mentionIDs := mentionUserIDs(postTexts(posts)...)
 	for _, post := range posts {
 		// Kullanıcının bu gönderiyi beğenip beğenmediğini kontrol et
 		var likedCount int64
@@ -957,16 +1034,24 @@ func GetSavedPosts(c *gin.Context) {
 
 		// Gönderi yanıtını oluştur
 		responsePost := map[string]interface{}{
-			"id":        post.ID,
-			"content":   post.Content,
-			"caption":   post.Caption,
-			"tags":      strings.Split(post.TagsString, ","),
-			"likes":     post.LikeCount,
-			"comments":  post.CommentCount,
-			"createdAt": formatTimeAgo(post.CreatedAt),
-			"liked":     likedCount > 0,
-			"saved":     true, // Zaten kaydedilmiş olduğunu biliyoruz
-			"images":    imageURLs,
+			"id":              post.ID,
+			"content":         post.Content,
+			"caption":         post.Caption,
+			"contentEntities": textEntities(post.Content, mentionIDs),
+			"captionEntities": textEntities(post.Caption, mentionIDs),
+			"tags":            strings.Split(post.TagsString, ","),
+			"likes":           post.LikeCount,
+			"comments":        post.CommentCount,
+			"shares":          post.ShareCount,
+			"repostOf":        repostReference(userID.(uint), post),
+			"poll":            postPoll(userID.(uint), post),
+			"createdAt":       formatTimeAgo(post.CreatedAt),
+			"liked":           likedCount > 0,
+			"saved":           true, // Zaten kaydedilmiş olduğunu biliyoruz
+			"images":          imageURLs,
+			"audience":        post.Audience,
+			"edited":          post.EditedAt != nil,
+			"editedAt":        post.EditedAt,
 			"user": map[string]interface{}{
 				"id":           post.User.ID,
 				"username":     post.User.Username,
@@ -1017,47 +1102,63 @@ func DeletePost(c *gin.Context) {
 	// Transaction başlat
 	tx := database.DB.Begin()
 
-	// Görselleri fiziksel olarak sil
-	workDir, err := os.Getwd()
-	if err != nil {
+	// Görsellerin referanslarını kaldır; başka içerikte kullanılmayan dosyalar bekleme süresinden
+	// sonra çöp toplayıcı tarafından depodan silinir
+	removeMediaReferences(tx, models.MediaRefPost, post.ID)
+
+	// Gönderi görsellerini veritabanından sil
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostImage{}).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"message": "Sistem hatası: Çalışma dizini alınamadı",
+			"message": "Gönderi görselleri silinirken bir hata oluştu",
 		})
 		return
 	}
 
-	for _, image := range post.Images {
-		imagePath := filepath.Join(workDir, "uploads", "images", filepath.Base(image.URL))
-		if err := os.Remove(imagePath); err != nil {
-			// Dosya silme hatası olsa bile devam et, sadece loglama yap
-			fmt.Printf("Görsel silinirken hata: %s - %s\n", imagePath, err.Error())
-		}
+	// Gönderi beğenilerini sil
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.Like{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Gönderi beğenileri silinirken bir hata oluştu",
+		})
+		return
 	}
 
-	// Gönderi görsellerini veritabanından sil
-	if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostImage{}).Error; err != nil {
+	// Gönderinin düzenleme geçmişini ve revizyon görsellerini sil
+	var revisionIDs []uint
+	tx.Model(&models.PostRevision{}).Where("post_id = ?", post.ID).Pluck("id", &revisionIDs)
+	for _, revisionID := range revisionIDs {
+		removeMediaReferences(tx, models.MediaRefPostRevision, revisionID)
+	}
+	if err := tx.Where("revision_id IN ?", revisionIDs).Delete(&models.PostRevisionImage{}).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"message": "Gönderi görselleri silinirken bir hata oluştu",
+			"message": "Gönderi düzenleme geçmişi silinirken bir hata oluştu",
 		})
 		return
 	}
-
-	// Gönderi beğenilerini sil
-	if err := tx.Where("post_id = ?", post.ID).Delete(&models.Like{}).Error; err != nil {
+	if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostRevision{}).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"message": "Gönderi beğenileri silinirken bir hata oluştu",
+			"message": "Gönderi düzenleme geçmişi silinirken bir hata oluştu",
 		})
 		return
 	}
 
-	// Gönderi kayıtlarını sil
+	// Gönderi kayıtlarını ve koleksiyonlardaki kopyalarını sil
 	if err := tx.Where("post_id = ?", post.ID).Delete(&models.SavedPost{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Gönderi kayıtları silinirken bir hata oluştu",
+		})
+		return
+	}
+	if err := deleteCollectionItems(tx, models.SavedItemPost, post.ID); err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -1066,17 +1167,58 @@ func DeletePost(c *gin.Context) {
 		return
 	}
 
-	// Gönderi yorumlarını sil
+	// Gönderi yorumlarını sil
 	if err := tx.Where("post_id = ?", post.ID).Delete(&models.Comment{}).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"message": "Gönderi yorumları silinirken bir hata oluştu",
+			"message": "Gönderi yorumları silinirken bir hata oluştu",
+		})
+		return
+	}
+
+	// Gönderinin anketini ve oylarını sil
+	if err := deletePostPoll(tx, post.ID); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Gönderi anketi silinirken bir hata oluştu",
+		})
+		return
+	}
+
+	// Gönderinin etiketlerinin gönderi sayılarını azalt
+	var tagIDs []uint
+	tx.Model(&models.PostTag{}).Where("post_id = ?", post.ID).Pluck("tag_id", &tagIDs)
+	if err := adjustCounter(tx, &models.Tag{}, postCountColumn, -1, tagIDs...); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Etiket sayaçları güncellenirken bir hata oluştu",
+		})
+		return
+	}
+
+	// Gönderi bir yeniden paylaşımsa orijinalin paylaşım kaydını, orijinalse paylaşım kayıtlarını
+	// ve metinsiz yeniden paylaşımlarını sil (alıntılar kalır, orijinal kullanılamıyor olarak görünür)
+	if err := removeRepostShares(tx, post); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Paylaşım kayıtları silinirken bir hata oluştu",
+		})
+		return
+	}
+	if err := deleteContentReposts(tx, models.ContentTypePost, post.ID); err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Yeniden paylaşımlar silinirken bir hata oluştu",
 		})
 		return
 	}
 
-	// Gönderiyi sil
+	// Gönderiyi sil
 	if err := tx.Delete(&post).Error; err != nil {
 		tx.Rollback()
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -0,0 +1,302 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// postEditWindow gönderinin paylaşıldıktan sonra düzenlenebileceği süre
+// (POST_EDIT_WINDOW_MINUTES, 0 veya tanımsız ise süre sınırı yoktur)
+func postEditWindow() time.Duration {
+	return time.Duration(intFromEnv("POST_EDIT_WINDOW_MINUTES", 0)) * time.Minute
+}
+
+// postImageURLs gönderi görsellerinin URL'lerini sırasıyla döndürür
+func postImageURLs(post models.Post) []string {
+	urls := []string{}
+	for _, image := range post.Images {
+		urls = append(urls, image.URL)
+	}
+	return urls
+}
+
+// sameStrings iki listenin aynı sırada aynı elemanlardan oluşup oluşmadığını kontrol eder
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EditPost - Gönderinin metnini, başlığını, etiketlerini ve görsel sırasını düzenler (sadece yazar).
+// Gönderilmeyen alanlar değişmez. Görseller sadece yeniden sıralanabilir veya kaldırılabilir.
+// Düzenlemeden önceki hal revizyon olarak saklanır.
+func EditPost(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz gönderi ID"})
+		return
+	}
+
+	var post models.Post
+	if err := database.DB.Preload("User").Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Gönderi bulunamadı"})
+		return
+	}
+
+	if post.UserID != userID {
+		c.JSON(http.StatusForbidden, Response{Success: false, Message: "Bu gönderiyi düzenleme yetkiniz yok"})
+		return
+	}
+	if isPlainRepost(post) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Yeniden paylaşımlar düzenlenemez"})
+		return
+	}
+
+	// Taslak ve zamanlanmış gönderiler yayınlanana kadar süre sınırı ve düzenleme geçmişi olmadan düzenlenir
+	published := isPublished(post.PublishStatus)
+	if window := postEditWindow(); published && window > 0 && time.Since(post.CreatedAt) > window {
+		c.JSON(http.StatusForbidden, Response{
+			Success: false,
+			Message: fmt.Sprintf("Gönderiler paylaşıldıktan sonra en fazla %d dakika düzenlenebilir", int(window.Minutes())),
+		})
+		return
+	}
+
+	var request struct {
+		Content *string   `json:"content"`
+		Caption *string   `json:"caption"`
+		Tags    *string   `json:"tags"`   // Virgülle ayrılmış etiketler
+		Images  *[]string `json:"images"` // Gönderideki görsel URL'lerinin yeni sırası
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz gönderi verisi: " + err.Error()})
+		return
+	}
+
+	oldImages := postImageURLs(post)
+	content, caption, images := post.Content, post.Caption, oldImages
+	if request.Content != nil {
+		content = *request.Content
+	}
+	if request.Caption != nil {
+		caption = *request.Caption
+	}
+	if request.Images != nil {
+		existing := make(map[string]bool)
+		for _, url := range oldImages {
+			existing[url] = true
+		}
+		images = []string{}
+		for _, url := range *request.Images {
+			if !existing[url] {
+				c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Sadece gönderide bulunan görseller sıralanabilir"})
+				return
+			}
+			existing[url] = false // Aynı görsel iki kez verilemez
+			images = append(images, url)
+		}
+	}
+	if post.RepostOfID != nil && strings.TrimSpace(content) == "" && strings.TrimSpace(caption) == "" {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Alıntı metni boş olamaz"})
+		return
+	}
+	if post.RepostOfID == nil && strings.TrimSpace(content) == "" && len(images) == 0 {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Gönderi içeriği veya en az bir görsel gereklidir"})
+		return
+	}
+
+	// Etiketler: verilmişse yeni liste, verilmemişse eski metindeki hashtag'ler çıkarılmış mevcut liste.
+	// Her iki durumda da yeni metindeki hashtag'ler eklenir.
+	var baseTags []string
+	if request.Tags != nil {
+		baseTags = splitTagsString(*request.Tags)
+	} else {
+		oldHashtags := make(map[string]bool)
+		for _, tag := range utils.ExtractHashtags(post.Content, post.Caption) {
+			oldHashtags[strings.ToLower(tag)] = true
+		}
+		for _, tag := range splitTagsString(post.TagsString) {
+			if !oldHashtags[strings.ToLower(tag)] {
+				baseTags = append(baseTags, tag)
+			}
+		}
+	}
+	tags := mergeTags(baseTags, utils.ExtractHashtags(content, caption))
+	tagsString := strings.Join(tags, ",")
+
+	textChanged := content != post.Content || caption != post.Caption
+	imagesChanged := !sameStrings(images, oldImages)
+	if !textChanged && !imagesChanged && tagsString == post.TagsString {
+		c.JSON(http.StatusOK, Response{Success: true, Message: "Gönderide değişiklik yok", Data: gin.H{"post": editedPostResponse(post, images)}})
+		return
+	}
+
+	now := time.Now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// Anketli gönderilerde metin anketin sorusudur; oy verildikten sonra soru değiştirilemez
+		if post.HasPoll && content != post.Content && pollHasVotes(tx, post.ID) {
+			return errPollQuestionLocked
+		}
+		updates := map[string]interface{}{
+			"content":     content,
+			"caption":     caption,
+			"tags_string": tagsString,
+		}
+		if published {
+			revision := models.PostRevision{
+				PostID:     post.ID,
+				EditorID:   userID,
+				Content:    post.Content,
+				Caption:    post.Caption,
+				TagsString: post.TagsString,
+				CreatedAt:  now,
+			}
+			for i, url := range oldImages {
+				revision.Images = append(revision.Images, models.PostRevisionImage{Position: i, URL: url})
+			}
+			if err := tx.Create(&revision).Error; err != nil {
+				return err
+			}
+			// Düzenleme geçmişi eski görselleri göstermeye devam ettiğinden dosyalar revizyona bağlanır
+			addMediaReferences(tx, models.MediaRefPostRevision, revision.ID, oldImages...)
+			updates["edited_at"] = now
+		}
+		if err := tx.Model(&post).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// Görseller yeni sırayla yeniden kaydedilir; kaldırılan görseller artık gönderiye ait sayılmaz
+		// ve başka içerikte kullanılmıyorsa çöp toplayıcı tarafından silinir
+		if imagesChanged {
+			if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostImage{}).Error; err != nil {
+				return err
+			}
+			for _, url := range images {
+				if err := tx.Create(&models.PostImage{PostID: post.ID, URL: url, CreatedAt: now}).Error; err != nil {
+					return err
+				}
+			}
+			setMediaReferences(tx, models.MediaRefPost, post.ID, images...)
+		}
+
+		syncPostTags(tx, post.ID, tags)
+		return nil
+	})
+	if errors.Is(err, errPollQuestionLocked) {
+		c.JSON(http.StatusConflict, Response{Success: false, Message: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Gönderi düzenlenirken bir hata oluştu: " + err.Error()})
+		return
+	}
+
+	post.Content, post.Caption, post.TagsString = content, caption, tagsString
+	if published {
+		post.EditedAt = &now
+	}
+	if textChanged {
+		// Yeni bahsedilen kullanıcılara bildirim gönderilir (taslaklarda yayın anında), yapay zeka etiketleri yeniden üretilir
+		if published {
+			saveMentions(c.Request.Context(), post.User, models.Mention{PostID: &post.ID}, post.Content, post.Caption)
+		}
+		tagPostAsync(post, images)
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Gönderi düzenlendi",
+		Data:    gin.H{"post": editedPostResponse(post, images)},
+	})
+}
+
+// editedPostResponse düzenlenen gönderiyi istemci formatına çevirir
+func editedPostResponse(post models.Post, images []string) gin.H {
+	return gin.H{
+		"id":              post.ID,
+		"content":         post.Content,
+		"caption":         post.Caption,
+		"contentEntities": buildTextEntities(post.Content),
+		"captionEntities": buildTextEntities(post.Caption),
+		"tags":            splitTagsString(post.TagsString),
+		"likes":           post.LikeCount,
+		"comments":        post.CommentCount,
+		"images":          images,
+		"audience":        post.Audience,
+		"edited":          post.EditedAt != nil,
+		"editedAt":        post.EditedAt,
+		"createdAt":       formatTimeAgo(post.CreatedAt),
+		"user": gin.H{
+			"id":           post.User.ID,
+			"username":     post.User.Username,
+			"profileImage": post.User.ProfileImage,
+		},
+	}
+}
+
+// GetPostRevisions - Gönderinin düzenleme geçmişini en yeniden eskiye getirir.
+// Gönderiyi görebilen herkes geçmişini de görebilir.
+func GetPostRevisions(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var post models.Post
+	if err := database.DB.First(&post, c.Param("id")).Error; err != nil || !CanViewPost(userID, post) {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Gönderi bulunamadı"})
+		return
+	}
+
+	var revisions []models.PostRevision
+	if err := database.DB.Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position, id")
+	}).Where("post_id = ?", post.ID).Order("created_at DESC, id DESC").Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Düzenleme geçmişi alınamadı"})
+		return
+	}
+
+	response := []gin.H{}
+	for _, revision := range revisions {
+		images := []string{}
+		for _, image := range revision.Images {
+			images = append(images, image.URL)
+		}
+		response = append(response, gin.H{
+			"id":         revision.ID,
+			"content":    revision.Content,
+			"caption":    revision.Caption,
+			"tags":       splitTagsString(revision.TagsString),
+			"images":     images,
+			"replacedAt": revision.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Düzenleme geçmişi getirildi",
+		Data: gin.H{
+			"postId":    post.ID,
+			"edited":    post.EditedAt != nil,
+			"editedAt":  post.EditedAt,
+			"revisions": response,
+		},
+	})
+}
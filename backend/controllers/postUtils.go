@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"social-media-app/backend/database"
 	"social-media-app/backend/models"
 )
 
@@ -13,6 +14,16 @@ func CanViewPost(userID uint, post models.Post) bool {
 		return true
 	}
 
+	// Taslak, zamanlanmış ve arşivlenmiş gönderileri sadece sahibi görebilir
+	if !isPublished(post.PublishStatus) || post.ArchivedAt != nil {
+		return false
+	}
+
+	// Yakın arkadaşlara özel gönderiyi sadece listedekiler görebilir
+	if !canViewAudience(userID, post.UserID, post.Audience) {
+		return false
+	}
+
 	// Normalde burada:
 	// 1. Post sahibinin hesabı gizli mi diye kontrol et
 	// 2. Gizli ise, mevcut kullanıcı onu takip ediyor mu kontrol et
@@ -21,3 +32,50 @@ func CanViewPost(userID uint, post models.Post) bool {
 
 	return true
 }
+
+// isCloseFriendOf kullanıcının ownerID'nin yakın arkadaş listesinde olup olmadığını kontrol eder
+func isCloseFriendOf(userID, ownerID uint) bool {
+	if userID == 0 {
+		return false
+	}
+	var count int64
+	database.DB.Model(&models.Follow{}).
+		Where("follower_id = ? AND following_id = ? AND is_close_friend = ?", userID, ownerID, true).
+		Count(&count)
+	return count > 0
+}
+
+// canViewAudience içeriğin kitlesine göre kullanıcının içeriği görüp göremeyeceğini belirler
+func canViewAudience(userID, ownerID uint, audience string) bool {
+	if audience != models.AudienceCloseFriends || userID == ownerID {
+		return true
+	}
+	return isCloseFriendOf(userID, ownerID)
+}
+
+// isPublished içeriğin yayında olup olmadığını kontrol eder (durumu boş olan eski kayıtlar yayında sayılır)
+func isPublished(status string) bool {
+	return status == "" || status == models.PublishStatusPublished
+}
+
+// canViewReel kullanıcının reeli görüp göremeyeceğini yayın durumu ve kitleye göre belirler
+func canViewReel(userID uint, reel models.Reels) bool {
+	if reel.UserID == userID {
+		return true
+	}
+	return isPublished(reel.PublishStatus) && canViewAudience(userID, reel.UserID, reel.Audience)
+}
+
+// contentVisibilityClause listeleme sorgularında sadece yayınlanmış içerikleri, yakın arkadaşlara özel
+// içerikleri de sahibine ve listedeki kullanıcılara gösteren koşulu üretir (table: posts veya reels).
+// Taslak ve zamanlanmış içerikler akışlarda yer almaz; sahibi bunlara taslaklar listesinden ulaşır.
+// Arşivlenmiş gönderiler de sahibi dahil kimsenin akışında yer almaz; sahibi arşiv listesinden ulaşır.
+func contentVisibilityClause(table string, userID uint) (string, []interface{}) {
+	clause := `(COALESCE(` + table + `.publish_status, 'published') = ? AND (` + table + `.user_id = ?
+		OR COALESCE(` + table + `.audience, 'everyone') <> ?
+		OR ` + table + `.user_id IN (SELECT following_id FROM follows WHERE follower_id = ? AND is_close_friend = ?)))`
+	if table == "posts" {
+		clause = `(` + clause + ` AND posts.archived_at IS NULL)`
+	}
+	return clause, []interface{}{models.PublishStatusPublished, userID, models.AudienceCloseFriends, userID, true}
+}
@@ -0,0 +1,441 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Zamanlanmış içeriklerin kontrol edilme aralığı
+const scheduledPublishInterval = time.Minute
+
+// resolvePublishStatus taslak ve zamanlama seçeneklerinden yayın durumunu belirler.
+// Zamanlanan yayın zamanı gelecekte olmalıdır; taslaklar zamanlama bilgisi taşımaz.
+func resolvePublishStatus(draft bool, scheduledAt *time.Time) (string, *time.Time, error) {
+	if draft {
+		return models.PublishStatusDraft, nil, nil
+	}
+	if scheduledAt == nil {
+		return models.PublishStatusPublished, nil, nil
+	}
+	if !scheduledAt.After(time.Now()) {
+		return "", nil, errors.New("Zamanlanan yayın zamanı gelecekte olmalı")
+	}
+	return models.PublishStatusScheduled, scheduledAt, nil
+}
+
+// announcePost yayınlanan gönderide bahsedilen kullanıcılara ve gönderi sahibinin takipçilerine
+// bildirim gönderir (yakın arkadaş gönderilerinde sadece listedekilere)
+func announcePost(ctx context.Context, user models.User, post models.Post) {
+	saveMentions(ctx, user, models.Mention{PostID: &post.ID}, post.Content, post.Caption)
+
+	var followerIDs []uint
+	followerQuery := database.DB.Model(&models.Follow{}).
+		Where("following_id = ? AND status = ?", user.ID, "active")
+	if post.Audience == models.AudienceCloseFriends {
+		followerQuery = followerQuery.Where("is_close_friend = ?", true)
+	}
+	if err := followerQuery.Pluck("follower_id", &followerIDs).Error; err != nil {
+		fmt.Printf("Takipçiler aranırken hata: %v\n", err)
+		return
+	}
+
+	for _, followerID := range followerIDs {
+		notification := models.Notification{
+			ToUserID:   followerID,
+			FromUserID: user.ID,
+			Type:       "post",
+			Message:    fmt.Sprintf("%s yeni bir gönderi paylaştı", user.FullName),
+			IsRead:     false,
+			CreatedAt:  time.Now(),
+		}
+		if err := database.DB.Create(&notification).Error; err != nil {
+			// Bildirimin oluşturulamaması gönderi işlemini engellememelidir
+			fmt.Printf("Gönderi bildirimi oluşturulurken hata: %v\n", err)
+			continue
+		}
+
+		// WebSocket üzerinden bildirim gönder (opsiyonel)
+		if notifService != nil {
+			if err := notifService.CreatePostNotification(ctx,
+				fmt.Sprintf("%d", followerID),
+				fmt.Sprintf("%d", user.ID),
+				user.FullName,
+				user.Username,
+				user.ProfileImage,
+				fmt.Sprintf("%d", post.ID),
+			); err != nil {
+				fmt.Printf("WebSocket bildirimi gönderilemedi: %v\n", err)
+			}
+		}
+	}
+}
+
+// announceReel yayınlanan reelin açıklamasında bahsedilen kullanıcılara bildirim gönderir
+func announceReel(ctx context.Context, reel models.Reels) {
+	var user models.User
+	if err := database.DB.First(&user, reel.UserID).Error; err != nil {
+		return
+	}
+	saveMentions(ctx, user, models.Mention{ReelID: &reel.ID}, reel.Caption)
+}
+
+// publishPost taslak veya zamanlanmış gönderiyi yayınlar ve bildirimleri gönderir. Gönderi akışlarda
+// yayın anına göre sıralansın diye oluşturulma zamanı yayın zamanına çekilir. Gönderi bu arada
+// başka bir istekle yayınlandıysa false döner.
+func publishPost(ctx context.Context, post models.Post) bool {
+	now := time.Now()
+	result := database.DB.Model(&post).Where("publish_status <> ?", models.PublishStatusPublished).
+		Updates(map[string]interface{}{"publish_status": models.PublishStatusPublished, "created_at": now})
+	if result.Error != nil {
+		fmt.Printf("Gönderi yayınlanamadı (ID: %d): %v\n", post.ID, result.Error)
+		return false
+	}
+	if result.RowsAffected == 0 {
+		return false
+	}
+	if post.HasPoll {
+		if err := startPollClock(database.DB, post.ID, now); err != nil {
+			fmt.Printf("Anket süresi başlatılamadı (Gönderi ID: %d): %v\n", post.ID, err)
+		}
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, post.UserID).Error; err == nil {
+		announcePost(ctx, user, post)
+	}
+	return true
+}
+
+// publishReel taslak veya zamanlanmış reeli yayınlar; publishPost ile aynı kurallar geçerlidir
+func publishReel(ctx context.Context, reel models.Reels) bool {
+	now := time.Now()
+	result := database.DB.Model(&reel).Where("publish_status <> ?", models.PublishStatusPublished).
+		Updates(map[string]interface{}{"publish_status": models.PublishStatusPublished, "created_at": now})
+	if result.Error != nil {
+		fmt.Printf("Reel yayınlanamadı (ID: %d): %v\n", reel.ID, result.Error)
+		return false
+	}
+	if result.RowsAffected == 0 {
+		return false
+	}
+
+	announceReel(ctx, reel)
+	return true
+}
+
+// StartScheduledPublisher zamanı gelen gönderi ve reelleri yayınlayan zamanlayıcıyı başlatır
+func StartScheduledPublisher() {
+	go func() {
+		ticker := time.NewTicker(scheduledPublishInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if published, err := PublishDueContent(context.Background()); err != nil {
+				fmt.Printf("Zamanlanmış içerik yayınlama hatası: %v\n", err)
+			} else if published > 0 {
+				fmt.Printf("Zamanlanmış %d içerik yayınlandı\n", published)
+			}
+		}
+	}()
+}
+
+// PublishDueContent yayın zamanı gelmiş gönderi ve reelleri yayınlar. Videosu henüz işlenmemiş
+// reeller işleme tamamlandıktan sonraki ilk çalışmada yayınlanır.
+func PublishDueContent(ctx context.Context) (int, error) {
+	now := time.Now()
+	published := 0
+
+	var posts []models.Post
+	if err := database.DB.Where("publish_status = ? AND scheduled_at <= ?", models.PublishStatusScheduled, now).
+		Find(&posts).Error; err != nil {
+		return 0, err
+	}
+	for _, post := range posts {
+		if publishPost(ctx, post) {
+			published++
+		}
+	}
+
+	var reels []models.Reels
+	if err := database.DB.Where("publish_status = ? AND scheduled_at <= ? AND status = ?",
+		models.PublishStatusScheduled, now, models.ReelStatusReady).Find(&reels).Error; err != nil {
+		return published, err
+	}
+	for _, reel := range reels {
+		if publishReel(ctx, reel) {
+			published++
+		}
+	}
+	return published, nil
+}
+
+// publishingRequest yayın durumu değişikliği isteği
+type publishingRequest struct {
+	Action      string     `json:"action" binding:"required"` // draft, schedule, publish
+	ScheduledAt *time.Time `json:"scheduledAt"`               // schedule için yayın zamanı
+}
+
+// bindPublishingRequest isteği doğrular ve yeni yayın durumunu döndürür
+func bindPublishingRequest(c *gin.Context) (string, *time.Time, bool) {
+	var request publishingRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+		return "", nil, false
+	}
+
+	var status string
+	var scheduledAt *time.Time
+	var err error
+	switch request.Action {
+	case "draft":
+		status, scheduledAt, err = resolvePublishStatus(true, nil)
+	case "schedule":
+		if request.ScheduledAt == nil {
+			err = errors.New("Zamanlama için yayın zamanı gereklidir")
+			break
+		}
+		status, scheduledAt, err = resolvePublishStatus(false, request.ScheduledAt)
+	case "publish":
+		status, scheduledAt, err = resolvePublishStatus(false, nil)
+	default:
+		err = errors.New("Geçersiz işlem: draft, schedule veya publish olmalı")
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: err.Error()})
+		return "", nil, false
+	}
+	return status, scheduledAt, true
+}
+
+// UpdatePostPublishing - Taslak veya zamanlanmış gönderiyi yayınlar, yeniden zamanlar ya da taslağa alır (sadece yazar)
+func UpdatePostPublishing(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var post models.Post
+	if err := database.DB.First(&post, c.Param("id")).Error; err != nil || !CanViewPost(userID, post) {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Gönderi bulunamadı"})
+		return
+	}
+	if post.UserID != userID {
+		c.JSON(http.StatusForbidden, Response{Success: false, Message: "Bu gönderinin yayın durumunu değiştirme yetkiniz yok"})
+		return
+	}
+	if isPublished(post.PublishStatus) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Yayınlanmış gönderinin yayın durumu değiştirilemez"})
+		return
+	}
+
+	status, scheduledAt, ok := bindPublishingRequest(c)
+	if !ok {
+		return
+	}
+
+	if status == models.PublishStatusPublished {
+		if !publishPost(c.Request.Context(), post) {
+			c.JSON(http.StatusConflict, Response{Success: false, Message: "Gönderi zaten yayınlanmış"})
+			return
+		}
+	} else if err := database.DB.Model(&post).Where("publish_status <> ?", models.PublishStatusPublished).
+		Updates(map[string]interface{}{"publish_status": status, "scheduled_at": scheduledAt}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yayın durumu güncellenemedi: " + err.Error()})
+		return
+	}
+
+	database.DB.First(&post, post.ID)
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Yayın durumu güncellendi",
+		Data:    gin.H{"id": post.ID, "publishStatus": post.PublishStatus, "scheduledAt": post.ScheduledAt},
+	})
+}
+
+// UpdateReelPublishing - Taslak veya zamanlanmış reeli yayınlar, yeniden zamanlar ya da taslağa alır (sadece sahibi)
+func UpdateReelPublishing(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var reel models.Reels
+	if err := database.DB.First(&reel, c.Param("id")).Error; err != nil || !canViewReel(userID, reel) {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Reel bulunamadı"})
+		return
+	}
+	if reel.UserID != userID {
+		c.JSON(http.StatusForbidden, Response{Success: false, Message: "Bu reelin yayın durumunu değiştirme yetkiniz yok"})
+		return
+	}
+	if isPublished(reel.PublishStatus) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Yayınlanmış reelin yayın durumu değiştirilemez"})
+		return
+	}
+
+	status, scheduledAt, ok := bindPublishingRequest(c)
+	if !ok {
+		return
+	}
+
+	if status == models.PublishStatusPublished {
+		if reel.Status != models.ReelStatusReady {
+			c.JSON(http.StatusConflict, Response{Success: false, Message: "Video işlenmeden reel yayınlanamaz; yayını zamanlayabilirsiniz"})
+			return
+		}
+		if !publishReel(c.Request.Context(), reel) {
+			c.JSON(http.StatusConflict, Response{Success: false, Message: "Reel zaten yayınlanmış"})
+			return
+		}
+	} else if err := database.DB.Model(&reel).Where("publish_status <> ?", models.PublishStatusPublished).
+		Updates(map[string]interface{}{"publish_status": status, "scheduled_at": scheduledAt}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yayın durumu güncellenemedi: " + err.Error()})
+		return
+	}
+
+	database.DB.First(&reel, reel.ID)
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Yayın durumu güncellendi",
+		Data:    gin.H{"id": reel.ID, "publishStatus": reel.PublishStatus, "scheduledAt": reel.ScheduledAt},
+	})
+}
+
+// EditReelDraft - Henüz yayınlanmamış reelin açıklamasını, müziğini ve kitlesini düzenler (sadece sahibi)
+func EditReelDraft(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	reelID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz reel ID"})
+		return
+	}
+
+	var reel models.Reels
+	if err := database.DB.First(&reel, reelID).Error; err != nil || !canViewReel(userID, reel) {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Reel bulunamadı"})
+		return
+	}
+	if reel.UserID != userID {
+		c.JSON(http.StatusForbidden, Response{Success: false, Message: "Bu reeli düzenleme yetkiniz yok"})
+		return
+	}
+	if isPublished(reel.PublishStatus) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Sadece taslak veya zamanlanmış reeller düzenlenebilir"})
+		return
+	}
+
+	var request struct {
+		Caption           *string `json:"caption"`
+		Music             *string `json:"music"`
+		Audience          *string `json:"audience"`
+		CommentPermission *string `json:"commentPermission"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz reel verisi: " + err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if request.Caption != nil {
+		updates["caption"] = *request.Caption
+	}
+	if request.Music != nil {
+		updates["music"] = *request.Music
+	}
+	if request.Audience != nil {
+		if *request.Audience != models.AudienceEveryone && *request.Audience != models.AudienceCloseFriends {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Kitle everyone veya close_friends olmalı"})
+			return
+		}
+		updates["audience"] = *request.Audience
+	}
+	if request.CommentPermission != nil {
+		if *request.CommentPermission != "" && !isValidCommentPermission(*request.CommentPermission) {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Yorum izni all, followers veya none olmalı"})
+			return
+		}
+		updates["comment_permission"] = *request.CommentPermission
+	}
+
+	if len(updates) > 0 {
+		if err := database.DB.Model(&reel).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Reel düzenlenemedi: " + err.Error()})
+			return
+		}
+	}
+
+	database.DB.First(&reel, reel.ID)
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Reel taslağı düzenlendi", Data: draftReelResponse(reel)})
+}
+
+// draftReelResponse yayınlanmamış reeli istemci formatına çevirir
+func draftReelResponse(reel models.Reels) gin.H {
+	return gin.H{
+		"id":                reel.ID,
+		"caption":           reel.Caption,
+		"videoURL":          reel.VideoURL,
+		"thumbnailURL":      reel.ThumbnailURL,
+		"music":             reel.Music,
+		"duration":          reel.Duration,
+		"audience":          reel.Audience,
+		"commentPermission": reel.CommentPermission,
+		"status":            reel.Status,
+		"publishStatus":     reel.PublishStatus,
+		"scheduledAt":       reel.ScheduledAt,
+		"createdAt":         reel.CreatedAt,
+		"updatedAt":         reel.UpdatedAt,
+	}
+}
+
+// GetDrafts - Oturumdaki kullanıcının taslak ve zamanlanmış gönderi ve reellerini getirir
+func GetDrafts(c *gin.Context) {
+	userID := c.GetUint("userID")
+	statuses := []string{models.PublishStatusDraft, models.PublishStatusScheduled}
+
+	var posts []models.Post
+	if err := database.DB.Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("user_id = ? AND publish_status IN ?", userID, statuses).
+		Order("updated_at DESC").Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Taslaklar alınamadı"})
+		return
+	}
+
+	var reels []models.Reels
+	if err := database.DB.Where("user_id = ? AND publish_status IN ?", userID, statuses).
+		Order("updated_at DESC").Find(&reels).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Taslaklar alınamadı"})
+		return
+	}
+
+	postsResponse := []gin.H{}
+	for _, post := range posts {
+		postsResponse = append(postsResponse, gin.H{
+			"id":                post.ID,
+			"content":           post.Content,
+			"caption":           post.Caption,
+			"tags":              splitTagsString(post.TagsString),
+			"images":            postImageURLs(post),
+			"audience":          post.Audience,
+			"commentPermission": post.CommentPermission,
+			"publishStatus":     post.PublishStatus,
+			"scheduledAt":       post.ScheduledAt,
+			"poll":              postPoll(userID, post),
+			"createdAt":         post.CreatedAt,
+			"updatedAt":         post.UpdatedAt,
+		})
+	}
+	reelsResponse := []gin.H{}
+	for _, reel := range reels {
+		reelsResponse = append(reelsResponse, draftReelResponse(reel))
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Taslaklar getirildi",
+		Data:    gin.H{"posts": postsResponse, "reels": reelsResponse},
+	})
+}
@@ -0,0 +1,237 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Varsayılan kotalar (USER_STORAGE_QUOTA_MB ve USER_DAILY_UPLOAD_LIMIT ile değiştirilebilir).
+// En uzun video süresi varsayılan olarak REEL_MAX_DURATION_SECONDS değeridir.
+const (
+	defaultStorageQuotaMB   = 2048
+	defaultDailyUploadLimit = 100
+)
+
+// StorageQuota kullanıcı için geçerli kota değerleri (0 sınırsız)
+type StorageQuota struct {
+	StorageBytes    int64 `json:"storageBytes"`
+	DailyFiles      int   `json:"dailyFiles"`
+	MaxVideoSeconds int   `json:"maxVideoSeconds"`
+	Custom          bool  `json:"custom"` // Admin tarafından kullanıcıya özel kota tanımlı mı
+}
+
+// MaxVideoDuration en uzun video süresini döndürür (0 sınırsız)
+func (q StorageQuota) MaxVideoDuration() time.Duration {
+	return time.Duration(q.MaxVideoSeconds) * time.Second
+}
+
+// StorageUsage kullanıcının mevcut depolama kullanımı
+type StorageUsage struct {
+	UsedBytes    int64        `json:"usedBytes"`
+	PendingBytes int64        `json:"pendingBytes"` // Devam eden devam ettirilebilir yüklemeler
+	FilesLast24h int64        `json:"filesLast24h"`
+	Quota        StorageQuota `json:"quota"`
+}
+
+// quotaError kotayı aşan yüklemeler için istemciye gösterilecek hata
+type quotaError struct {
+	status  int
+	message string
+}
+
+func (e *quotaError) Error() string {
+	return e.message
+}
+
+func intFromEnv(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return fallback
+}
+
+// defaultStorageQuota ortam değişkenlerinden varsayılan kotaları okur
+func defaultStorageQuota() StorageQuota {
+	quota := StorageQuota{
+		StorageBytes: int64(intFromEnv("USER_STORAGE_QUOTA_MB", defaultStorageQuotaMB)) << 20,
+		DailyFiles:   intFromEnv("USER_DAILY_UPLOAD_LIMIT", defaultDailyUploadLimit),
+	}
+	if videoProcessor != nil {
+		quota.MaxVideoSeconds = int(videoProcessor.MaxDuration.Seconds())
+	}
+	return quota
+}
+
+// userStorageQuota kullanıcıya özel tanımlı alanları varsayılanların üzerine yazar
+func userStorageQuota(userID uint) StorageQuota {
+	quota := defaultStorageQuota()
+
+	var override models.UserQuota
+	if database.DB.Where("user_id = ?", userID).First(&override).Error != nil {
+		return quota
+	}
+	quota.Custom = true
+	if override.StorageBytes != nil {
+		quota.StorageBytes = *override.StorageBytes
+	}
+	if override.DailyFiles != nil {
+		quota.DailyFiles = *override.DailyFiles
+	}
+	if override.MaxVideoSeconds != nil {
+		quota.MaxVideoSeconds = *override.MaxVideoSeconds
+	}
+	return quota
+}
+
+// userStorageUsage kullanıcının sahibi olduğu dosyaların toplam boyutunu ve son 24 saatteki yüklemelerini hesaplar.
+// Aynı içerik depoda bir kez saklansa da yükleyen her kullanıcının kotasından ayrıca düşer.
+func userStorageUsage(userID uint) StorageUsage {
+	usage := StorageUsage{Quota: userStorageQuota(userID)}
+
+	database.DB.Model(&models.MediaOwner{}).Joins("JOIN media ON media.id = media_owners.media_id").
+		Where("media_owners.user_id = ?", userID).
+		Select("COALESCE(SUM(media.size), 0)").Scan(&usage.UsedBytes)
+	database.DB.Model(&models.MediaOwner{}).
+		Where("user_id = ? AND created_at > ?", userID, time.Now().Add(-24*time.Hour)).
+		Count(&usage.FilesLast24h)
+
+	var pendingFiles int64
+	database.DB.Model(&models.UploadSession{}).
+		Where("user_id = ? AND status = ? AND expires_at > ?", userID, models.UploadStatusUploading, time.Now()).
+		Select("COALESCE(SUM(length), 0)").Scan(&usage.PendingBytes)
+	database.DB.Model(&models.UploadSession{}).
+		Where("user_id = ? AND status = ? AND expires_at > ?", userID, models.UploadStatusUploading, time.Now()).
+		Count(&pendingFiles)
+	usage.FilesLast24h += pendingFiles
+
+	return usage
+}
+
+// checkUploadQuota size baytlık yeni bir dosyanın kullanıcının kotasına sığıp sığmadığını kontrol eder
+func checkUploadQuota(userID uint, size int64) error {
+	usage := userStorageUsage(userID)
+	quota := usage.Quota
+
+	if quota.DailyFiles > 0 && usage.FilesLast24h >= int64(quota.DailyFiles) {
+		return &quotaError{
+			status:  http.StatusTooManyRequests,
+			message: fmt.Sprintf("Günlük yükleme sınırına ulaştınız (24 saatte en fazla %d dosya)", quota.DailyFiles),
+		}
+	}
+	if quota.StorageBytes > 0 && usage.UsedBytes+usage.PendingBytes+size > quota.StorageBytes {
+		remaining := quota.StorageBytes - usage.UsedBytes - usage.PendingBytes
+		if remaining < 0 {
+			remaining = 0
+		}
+		return &quotaError{
+			status: http.StatusRequestEntityTooLarge,
+			message: fmt.Sprintf("Depolama kotanız yetersiz: %.1f MB kullanılabilir, dosya %.1f MB",
+				float64(remaining)/(1<<20), float64(size)/(1<<20)),
+		}
+	}
+	return nil
+}
+
+// checkStorageQuota daha önce yüklenmiş dosyalarla içerik oluşturulurken kullanıcının depolama kotasını
+// aşmadığını kontrol eder (ör. admin kotayı kullanımın altına düşürdüyse yeni içerik paylaşılamaz)
+func checkStorageQuota(userID uint) error {
+	usage := userStorageUsage(userID)
+	if usage.Quota.StorageBytes > 0 && usage.UsedBytes > usage.Quota.StorageBytes {
+		return &quotaError{
+			status: http.StatusRequestEntityTooLarge,
+			message: fmt.Sprintf("Depolama kotanızı aştınız (%.1f / %.1f MB); yeni içerik paylaşmak için dosya silin",
+				float64(usage.UsedBytes)/(1<<20), float64(usage.Quota.StorageBytes)/(1<<20)),
+		}
+	}
+	return nil
+}
+
+// respondQuotaError hata bir kota hatasıysa yanıtı yazar ve true döner
+func respondQuotaError(c *gin.Context, err error) bool {
+	quotaErr, ok := err.(*quotaError)
+	if !ok {
+		return false
+	}
+	c.JSON(quotaErr.status, Response{
+		Success: false,
+		Message: quotaErr.message,
+	})
+	return true
+}
+
+// GetUserQuota - Bir kullanıcının kotasını ve kullanımını getirir (sadece admin)
+func GetUserQuota(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz kullanıcı ID"})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Kullanıcı kotası getirildi",
+		Data:    userStorageUsage(uint(userID)),
+	})
+}
+
+// UpdateUserQuota - Kullanıcıya özel kota tanımlar (sadece admin).
+// Gönderilmeyen (null) alanlar varsayılan değeri kullanır; tüm alanlar null ise özel kota kaldırılır.
+func UpdateUserQuota(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz kullanıcı ID"})
+		return
+	}
+
+	var request struct {
+		StorageBytes    *int64 `json:"storageBytes"`
+		DailyFiles      *int   `json:"dailyFiles"`
+		MaxVideoSeconds *int   `json:"maxVideoSeconds"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz kota verisi: " + err.Error()})
+		return
+	}
+	if (request.StorageBytes != nil && *request.StorageBytes < 0) ||
+		(request.DailyFiles != nil && *request.DailyFiles < 0) ||
+		(request.MaxVideoSeconds != nil && *request.MaxVideoSeconds < 0) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Kota değerleri negatif olamaz (0 sınırsız demektir)"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Kullanıcı bulunamadı"})
+		return
+	}
+
+	if request.StorageBytes == nil && request.DailyFiles == nil && request.MaxVideoSeconds == nil {
+		database.DB.Where("user_id = ?", user.ID).Delete(&models.UserQuota{})
+	} else {
+		override := models.UserQuota{
+			UserID:          user.ID,
+			StorageBytes:    request.StorageBytes,
+			DailyFiles:      request.DailyFiles,
+			MaxVideoSeconds: request.MaxVideoSeconds,
+			UpdatedBy:       c.GetUint("userID"),
+			UpdatedAt:       time.Now(),
+		}
+		if err := database.DB.Save(&override).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Kota kaydedilemedi: " + err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Kullanıcı kotası güncellendi",
+		Data:    userStorageUsage(user.ID),
+	})
+}
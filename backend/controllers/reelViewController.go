@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Varsayılan görüntülenme tekilleştirme penceresi (REEL_VIEW_DEDUP_WINDOW_MINUTES ile değiştirilebilir)
+const defaultReelViewDedupMinutes = 30
+
+// maxReelViewLoops bir görüntülenme oturumunda izlenme süresine eklenen en fazla döngü sayısı.
+// Sonraki loop olayları yok sayılır; böylece istemci izlenme süresini sınırsız şişiremez.
+const maxReelViewLoops = 10
+
+// reelExploreScoreSQL keşfet sıralamasında kullanılan reel puanı. Etkileşimler ve görüntülenmeler,
+// tamamlanma oranı ve ortalama izlenme oranıyla (izlenen süre / reel süresi) ağırlıklandırılır;
+// böylece çok açılıp hemen geçilen reeller sonuna kadar izlenenlerin gerisinde kalır. Oranlar izleme
+// verisi olmayan eski görüntülenmeler hariç tutularak hesaplanır.
+const reelExploreScoreSQL = `((reels.like_count * 2 + reels.comment_count * 3 + reels.share_count * 4 + reels.view_count)
+	* (1 + reels.completed_count * 1.0 / MAX(reels.view_count - reels.legacy_view_count, 1)
+		+ MIN(reels.watch_seconds * 1.0 / (MAX(reels.view_count - reels.legacy_view_count, 1) * MAX(reels.duration, 1)), 1)))`
+
+// reelViewDedupWindow aynı izleyicinin tekrar izlemelerinin tek görüntülenme sayıldığı süre
+func reelViewDedupWindow() time.Duration {
+	minutes := intFromEnv("REEL_VIEW_DEDUP_WINDOW_MINUTES", defaultReelViewDedupMinutes)
+	if minutes <= 0 {
+		minutes = defaultReelViewDedupMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// reelViewStats reelin görüntülenme istatistiklerini istemci formatına çevirir. Ortalamalar sadece
+// izleme oturumu olan görüntülenmelerden hesaplanır (eski görüntülenmelerin izlenme verisi yoktur).
+func reelViewStats(reel models.Reels) gin.H {
+	averageWatchTime, completionRate := 0.0, 0.0
+	if sessions := reel.ViewCount - reel.LegacyViewCount; sessions > 0 {
+		averageWatchTime = float64(reel.WatchSeconds) / float64(sessions)
+		completionRate = float64(reel.CompletedCount) / float64(sessions)
+	}
+	return gin.H{
+		"viewCount":        reel.ViewCount,
+		"averageWatchTime": math.Round(averageWatchTime*10) / 10, // Saniye
+		"completionRate":   math.Round(completionRate*1000) / 1000,
+	}
+}
+
+// RecordReelView - Reel izleme olayını kaydeder (start, progress, complete, loop).
+// Görüntülenme sadece start olayında ve izleyicinin son olayı tekilleştirme penceresinden eskiyse
+// (açık oturumu yoksa) sayılır; diğer olaylar açık oturumu günceller, oturum yoksa yok sayılır.
+// İzlenme süresi reelin sunucuda ölçülen süresiyle ve oturumun başından beri geçen süreyle sınırlıdır;
+// bir oturumda en fazla maxReelViewLoops döngü izlenme süresine eklenir.
+func RecordReelView(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	reelID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz reel ID"})
+		return
+	}
+
+	var request struct {
+		Event    string `json:"event" binding:"required"`
+		Progress int    `json:"progress"` // progress olayında izlenen yüzde (0-100)
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz izleme verisi: " + err.Error()})
+		return
+	}
+	switch request.Event {
+	case models.ReelViewEventStart, models.ReelViewEventProgress, models.ReelViewEventComplete, models.ReelViewEventLoop:
+	default:
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz izleme olayı: start, progress, complete veya loop olmalı"})
+		return
+	}
+	if request.Progress < 0 || request.Progress > 100 {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "İzleme yüzdesi 0 ile 100 arasında olmalı"})
+		return
+	}
+
+	var reel models.Reels
+	if err := database.DB.Where("status = ?", models.ReelStatusReady).First(&reel, reelID).Error; err != nil ||
+		!canViewReel(userID, reel) {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Reel bulunamadı"})
+		return
+	}
+
+	// Sahibinin kendi reelini izlemesi istatistiklere dahil edilmez
+	counted := false
+	if reel.UserID != userID {
+		now := time.Now()
+		windowStart := now.Add(-reelViewDedupWindow())
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			// Açık oturum yoksa start olayı yenisini açar. Kontrol ve ekleme tek sorguda yapılır;
+			// böylece eşzamanlı start olaylarından sadece biri oturum açar ve görüntülenme sayılır.
+			created := false
+			if request.Event == models.ReelViewEventStart {
+				result := tx.Exec(`INSERT INTO reel_views (reel_id, viewer_id, started_at, last_event_at)
+					SELECT ?, ?, ?, ? WHERE NOT EXISTS (
+						SELECT 1 FROM reel_views WHERE reel_id = ? AND viewer_id = ? AND last_event_at > ?)`,
+					reel.ID, userID, now, now, reel.ID, userID, windowStart)
+				if result.Error != nil {
+					return result.Error
+				}
+				created = result.RowsAffected > 0
+			}
+
+			// İzleyicinin açık oturumu bulunur ve son olay zamanı ilerletilir. İşlem bir yazmayla
+			// başladığından aynı oturuma gelen eşzamanlı olaylar sırayla uygulanır.
+			openSession := tx.Model(&models.ReelView{}).Select("id").
+				Where("reel_id = ? AND viewer_id = ? AND last_event_at > ?", reel.ID, userID, windowStart).
+				Order("last_event_at DESC").Limit(1)
+			result := tx.Model(&models.ReelView{}).Where("id = (?)", openSession).UpdateColumn("last_event_at", now)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return nil // start olmadan gelen olay
+			}
+			var view models.ReelView
+			if err := tx.Where("id = (?)", openSession).First(&view).Error; err != nil {
+				return err
+			}
+			previousWatch, previouslyCompleted := view.WatchSeconds, view.Completed
+
+			switch request.Event {
+			case models.ReelViewEventProgress:
+				if request.Progress > view.Progress {
+					view.Progress = request.Progress
+				}
+			case models.ReelViewEventComplete:
+				view.Progress = 100
+				view.Completed = true
+			case models.ReelViewEventLoop:
+				// Yeni döngü başladı; önceki döngü sonuna kadar izlenmiş sayılır
+				if view.LoopCount < maxReelViewLoops {
+					view.LoopCount++
+					view.Progress = 0
+				}
+				view.Completed = true
+			}
+			// reel.Duration işleme sırasında videodan ölçülür; istemcinin bildirdiği ilerleme ayrıca
+			// oturumun başından beri geçen süreyi aşamaz
+			watched := (view.LoopCount*100 + view.Progress) * reel.Duration / 100
+			if elapsed := int(now.Sub(view.StartedAt) / time.Second); watched > elapsed {
+				watched = elapsed
+			}
+			if watched > view.WatchSeconds {
+				view.WatchSeconds = watched
+			}
+
+			if err := tx.Save(&view).Error; err != nil {
+				return err
+			}
+
+			if created {
+				counted = true
+				if err := adjustCounter(tx, &models.Reels{}, viewCountColumn, 1, reel.ID); err != nil {
+					return err
+				}
+			}
+			if err := adjustCounter(tx, &models.Reels{}, watchSecondsColumn, view.WatchSeconds-previousWatch, reel.ID); err != nil {
+				return err
+			}
+			if view.Completed && !previouslyCompleted {
+				return adjustCounter(tx, &models.Reels{}, completedCountColumn, 1, reel.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "İzleme kaydedilemedi: " + err.Error()})
+			return
+		}
+		database.DB.First(&reel, reel.ID)
+	}
+
+	stats := reelViewStats(reel)
+	stats["counted"] = counted
+	c.JSON(http.StatusOK, Response{Success: true, Message: "İzleme kaydedildi", Data: stats})
+}
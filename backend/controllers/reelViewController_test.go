@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createTestReel verilen kullanıcı için yayınlanmış, işlenmiş bir reel oluşturur
+func createTestReel(t *testing.T, userID uint, duration int) models.Reels {
+	t.Helper()
+	reel := models.Reels{
+		UserID:        userID,
+		VideoURL:      "/uploads/videos/klip.mp4",
+		Duration:      duration,
+		Status:        models.ReelStatusReady,
+		PublishStatus: models.PublishStatusPublished,
+	}
+	if err := database.DB.Create(&reel).Error; err != nil {
+		t.Fatalf("Reel oluşturulamadı: %v", err)
+	}
+	return reel
+}
+
+// recordReelEvent izleyici adına reel izleme olayı gönderir ve yanıt durumunu döndürür
+func recordReelEvent(viewerID, reelID uint, event string, progress int) int {
+	router := gin.New()
+	router.POST("/reels/:id/view", func(c *gin.Context) { c.Set("userID", viewerID) }, RecordReelView)
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/reels/%d/view", reelID),
+		strings.NewReader(fmt.Sprintf(`{"event":%q,"progress":%d}`, event, progress)))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+// ageReelViewSessions izleyicinin oturumlarının zamanlarını verilen süre kadar geriye çeker
+func ageReelViewSessions(viewerID uint, age time.Duration) {
+	database.DB.Model(&models.ReelView{}).Where("viewer_id = ?", viewerID).UpdateColumns(map[string]interface{}{
+		"started_at":    time.Now().Add(-age),
+		"last_event_at": time.Now().Add(-age),
+	})
+}
+
+func reelViewSessions(reelID uint) int64 {
+	var sessions int64
+	database.DB.Model(&models.ReelView{}).Where("reel_id = ?", reelID).Count(&sessions)
+	return sessions
+}
+
+func TestRecordReelViewDeduplicatesWithinSlidingWindow(t *testing.T) {
+	setupTestDatabase(t)
+	gin.SetMode(gin.TestMode)
+	t.Setenv("REEL_VIEW_DEDUP_WINDOW_MINUTES", "30")
+	owner := createTestUser(t, "yapimci")
+	viewer := createTestUser(t, "izleyici")
+	late := createTestUser(t, "gecikmis")
+	reel := createTestReel(t, owner.ID, 15)
+
+	// Start olmadan gelen olaylar görüntülenme saymaz ve oturum açmaz
+	for _, event := range []string{models.ReelViewEventProgress, models.ReelViewEventComplete, models.ReelViewEventLoop} {
+		if code := recordReelEvent(late.ID, reel.ID, event, 50); code != http.StatusOK {
+			t.Fatalf("%s olayı durumu %d", event, code)
+		}
+	}
+	if sessions := reelViewSessions(reel.ID); sessions != 0 {
+		t.Fatalf("start olmadan %d oturum açıldı", sessions)
+	}
+
+	recordReelEvent(viewer.ID, reel.ID, models.ReelViewEventStart, 0)
+	recordReelEvent(viewer.ID, reel.ID, models.ReelViewEventStart, 0)
+	if got := counterValue(database.DB, &models.Reels{}, viewCountColumn, reel.ID); got != 1 {
+		t.Fatalf("görüntülenme %d, pencere içindeki tekrar start tek sayılmalı", got)
+	}
+
+	// Pencere son olaydan itibaren kayar: sabit bir pencere sınırını geçen izleme ikinci kez sayılmaz
+	for i := 0; i < 3; i++ {
+		ageReelViewSessions(viewer.ID, 20*time.Minute)
+		recordReelEvent(viewer.ID, reel.ID, models.ReelViewEventStart, 0)
+	}
+	if got := counterValue(database.DB, &models.Reels{}, viewCountColumn, reel.ID); got != 1 {
+		t.Errorf("görüntülenme %d, son olaydan 20 dakika sonraki start aynı oturumda kalmalı", got)
+	}
+
+	// Son olaydan pencere süresi geçince yeni oturum açılır
+	ageReelViewSessions(viewer.ID, 31*time.Minute)
+	recordReelEvent(viewer.ID, reel.ID, models.ReelViewEventStart, 0)
+	if got := counterValue(database.DB, &models.Reels{}, viewCountColumn, reel.ID); got != 2 {
+		t.Errorf("görüntülenme %d, pencere dolduktan sonraki start yeniden sayılmalı", got)
+	}
+	if sessions := reelViewSessions(reel.ID); sessions != 2 {
+		t.Errorf("%d oturum, beklenen 2", sessions)
+	}
+}
+
+func TestRecordReelViewCapsWatchTime(t *testing.T) {
+	setupTestDatabase(t)
+	gin.SetMode(gin.TestMode)
+	owner := createTestUser(t, "yapimci")
+	viewer := createTestUser(t, "izleyici")
+	reel := createTestReel(t, owner.ID, 15)
+
+	// Oturumun başından beri 5 saniye geçmişken bildirilen tam izleme 5 saniyeyle sınırlanır
+	recordReelEvent(viewer.ID, reel.ID, models.ReelViewEventStart, 0)
+	ageReelViewSessions(viewer.ID, 5*time.Second)
+	recordReelEvent(viewer.ID, reel.ID, models.ReelViewEventComplete, 0)
+	if got := counterValue(database.DB, &models.Reels{}, watchSecondsColumn, reel.ID); got != 5 {
+		t.Errorf("izlenme süresi %d saniye, geçen süre olan 5 saniyeyi aşmamalı", got)
+	}
+
+	// Döngüler reelin ölçülen süresiyle ve oturum başına döngü sınırıyla hesaplanır
+	ageReelViewSessions(viewer.ID, time.Hour/2-time.Minute)
+	for i := 0; i < maxReelViewLoops+5; i++ {
+		recordReelEvent(viewer.ID, reel.ID, models.ReelViewEventLoop, 0)
+	}
+	want := maxReelViewLoops * reel.Duration
+	if got := counterValue(database.DB, &models.Reels{}, watchSecondsColumn, reel.ID); got != want {
+		t.Errorf("izlenme süresi %d saniye, beklenen %d", got, want)
+	}
+	if got := counterValue(database.DB, &models.Reels{}, completedCountColumn, reel.ID); got != 1 {
+		t.Errorf("tamamlanma %d, oturum bir kez sayılmalı", got)
+	}
+}
+
+func TestRecordReelViewConcurrentEvents(t *testing.T) {
+	setupTestDatabase(t)
+	gin.SetMode(gin.TestMode)
+	owner := createTestUser(t, "yapimci")
+	viewers := []models.User{createTestUser(t, "izleyici1"), createTestUser(t, "izleyici2"), createTestUser(t, "izleyici3")}
+	reel := createTestReel(t, owner.ID, 15)
+
+	// Her izleyici aynı anda birden çok start ve complete olayı gönderir
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failures := 0
+	for _, viewer := range viewers {
+		for attempt := 0; attempt < 4; attempt++ {
+			for _, event := range []string{models.ReelViewEventStart, models.ReelViewEventComplete} {
+				wg.Add(1)
+				go func(viewerID uint, event string) {
+					defer wg.Done()
+					if code := recordReelEvent(viewerID, reel.ID, event, 0); code != http.StatusOK {
+						mu.Lock()
+						failures++
+						mu.Unlock()
+					}
+				}(viewer.ID, event)
+			}
+		}
+	}
+	wg.Wait()
+
+	if failures != 0 {
+		t.Errorf("%d istek başarısız oldu", failures)
+	}
+	if sessions := reelViewSessions(reel.ID); sessions != int64(len(viewers)) {
+		t.Errorf("%d oturum, izleyici başına bir oturum beklenirdi", sessions)
+	}
+	if got := counterValue(database.DB, &models.Reels{}, viewCountColumn, reel.ID); got != len(viewers) {
+		t.Errorf("görüntülenme %d, beklenen %d", got, len(viewers))
+	}
+
+	// Sayaçlar oturum tablosuyla tutarlı kalır
+	report, err := RunCounterReconciliation(true)
+	if err != nil {
+		t.Fatalf("Uzlaştırma başarısız: %v", err)
+	}
+	if report.TotalDrifted != 0 {
+		t.Errorf("eşzamanlı olaylardan sonra sayaçlarda sapma var: %v", driftedCounters(report))
+	}
+}
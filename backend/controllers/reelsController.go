@@ -1,15 +1,18 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"path/filepath"
 	"social-media-app/backend/database"
 	"social-media-app/backend/models"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -25,10 +28,12 @@ func GetExploreReels(c *gin.Context) {
 		}
 	}
 
-	// Popüler reelsleri getir (beğeni ve görüntüleme sayısına göre)
+	// Popüler reelsleri getir (etkileşim, görüntülenme, izlenme süresi ve tamamlanma oranına göre)
 	var reels []models.Reels
-	query := database.DB.
-		Order("like_count DESC, view_count DESC, created_at DESC").
+	visibilitySQL, visibilityArgs := contentVisibilityClause("reels", c.GetUint("userID"))
+	query := database.DB.Where("status = ?", models.ReelStatusReady).
+		Where(visibilitySQL, visibilityArgs...).
+		Order(reelExploreScoreSQL + " DESC, reels.created_at DESC").
 		Limit(limit)
 
 	// Reels verilerini yükle - User ilişkisini preload et
@@ -59,24 +64,29 @@ func GetExploreReels(c *gin.Context) {
 		isSaved = saveCheck.RowsAffected > 0
 
 		// Yanıt için reel bilgilerini hazırla
+		stats := reelViewStats(reel)
 		reelsResponse = append(reelsResponse, gin.H{
-			"id":           reel.ID,
-			"caption":      reel.Caption,
-			"videoURL":     reel.VideoURL,
-			"thumbnailURL": reel.ThumbnailURL,
-			"thumbnail":    reel.ThumbnailURL,
-			"media_url":    reel.VideoURL,
-			"music":        reel.Music,
-			"duration":     reel.Duration,
-			"user":         reel.User,
-			"likes":        reel.LikeCount,
-			"likeCount":    reel.LikeCount,
-			"commentCount": reel.CommentCount,
-			"shareCount":   reel.ShareCount,
-			"viewCount":    reel.ViewCount,
-			"isLiked":      isLiked,
-			"isSaved":      isSaved,
-			"createdAt":    reel.CreatedAt,
+			"id":               reel.ID,
+			"caption":          reel.Caption,
+			"videoURL":         reel.VideoURL,
+			"thumbnailURL":     reel.ThumbnailURL,
+			"thumbnail":        reel.ThumbnailURL,
+			"hlsURL":           reel.HLSURL,
+			"media_url":        reel.VideoURL,
+			"music":            reel.Music,
+			"duration":         reel.Duration,
+			"audience":         reel.Audience,
+			"user":             reel.User,
+			"likes":            reel.LikeCount,
+			"likeCount":        reel.LikeCount,
+			"commentCount":     reel.CommentCount,
+			"shareCount":       reel.ShareCount,
+			"viewCount":        reel.ViewCount,
+			"averageWatchTime": stats["averageWatchTime"],
+			"completionRate":   stats["completionRate"],
+			"isLiked":          isLiked,
+			"isSaved":          isSaved,
+			"createdAt":        reel.CreatedAt,
 		})
 	}
 
@@ -110,8 +120,12 @@ func GetReels(c *gin.Context) {
 		query = database.DB.Order("created_at DESC")
 	}
 
-	// Reels verilerini yükle - User ilisşkisini preload et
-	result := query.Preload("User").Find(&reels)
+	// Taslaklar akışta yer almaz, yakın arkadaşlara özel reeller sahibine ve listedekilere gösterilir
+	visibilitySQL, visibilityArgs := contentVisibilityClause("reels", c.GetUint("userID"))
+	query = query.Where(visibilitySQL, visibilityArgs...)
+
+	// Reels verilerini yükle - User ilisşkisini preload et (sadece işlenmiş reeller)
+	result := query.Where("reels.status = ?", models.ReelStatusReady).Preload("User").Find(&reels)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
@@ -137,23 +151,21 @@ func GetReels(c *gin.Context) {
 			First(&models.SavedReel{})
 		isSaved = saveCheck.RowsAffected > 0
 
-		// Her bir reel için görüntülenme sayısını artır
-		database.DB.Model(&models.Reels{}).Where("id = ?", reel.ID).
-			Update("view_count", gorm.Expr("view_count + ?", 1))
-
 		// Yanıt için reel bilgilerini hazırla
 		reelsResponse = append(reelsResponse, gin.H{
 			"id":           reel.ID,
 			"caption":      reel.Caption,
 			"videoURL":     reel.VideoURL,
 			"thumbnailURL": reel.ThumbnailURL,
+			"hlsURL":       reel.HLSURL,
 			"music":        reel.Music,
 			"duration":     reel.Duration,
+			"audience":     reel.Audience,
 			"user":         reel.User,
 			"likeCount":    reel.LikeCount,
 			"commentCount": reel.CommentCount,
 			"shareCount":   reel.ShareCount,
-			"viewCount":    reel.ViewCount + 1,
+			"viewCount":    reel.ViewCount,
 			"isLiked":      isLiked,
 			"isSaved":      isSaved,
 			"createdAt":    reel.CreatedAt,
@@ -174,65 +186,157 @@ func CreateReel(c *gin.Context) {
 	// Form verilerini al (multipart/form-data)
 	caption := c.PostForm("caption")
 	music := c.PostForm("music")
-	durationStr := c.PostForm("duration")
-	duration, _ := strconv.Atoi(durationStr)
-
-	// --- Video Dosyasını İşle ---
-	videoFile, videoHeader, err := c.Request.FormFile("video")
-	if err != nil {
+	audience := c.DefaultPostForm("audience", models.AudienceEveryone)
+	if audience != models.AudienceEveryone && audience != models.AudienceCloseFriends {
 		c.JSON(http.StatusBadRequest, Response{
 			Success: false,
-			Message: "Video dosyası alınamadı: " + err.Error(),
+			Message: "Kitle everyone veya close_friends olmalı",
 		})
 		return
 	}
-	defer videoFile.Close()
-
-	// Video dosyasını kaydet
-	videoFilename := generateUniqueFilename(videoHeader.Filename)
-	videoUploadPath := filepath.Join("uploads", "videos", videoFilename)
-	if err := c.SaveUploadedFile(videoHeader, videoUploadPath); err != nil {
-		c.JSON(http.StatusInternalServerError, Response{
+	commentPermission := c.PostForm("commentPermission") // Boşsa hesap ayarı geçerli
+	if commentPermission != "" && !isValidCommentPermission(commentPermission) {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Yorum izni all, followers veya none olmalı",
+		})
+		return
+	}
+	// Taslak olarak kaydetme veya ileri bir zamana zamanlama (RFC3339)
+	var scheduledAt *time.Time
+	if value := c.PostForm("scheduledAt"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Message: "Geçersiz yayın zamanı: RFC3339 formatında olmalı",
+			})
+			return
+		}
+		scheduledAt = &parsed
+	}
+	publishStatus, scheduledAt, err := resolvePublishStatus(c.PostForm("draft") == "true", scheduledAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
 			Success: false,
-			Message: "Video dosyası kaydedilemedi: " + err.Error(),
+			Message: err.Error(),
 		})
 		return
 	}
-	videoURL := "/api/videos/" + videoFilename // API üzerinden erişim için URL
 
-	// --- Thumbnail Dosyasını İşle (Opsiyonel) ---
-	thumbnailURL := "" // Varsayılan boş
+	// --- Thumbnail Dosyasını Al (Opsiyonel) ---
+	// Kapak da kotaya sayıldığı için video kaydedilmeden önce alınır ve doğrulanır
 	thumbnailFile, thumbnailHeader, err := c.Request.FormFile("thumbnail")
+	var thumbnailSize int64
+	var thumbnailContentType string
 	if err == nil { // Hata yoksa thumbnail var demektir
 		defer thumbnailFile.Close()
+		thumbnailContentType, err = sniffUploadedFile(thumbnailHeader)
+		if err != nil || !strings.HasPrefix(thumbnailContentType, "image/") {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Message: "Kapak dosyası bir görsel olmalı",
+			})
+			return
+		}
+		thumbnailSize = thumbnailHeader.Size
+	} else if err != http.ErrMissingFile {
+		// Dosya yok hatası dışındaki hataları logla
+		fmt.Println("Thumbnail alınırken hata:", err)
+	}
+
+	// --- Video Dosyasını İşle ---
+	// Video ya devam ettirilebilir yükleme ile önceden yüklenmiş olabilir (uploadId) ya da formda gönderilir.
+	// Süre istemciden alınmaz; video işlenirken ffprobe ile ölçülür.
+	var videoURL string
+	var uploadSession *models.UploadSession
+	if uploadID := c.PostForm("uploadId"); uploadID != "" {
+		session, err := consumeUpload(userID.(uint), uploadID, "video")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Message: "Yüklenen video kullanılamıyor: " + err.Error(),
+			})
+			return
+		}
+		if err := checkUploadQuota(userID.(uint), thumbnailSize); err != nil {
+			releaseUpload(session)
+			respondQuotaError(c, err)
+			return
+		}
+		uploadSession = session
+		videoURL = session.URL
+	} else {
+		videoFile, videoHeader, err := c.Request.FormFile("video")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Message: "Video dosyası alınamadı: " + err.Error(),
+			})
+			return
+		}
+		defer videoFile.Close()
+
+		videoContentType, err := sniffUploadedFile(videoHeader)
+		if err != nil || !strings.HasPrefix(videoContentType, "video/") {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Message: "Yüklenen dosya bir video değil",
+			})
+			return
+		}
+
+		if err := checkUploadQuota(userID.(uint), videoHeader.Size+thumbnailSize); err != nil {
+			respondQuotaError(c, err)
+			return
+		}
+
+		// Video dosyasını kaydet (aynı video daha önce yüklendiyse mevcut dosya kullanılır)
+		videoFilename := generateUniqueFilename(videoHeader.Filename)
+		media, err := storeUploadedFile(c.Request.Context(), userID.(uint), videosKeyPrefix+videoFilename, "/api/videos/"+videoFilename, videoHeader, videoContentType)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, Response{
+				Success: false,
+				Message: "Video dosyası kaydedilemedi: " + err.Error(),
+			})
+			return
+		}
+		videoURL = media.URL // API üzerinden erişim için URL
+	}
+
+	// --- Thumbnail Dosyasını Kaydet ---
+	thumbnailURL := "" // Varsayılan boş
+	if thumbnailHeader != nil {
 		thumbnailFilename := generateUniqueFilename(thumbnailHeader.Filename)
-		thumbnailUploadPath := filepath.Join("uploads", "thumbnails", thumbnailFilename)
-		if err := c.SaveUploadedFile(thumbnailHeader, thumbnailUploadPath); err != nil {
+		media, err := storeUploadedFile(c.Request.Context(), userID.(uint), thumbnailsKeyPrefix+thumbnailFilename, "/api/thumbnails/"+thumbnailFilename, thumbnailHeader, thumbnailContentType)
+		if err != nil {
 			// Thumbnail kaydetme hatası olursa logla ama devam et
 			fmt.Println("Thumbnail kaydedilemedi:", err)
 		} else {
-			thumbnailURL = "/api/thumbnails/" + thumbnailFilename
+			thumbnailURL = media.URL
 		}
-	} else if err != http.ErrMissingFile {
-		// Dosya yok hatası dışındaki hataları logla
-		fmt.Println("Thumbnail alınırken hata:", err)
 	}
 
 	// Yeni Reel olusştur
 	newReel := models.Reels{
-		UserID:       userID.(uint),
-		Caption:      caption,
-		VideoURL:     videoURL,
-		ThumbnailURL: thumbnailURL, // Eklenen alan
-		Music:        music,
-		Duration:     duration,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		UserID:            userID.(uint),
+		Caption:           caption,
+		VideoURL:          videoURL,
+		ThumbnailURL:      thumbnailURL, // Eklenen alan
+		Status:            models.ReelStatusProcessing,
+		Music:             music,
+		Audience:          audience,
+		CreatedAt:         time.Now(),
+		CommentPermission: commentPermission,
+		PublishStatus:     publishStatus,
+		ScheduledAt:       scheduledAt,
+		UpdatedAt:         time.Now(),
 	}
 
 	// Veritabanına kaydet
 	result := database.DB.Create(&newReel)
 	if result.Error != nil {
+		releaseUpload(uploadSession)
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
 			Message: "Reel kaydedilirken bir hata olusştu: " + result.Error.Error(),
@@ -240,25 +344,48 @@ func CreateReel(c *gin.Context) {
 		return
 	}
 
+	// Video ve kapak dosyalarını reele bağla
+	addMediaReferences(database.DB, models.MediaRefReel, newReel.ID, newReel.VideoURL, newReel.ThumbnailURL)
+
 	// Kullanıcı bilgisini yükle
 	database.DB.Preload("User").First(&newReel, newReel.ID)
 
+	// Açıklamada bahsedilen kullanıcıları kaydet ve bildirim gönder (taslaklarda yayın anında)
+	message := "Reel başarıyla olusşturuldu"
+	switch publishStatus {
+	case models.PublishStatusDraft:
+		message = "Reel taslak olarak kaydedildi"
+	case models.PublishStatusScheduled:
+		message = "Reel zamanlandı"
+	default:
+		saveMentions(c.Request.Context(), newReel.User, models.Mention{ReelID: &newReel.ID}, newReel.Caption)
+	}
+
+	// Video doğrulama, HLS dönüştürme ve kapak çıkarma arka planda yapılır
+	enqueueReelProcessing(newReel.ID)
+
 	c.JSON(http.StatusCreated, Response{
 		Success: true,
-		Message: "Reel başarıyla olusşturuldu",
+		Message: message,
 		Data: gin.H{
-			"id":           newReel.ID,
-			"caption":      newReel.Caption,
-			"videoURL":     newReel.VideoURL,
-			"thumbnailURL": newReel.ThumbnailURL,
-			"music":        newReel.Music,
-			"duration":     newReel.Duration,
-			"user":         newReel.User,
-			"likeCount":    newReel.LikeCount,
-			"commentCount": newReel.CommentCount,
-			"shareCount":   newReel.ShareCount,
-			"viewCount":    newReel.ViewCount,
-			"createdAt":    newReel.CreatedAt,
+			"id":              newReel.ID,
+			"caption":         newReel.Caption,
+			"captionEntities": buildTextEntities(newReel.Caption),
+			"videoURL":        newReel.VideoURL,
+			"thumbnailURL":    newReel.ThumbnailURL,
+			"hlsURL":          newReel.HLSURL,
+			"status":          newReel.Status,
+			"music":           newReel.Music,
+			"duration":        newReel.Duration,
+			"audience":        newReel.Audience,
+			"publishStatus":   newReel.PublishStatus,
+			"scheduledAt":     newReel.ScheduledAt,
+			"user":            newReel.User,
+			"likeCount":       newReel.LikeCount,
+			"commentCount":    newReel.CommentCount,
+			"shareCount":      newReel.ShareCount,
+			"viewCount":       newReel.ViewCount,
+			"createdAt":       newReel.CreatedAt,
 		},
 	})
 }
@@ -280,7 +407,7 @@ func LikeReel(c *gin.Context) {
 
 	// Reelin var olup olmadıgını kontrol et
 	var reel models.Reels
-	if err := database.DB.First(&reel, reelIDUint).Error; err != nil {
+	if err := database.DB.First(&reel, reelIDUint).Error; err != nil || !canViewReel(userID.(uint), reel) {
 		c.JSON(http.StatusNotFound, Response{
 			Success: false,
 			Message: "Reel bulunamadı",
@@ -300,7 +427,13 @@ func LikeReel(c *gin.Context) {
 			CreatedAt: time.Now(),
 		}
 
-		if err := database.DB.Create(&newLike).Error; err != nil {
+		// Begeniyi ve begeni sayısını aynı transaction içinde kaydet
+		if err := database.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&newLike).Error; err != nil {
+				return err
+			}
+			return adjustCounter(tx, &models.Reels{}, likeCountColumn, 1, reel.ID)
+		}); err != nil {
 			c.JSON(http.StatusInternalServerError, Response{
 				Success: false,
 				Message: "Begeni eklenirken bir hata olusştu: " + err.Error(),
@@ -308,9 +441,6 @@ func LikeReel(c *gin.Context) {
 			return
 		}
 
-		// Begeni sayısını artır
-		database.DB.Model(&reel).Update("like_count", gorm.Expr("like_count + ?", 1))
-
 		c.JSON(http.StatusOK, Response{
 			Success: true,
 			Message: "Reel başarıyla begendi",
@@ -348,13 +478,24 @@ func UnlikeReel(c *gin.Context) {
 		return
 	}
 
-	// Kullanıcının begeniğini bul ve sil
-	result := database.DB.Where("user_id = ? AND reel_id = ?", userID, reelIDUint).Delete(&models.ReelLike{})
-
-	if result.RowsAffected > 0 {
-		// Begeni sayısını azalt
-		database.DB.Model(&reel).Update("like_count", gorm.Expr("like_count - ?", 1))
+	// Kullanıcının begeniğini bul, sil ve begeni sayısını aynı transaction içinde azalt
+	var removed int64
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND reel_id = ?", userID, reelIDUint).Delete(&models.ReelLike{})
+		if result.Error != nil {
+			return result.Error
+		}
+		removed = result.RowsAffected
+		return adjustCounter(tx, &models.Reels{}, likeCountColumn, -int(removed), reel.ID)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Message: "Begeni kaldırılırken bir hata olustu: " + err.Error(),
+		})
+		return
+	}
 
+	if removed > 0 {
 		c.JSON(http.StatusOK, Response{
 			Success: true,
 			Message: "Reel begeniği başarıyla kaldırıldı",
@@ -383,7 +524,7 @@ func ShareReel(c *gin.Context) {
 
 	// Reelin var olup olmadıgını kontrol et
 	var reel models.Reels
-	if err := database.DB.First(&reel, reelIDUint).Error; err != nil {
+	if err := database.DB.First(&reel, reelIDUint).Error; err != nil || !canViewReel(c.GetUint("userID"), reel) {
 		c.JSON(http.StatusNotFound, Response{
 			Success: false,
 			Message: "Reel bulunamadı",
@@ -391,21 +532,37 @@ func ShareReel(c *gin.Context) {
 		return
 	}
 
-	// Paylasım sayısını artır
-	result := database.DB.Model(&reel).Update("share_count", gorm.Expr("share_count + ?", 1))
-	if result.Error != nil {
+	// Alıcı belirtilmişse reel direkt mesajla gönderilir
+	var request shareMessageRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+			return
+		}
+	}
+	if len(request.UserIDs) > 0 {
+		request.Type = models.SharedContentReel
+		request.ID = reel.ID
+		shareToConversations(c, request)
+		return
+	}
+
+	// Paylasımı kaydet ve paylasım sayısını aynı transaction içinde artır
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return recordShare(tx, models.ContentTypeReel, reel.ID, c.GetUint("userID"), models.ShareChannelLink, nil)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
-			Message: "Paylasım sayısı güncellenirken hata olusştu: " + result.Error.Error(),
+			Message: "Paylasım sayısı güncellenirken hata olusştu: " + err.Error(),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, Response{
 		Success: true,
-		Message: "Reel paylasım sayısı güncellenmiştir",
+		Message: "Reel paylasım sayısı güncellenmiştir",
 		Data: gin.H{
-			"shareCount": reel.ShareCount + 1,
+			"shareCount": counterValue(database.DB, &models.Reels{}, shareCountColumn, reel.ID),
 		},
 	})
 }
@@ -425,9 +582,14 @@ func GetUserReels(c *gin.Context) {
 		return
 	}
 
-	// Kullanıcının reellerini getir
+	// Kullanıcının reellerini getir; işlenmekte olan veya başarısız reelleri sadece sahibi görür
 	var reels []models.Reels
-	if err := database.DB.Where("user_id = ?", user.ID).Order("created_at DESC").Find(&reels).Error; err != nil {
+	visibilitySQL, visibilityArgs := contentVisibilityClause("reels", c.GetUint("userID"))
+	query := database.DB.Where("user_id = ?", user.ID).Where(visibilitySQL, visibilityArgs...)
+	if currentUserID != user.ID {
+		query = query.Where("status = ?", models.ReelStatusReady)
+	}
+	if err := query.Order("created_at DESC").Find(&reels).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
 			Message: "Reeller getirilirken bir hata olusştu: " + err.Error(),
@@ -451,8 +613,12 @@ func GetUserReels(c *gin.Context) {
 			"caption":      reel.Caption,
 			"videoURL":     reel.VideoURL,
 			"thumbnailURL": reel.ThumbnailURL,
+			"hlsURL":       reel.HLSURL,
+			"status":       reel.Status,
+			"processError": reel.ProcessError,
 			"music":        reel.Music,
 			"duration":     reel.Duration,
+			"audience":     reel.Audience,
 			"likeCount":    reel.LikeCount,
 			"commentCount": reel.CommentCount,
 			"shareCount":   reel.ShareCount,
@@ -494,17 +660,39 @@ func DeleteReel(c *gin.Context) {
 		return
 	}
 
-	// Ilisşkili begeniğileri sil
-	database.DB.Where("reel_id = ?", reelIDUint).Delete(&models.ReelLike{})
+	// Reel ve ilişkili kayıtlar tek transaction içinde silinir; herhangi bir adım başarısız olursa
+	// hiçbir şey silinmez
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		// İlişkili beğenileri sil
+		if err := tx.Where("reel_id = ?", reel.ID).Delete(&models.ReelLike{}).Error; err != nil {
+			return err
+		}
+
+		// İlişkili kaydetmeleri ve koleksiyonlardaki kopyalarını sil
+		if err := tx.Where("reel_id = ?", reel.ID).Delete(&models.SavedReel{}).Error; err != nil {
+			return err
+		}
+		if err := deleteCollectionItems(tx, models.SavedItemReel, reel.ID); err != nil {
+			return err
+		}
+
+		// Paylaşım kayıtlarını ve metinsiz yeniden paylaşımları sil (alıntılar kalır)
+		if err := deleteContentReposts(tx, models.ContentTypeReel, reel.ID); err != nil {
+			return err
+		}
 
-	// Ilisşkili kaydetmeleri sil
-	database.DB.Where("reel_id = ?", reelIDUint).Delete(&models.SavedReel{})
+		// İlişkili yorumları sil
+		if err := tx.Where("reel_id = ?", reel.ID).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
 
-	// Ilisşkili yorumları sil
-	database.DB.Where("reel_id = ?", reelIDUint).Delete(&models.Comment{})
+		// Video ve kapak dosyası başka içerikte kullanılmıyorsa bekleme süresinden sonra
+		// çöp toplayıcı tarafından silinir
+		removeMediaReferences(tx, models.MediaRefReel, reel.ID)
 
-	// Reeli sil
-	if err := database.DB.Delete(&reel).Error; err != nil {
+		return tx.Delete(&reel).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
 			Message: "Reel silinirken bir hata olusştu: " + err.Error(),
@@ -512,6 +700,9 @@ func DeleteReel(c *gin.Context) {
 		return
 	}
 
+	// HLS çıktıları yalnızca bu reele aittir, silme işlemi tamamlandıktan sonra hemen silinir
+	deleteReelHLS(c.Request.Context(), reel.ID)
+
 	c.JSON(http.StatusOK, Response{
 		Success: true,
 		Message: "Reel başarıyla silindi",
@@ -535,7 +726,7 @@ func SaveReel(c *gin.Context) {
 
 	// Reelin var olup olmadığını kontrol et
 	var reel models.Reels
-	if err := database.DB.First(&reel, reelIDUint).Error; err != nil {
+	if err := database.DB.First(&reel, reelIDUint).Error; err != nil || !canViewReel(userID.(uint), reel) {
 		c.JSON(http.StatusNotFound, Response{
 			Success: false,
 			Message: "Reel bulunamadı",
@@ -543,25 +734,35 @@ func SaveReel(c *gin.Context) {
 		return
 	}
 
-	// Kullanıcının zaten kaydetmiş olup olmadığını kontrol et
-	var savedReel models.SavedReel
-	result := database.DB.Where("user_id = ? AND reel_id = ?", userID, reelIDUint).First(&savedReel)
-
-	// Eğer daha önce kaydedilmemişse, kaydet
-	if result.RowsAffected == 0 {
-		newSavedReel := models.SavedReel{
-			UserID: userID.(uint),
-			ReelID: uint(reelIDUint),
-		}
+	// İstenirse reel belirli koleksiyonlara da eklenir (her kayıt varsayılan koleksiyona girer)
+	collectionIDs, err := bindCollectionIDs(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Geçersiz istek: " + err.Error(),
+		})
+		return
+	}
 
-		if err := database.DB.Create(&newSavedReel).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, Response{
-				Success: false,
-				Message: "Reel kaydedilirken bir hata oluştu: " + err.Error(),
-			})
+	// Kullanıcının zaten kaydetmiş olup olmadığını kontrol et
+	var existingCount int64
+	database.DB.Model(&models.SavedReel{}).Where("user_id = ? AND reel_id = ?", userID, reelIDUint).Count(&existingCount)
+
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return saveContent(tx, userID.(uint), models.SavedItemReel, reel.ID, collectionIDs...)
+	}); err != nil {
+		if errors.Is(err, errCollectionNotFound) {
+			respondCollectionError(c, err)
 			return
 		}
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Message: "Reel kaydedilirken bir hata oluştu: " + err.Error(),
+		})
+		return
+	}
 
+	if existingCount == 0 {
 		c.JSON(http.StatusOK, Response{
 			Success: true,
 			Message: "Reel başarıyla kaydedildi",
@@ -589,10 +790,21 @@ func UnsaveReel(c *gin.Context) {
 		return
 	}
 
-	// Kaydı sil
-	result := database.DB.Where("user_id = ? AND reel_id = ?", userID, reelIDUint).Delete(&models.SavedReel{})
+	// Kaydı ve tüm koleksiyonlardaki kopyalarını sil
+	var removed bool
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		removed, err = unsaveItem(tx, userID.(uint), models.SavedItemReel, uint(reelIDUint))
+		return err
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Message: "Reel kaydı kaldırılırken bir hata oluştu: " + err.Error(),
+		})
+		return
+	}
 
-	if result.RowsAffected > 0 {
+	if removed {
 		c.JSON(http.StatusOK, Response{
 			Success: true,
 			Message: "Reel kayıtlardan kaldırıldı",
@@ -612,8 +824,7 @@ func GetReelComments(c *gin.Context) {
 	reelID := c.Param("id")
 	fmt.Printf("🆔 Reel ID: %s\n", reelID)
 
-	// Test için userID kontrolünü geçici olarak kaldıralım
-	// userID, _ := c.Get("userID")
+	userID := c.GetUint("userID")
 
 	// ReelID'yi doğrula
 	reelIDUint, err := strconv.ParseUint(reelID, 10, 32)
@@ -630,7 +841,7 @@ func GetReelComments(c *gin.Context) {
 
 	// Reelin var olup olmadığını kontrol et
 	var reel models.Reels
-	if err := database.DB.First(&reel, reelIDUint).Error; err != nil {
+	if err := database.DB.First(&reel, reelIDUint).Error; err != nil || !canViewReel(userID, reel) {
 		c.JSON(http.StatusNotFound, Response{
 			Success: false,
 			Message: "Reel bulunamadı",
@@ -661,16 +872,35 @@ func GetReelComments(c *gin.Context) {
 		fmt.Printf("🔍 Yorum %d User: ID=%d, Username=%s\n", i, comment.User.ID, comment.User.Username)
 	}
 
-	// Her yorum için beğeni durumunu kontrol et (user ID olmadan)
+	// Kullanıcının yorumu beğenip beğenmediğini kontrol et
+	isCommentLiked := func(commentID uint) bool {
+		var count int64
+		database.DB.Model(&models.CommentLike{}).
+			Where("comment_id = ? AND user_id = ?", commentID, userID).
+			Count(&count)
+		return count > 0
+	}
+
+	// Sayfadaki yorum ve yanıtların mention'ları tek sorguda çözümlenir
+	var texts []string
+	for _, comment := range comments {
+		texts = append(texts, comment.Content)
+		for _, reply := range comment.Replies {
+			texts = append(texts, reply.Content)
+		}
+	}
+	mentionIDs := mentionUserIDs(texts...)
+
+	// Her yorum için beğeni durumunu kontrol et
 	var commentsResponse []models.CommentResponse
 	for _, comment := range comments {
-		// Beğeni durumunu false olarak ayarla (user ID olmadığından)
-		var isLiked bool = false
+		isLiked := isCommentLiked(comment.ID)
 
 		// Alt yorumlar için de beğeni kontrolü
 		var repliesResponse []models.Comment
 		for _, reply := range comment.Replies {
-			reply.IsLiked = false // Test için false
+			reply.IsLiked = isCommentLiked(reply.ID)
+			reply.Entities = textEntities(reply.Content, mentionIDs)
 			repliesResponse = append(repliesResponse, reply)
 		}
 
@@ -685,36 +915,31 @@ func GetReelComments(c *gin.Context) {
 			Replies:   repliesResponse,
 			LikeCount: comment.LikeCount,
 			IsLiked:   isLiked,
+			Entities:  textEntities(comment.Content, mentionIDs),
 			CreatedAt: comment.CreatedAt,
 		}
 
 		commentsResponse = append(commentsResponse, commentResponse)
 	}
 
+	canComment, reason := canCommentOn(userID, reel.UserID, reel.CommentPermission)
+
 	c.JSON(http.StatusOK, Response{
 		Success: true,
 		Message: "Yorumlar başarıyla getirildi",
 		Data: gin.H{
-			"comments": commentsResponse,
-			"total":    len(commentsResponse),
+			"comments":              commentsResponse,
+			"total":                 len(commentsResponse),
+			"canComment":            canComment,
+			"commentPermission":     effectiveCommentPermission(reel.UserID, reel.CommentPermission),
+			"commentDisabledReason": reason,
 		},
 	})
 }
 
 // AddReelComment - Reele yorum ekle
 func AddReelComment(c *gin.Context) {
-	// Test için userID kontrolünü geçici olarak düzenleyelim
-	userIDInterface, exists := c.Get("userID")
-	var userIDUint uint
-
-	if !exists || userIDInterface == nil {
-		// Auth middleware yoksa varsayılan olarak user ID 1 kullan (test için)
-		userIDUint = uint(1)
-		fmt.Println("⚠️ Auth middleware yok, test için userID=1 kullanılıyor")
-	} else {
-		userIDUint = userIDInterface.(uint)
-		fmt.Printf("✅ Auth middleware var, userID=%d\n", userIDUint)
-	}
+	userIDUint := c.GetUint("userID")
 
 	reelID := c.Param("id")
 	fmt.Printf("📝 AddReelComment çağırıldı - ReelID: %s, UserID: %d\n", reelID, userIDUint)
@@ -754,7 +979,7 @@ func AddReelComment(c *gin.Context) {
 
 	// Reelin var olup olmadığını kontrol et
 	var reel models.Reels
-	if err := database.DB.First(&reel, reelIDUint).Error; err != nil {
+	if err := database.DB.First(&reel, reelIDUint).Error; err != nil || !canViewReel(userIDUint, reel) {
 		c.JSON(http.StatusNotFound, Response{
 			Success: false,
 			Message: "Reel bulunamadı",
@@ -762,6 +987,15 @@ func AddReelComment(c *gin.Context) {
 		return
 	}
 
+	// Reel sahibinin yorum iznini kontrol et
+	if allowed, reason := canCommentOn(userIDUint, reel.UserID, reel.CommentPermission); !allowed {
+		c.JSON(http.StatusForbidden, Response{
+			Success: false,
+			Message: reason,
+		})
+		return
+	}
+
 	// Eğer parent comment varsa onun varlığını kontrol et
 	if requestBody.ParentID != nil {
 		var parentComment models.Comment
@@ -785,24 +1019,29 @@ func AddReelComment(c *gin.Context) {
 		UpdatedAt: time.Now(),
 	}
 
-	// Veritabanına kaydet
-	result := database.DB.Create(&newComment)
-	if result.Error != nil {
+	// Yorumu ve yorum sayısını aynı transaction içinde kaydet
+	if err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newComment).Error; err != nil {
+			return err
+		}
+		if requestBody.ParentID != nil { // Sadece ana yorumlar için sayacı artır
+			return nil
+		}
+		return adjustCounter(tx, &models.Reels{}, commentCountColumn, 1, reel.ID)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
-			Message: "Yorum kaydedilirken hata oluştu: " + result.Error.Error(),
+			Message: "Yorum kaydedilirken hata oluştu: " + err.Error(),
 		})
 		return
 	}
 
-	// Yorum sayısını artır
-	if requestBody.ParentID == nil { // Sadece ana yorumlar için sayacı artır
-		database.DB.Model(&reel).Update("comment_count", gorm.Expr("comment_count + ?", 1))
-	}
-
 	// Kullanıcı bilgisini yükle
 	database.DB.Preload("User").First(&newComment, newComment.ID)
 
+	// Yorumda bahsedilen kullanıcıları kaydet ve bildirim gönder
+	saveMentions(c.Request.Context(), newComment.User, models.Mention{ReelID: newComment.ReelID, CommentID: &newComment.ID}, newComment.Content)
+
 	c.JSON(http.StatusCreated, Response{
 		Success: true,
 		Message: "Yorum başarıyla eklendi",
@@ -817,22 +1056,21 @@ func AddReelComment(c *gin.Context) {
 				User:      newComment.User,
 				LikeCount: newComment.LikeCount,
 				IsLiked:   false,
+				Entities:  buildTextEntities(newComment.Content),
 				CreatedAt: newComment.CreatedAt,
 			},
 		},
 	})
 }
 
-// Dosya adı için benzersiz bir isim oluşturur
+// Dosya adı için benzersiz bir isim oluşturur. Orijinal ad kullanılmaz; böylece ad medya
+// doğrulayıcısından geçer ve UUID içerdiği için değişmez (immutable) olarak önbelleklenebilir.
 func generateUniqueFilename(originalFilename string) string {
-	// Zaman damgası ve rastgele bir sayı ekleyerek benzersizlik sağla
-	timestamp := time.Now().Format("20060102150405")
-	// Dosya uzantısını al
-	ext := filepath.Ext(originalFilename)
-	// Yeni dosya adını oluştur (örnek: video_20231027103000_12345.mp4)
-	// Rastgele sayı yerine basit bir UUID veya benzeri bir şey de kullanılabilir
-	base := originalFilename[0 : len(originalFilename)-len(ext)]
-	return fmt.Sprintf("%s_%s%s", base, timestamp, ext)
+	ext := strings.ToLower(filepath.Ext(originalFilename))
+	if !mediaKeySegmentPattern.MatchString("x" + ext) {
+		ext = ""
+	}
+	return fmt.Sprintf("%s-%s%s", uuid.New().String(), time.Now().Format("20060102150405"), ext)
 }
 
 // TestGetReelComments - Test amaçlı reele ait yorumları getir
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pngHeader geçerli bir PNG imzası
+const pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+// reelUploadFile formda gönderilecek bir dosya
+type reelUploadFile struct {
+	field, name, content string
+}
+
+// postReelForm verilen kullanıcı adına multipart reel oluşturma isteği gönderir
+func postReelForm(t *testing.T, userID uint, fields map[string]string, files ...reelUploadFile) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	for _, file := range files {
+		part, err := writer.CreateFormFile(file.field, file.name)
+		if err != nil {
+			t.Fatalf("Form dosyası oluşturulamadı: %v", err)
+		}
+		part.Write([]byte(file.content))
+	}
+	writer.Close()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/reels", func(c *gin.Context) { c.Set("userID", userID) }, CreateReel)
+
+	request := httptest.NewRequest(http.MethodPost, "/reels", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestCreateReelValidatesUploadedFiles(t *testing.T) {
+	setupTestDatabase(t)
+	setupTestBlobStore(t)
+	user := createTestUser(t, "reelci")
+
+	tests := []struct {
+		name  string
+		files []reelUploadFile
+	}{
+		{"video uzantılı metin dosyası", []reelUploadFile{{"video", "klip.mp4", "merhaba dünya"}}},
+		{"görsel olmayan kapak", []reelUploadFile{{"video", "klip.mp4", mp4Header}, {"thumbnail", "kapak.png", "<html></html>"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := postReelForm(t, user.ID, nil, tt.files...)
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("durum %d, beklenen %d: %s", recorder.Code, http.StatusBadRequest, recorder.Body.String())
+			}
+		})
+	}
+
+	var count int64
+	database.DB.Model(&models.Reels{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d reel oluşturuldu, hiç beklenmiyordu", count)
+	}
+}
+
+func TestCreateReelCountsThumbnailAgainstQuota(t *testing.T) {
+	setupTestDatabase(t)
+	setupTestBlobStore(t)
+	user := createTestUser(t, "reelci")
+
+	// Kota yalnızca videoya yeter; kapakla birlikte aşılır
+	limit := int64(len(mp4Header) + 4)
+	if err := database.DB.Create(&models.UserQuota{UserID: user.ID, StorageBytes: &limit}).Error; err != nil {
+		t.Fatalf("Kota oluşturulamadı: %v", err)
+	}
+
+	recorder := postReelForm(t, user.ID, nil,
+		reelUploadFile{"video", "klip.mp4", mp4Header},
+		reelUploadFile{"thumbnail", "kapak.png", pngHeader})
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("durum %d, beklenen %d: %s", recorder.Code, http.StatusRequestEntityTooLarge, recorder.Body.String())
+	}
+}
+
+func TestCreateReelWaitsForProbeWithoutFFmpeg(t *testing.T) {
+	setupTestDatabase(t)
+	setupTestBlobStore(t)
+	user := createTestUser(t, "reelci")
+
+	previous := videoProcessor
+	videoProcessor = nil // ffmpeg kurulu değil
+	t.Cleanup(func() { videoProcessor = previous })
+
+	recorder := postReelForm(t, user.ID, map[string]string{"duration": "5"},
+		reelUploadFile{"video", "klip.mp4", mp4Header},
+		reelUploadFile{"thumbnail", "kapak.png", pngHeader})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("durum %d, beklenen %d: %s", recorder.Code, http.StatusCreated, recorder.Body.String())
+	}
+
+	var reel models.Reels
+	if err := database.DB.First(&reel).Error; err != nil {
+		t.Fatalf("Reel bulunamadı: %v", err)
+	}
+	if reel.Status != models.ReelStatusProcessing {
+		t.Errorf("durum %q, video doğrulanana kadar %q kalmalı", reel.Status, models.ReelStatusProcessing)
+	}
+	if reel.Duration == 5 {
+		t.Errorf("süre %d, istemcinin bildirdiği süre kullanılmamalı", reel.Duration)
+	}
+	if reel.ThumbnailURL == "" {
+		t.Error("kapak kaydedilmedi")
+	}
+}
@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// repostRequest yeniden paylaşım isteği; içerik veya açıklama verilirse alıntı olarak paylaşılır
+type repostRequest struct {
+	Content  string `json:"content"`
+	Caption  string `json:"caption"`
+	Audience string `json:"audience"` // everyone (varsayılan), close_friends
+}
+
+// RepostPost - Gönderiyi takipçilerle yeniden paylaşır veya alıntılar. Metinsiz yeniden paylaşımlar
+// yeniden paylaşılırsa orijinal içerik paylaşılır.
+func RepostPost(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var post models.Post
+	if err := database.DB.First(&post, c.Param("id")).Error; err != nil || !CanViewPost(userID, post) ||
+		!canViewAccountContent(userID, post.UserID) {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Gönderi bulunamadı"})
+		return
+	}
+
+	if isPlainRepost(post) {
+		if post.RepostOfType == models.ContentTypeReel {
+			var reel models.Reels
+			if err := database.DB.First(&reel, *post.RepostOfID).Error; err != nil || !canViewReel(userID, reel) ||
+				!canViewAccountContent(userID, reel.UserID) {
+				c.JSON(http.StatusNotFound, Response{Success: false, Message: "Reel bulunamadı"})
+				return
+			}
+			createRepost(c, models.ContentTypeReel, reel.ID, reel.UserID, reel.Audience, reel.PublishStatus)
+			return
+		}
+		var original models.Post
+		if err := database.DB.First(&original, *post.RepostOfID).Error; err != nil || !CanViewPost(userID, original) ||
+			!canViewAccountContent(userID, original.UserID) {
+			c.JSON(http.StatusNotFound, Response{Success: false, Message: "Gönderi bulunamadı"})
+			return
+		}
+		post = original
+	}
+
+	createRepost(c, models.ContentTypePost, post.ID, post.UserID, post.Audience, post.PublishStatus)
+}
+
+// RepostReel - Reeli takipçilerle yeniden paylaşır veya alıntılar
+func RepostReel(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var reel models.Reels
+	if err := database.DB.First(&reel, c.Param("id")).Error; err != nil || !canViewReel(userID, reel) ||
+		!canViewAccountContent(userID, reel.UserID) {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Reel bulunamadı"})
+		return
+	}
+	if reel.Status != models.ReelStatusReady {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Video işlenmeden reel yeniden paylaşılamaz"})
+		return
+	}
+
+	createRepost(c, models.ContentTypeReel, reel.ID, reel.UserID, reel.Audience, reel.PublishStatus)
+}
+
+// createRepost yeniden paylaşım veya alıntı gönderisini oluşturur, paylaşım kaydını ekler ve
+// orijinal içeriğin sahibine bildirim gönderir
+func createRepost(c *gin.Context, contentType string, contentID, ownerID uint, audience, publishStatus string) {
+	userID := c.GetUint("userID")
+
+	if !isPublished(publishStatus) {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Yayınlanmamış içerik yeniden paylaşılamaz"})
+		return
+	}
+	if reason := repostBlockReason(userID, ownerID, audience); reason != "" {
+		c.JSON(http.StatusForbidden, Response{Success: false, Message: reason})
+		return
+	}
+
+	var request repostRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek: " + err.Error()})
+			return
+		}
+	}
+	if request.Audience == "" {
+		request.Audience = models.AudienceEveryone
+	}
+	if request.Audience != models.AudienceEveryone && request.Audience != models.AudienceCloseFriends {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Kitle everyone veya close_friends olmalı"})
+		return
+	}
+
+	now := time.Now()
+	repost := models.Post{
+		UserID:        userID,
+		Content:       strings.TrimSpace(request.Content),
+		Caption:       strings.TrimSpace(request.Caption),
+		RepostOfType:  contentType,
+		RepostOfID:    &contentID,
+		Audience:      request.Audience,
+		PublishStatus: models.PublishStatusPublished,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	quote := !isPlainRepost(repost)
+
+	// Aynı içerik metinsiz olarak sadece bir kez yeniden paylaşılabilir
+	if !quote {
+		var existing models.Post
+		if err := database.DB.Where("user_id = ? AND repost_of_type = ? AND repost_of_id = ?", userID, contentType, contentID).
+			Where("TRIM(COALESCE(content, '')) = '' AND TRIM(COALESCE(caption, '')) = ''").First(&existing).Error; err == nil {
+			c.JSON(http.StatusOK, Response{
+				Success: true,
+				Message: "Bu içeriği zaten yeniden paylaştınız",
+				Data:    gin.H{"reposted": true, "alreadyReposted": true, "postId": existing.ID},
+			})
+			return
+		}
+	}
+
+	channel := models.ShareChannelRepost
+	if quote {
+		channel = models.ShareChannelQuote
+	}
+	hashtags := utils.ExtractHashtags(repost.Content, repost.Caption)
+	repost.TagsString = strings.Join(hashtags, ",")
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&repost).Error; err != nil {
+			return err
+		}
+		if len(hashtags) > 0 {
+			attachPostTags(tx, repost.ID, hashtags, "primary")
+		}
+		return recordShare(tx, contentType, contentID, userID, channel, &repost.ID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yeniden paylaşım oluşturulamadı: " + err.Error()})
+		return
+	}
+
+	var user models.User
+	database.DB.First(&user, userID)
+	notifyRepost(c.Request.Context(), user, ownerID, repost)
+	if quote {
+		saveMentions(c.Request.Context(), user, models.Mention{PostID: &repost.ID}, repost.Content, repost.Caption)
+	}
+
+	message := "İçerik yeniden paylaşıldı"
+	if quote {
+		message = "İçerik alıntılandı"
+	}
+	shareCount := 0
+	if contentType == models.ContentTypeReel {
+		shareCount = counterValue(database.DB, &models.Reels{}, shareCountColumn, contentID)
+	} else {
+		shareCount = counterValue(database.DB, &models.Post{}, shareCountColumn, contentID)
+	}
+
+	c.JSON(http.StatusCreated, Response{
+		Success: true,
+		Message: message,
+		Data: gin.H{
+			"post": gin.H{
+				"id":              repost.ID,
+				"content":         repost.Content,
+				"caption":         repost.Caption,
+				"contentEntities": buildTextEntities(repost.Content),
+				"captionEntities": buildTextEntities(repost.Caption),
+				"tags":            splitTagsString(repost.TagsString),
+				"audience":        repost.Audience,
+				"repostOf":        repostReference(userID, repost),
+				"createdAt":       "Şimdi",
+				"user": gin.H{
+					"id":           user.ID,
+					"username":     user.Username,
+					"profileImage": user.ProfileImage,
+				},
+			},
+			"reposted":   true,
+			"shareCount": shareCount,
+		},
+	})
+}
+
+// UndoPostRepost - Gönderinin metinsiz yeniden paylaşımını geri alır (alıntılar gönderi gibi silinir)
+func UndoPostRepost(c *gin.Context) {
+	undoRepost(c, models.ContentTypePost)
+}
+
+// UndoReelRepost - Reelin metinsiz yeniden paylaşımını geri alır
+func UndoReelRepost(c *gin.Context) {
+	undoRepost(c, models.ContentTypeReel)
+}
+
+// undoRepost kullanıcının içeriğe ait metinsiz yeniden paylaşımını ve paylaşım kaydını siler
+func undoRepost(c *gin.Context, contentType string) {
+	userID := c.GetUint("userID")
+
+	var repost models.Post
+	if err := database.DB.Where("user_id = ? AND repost_of_type = ? AND repost_of_id = ?", userID, contentType, c.Param("id")).
+		Where("TRIM(COALESCE(content, '')) = '' AND TRIM(COALESCE(caption, '')) = ''").First(&repost).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Bu içeriği yeniden paylaşmadınız"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := removeRepostShares(tx, repost); err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ?", repost.ID).Delete(&models.Like{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ?", repost.ID).Delete(&models.SavedPost{}).Error; err != nil {
+			return err
+		}
+		if err := deleteCollectionItems(tx, models.SavedItemPost, repost.ID); err != nil {
+			return err
+		}
+		return tx.Delete(&repost).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yeniden paylaşım geri alınamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Yeniden paylaşım geri alındı",
+		Data:    gin.H{"reposted": false},
+	})
+}
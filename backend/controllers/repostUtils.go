@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// isPlainRepost gönderinin metin eklenmeden yapılmış bir yeniden paylaşım olup olmadığını belirler
+func isPlainRepost(post models.Post) bool {
+	return post.RepostOfID != nil && strings.TrimSpace(post.Content) == "" && strings.TrimSpace(post.Caption) == ""
+}
+
+// canViewAccountContent kullanıcının hesap sahibinin içeriklerini gizlilik ve engel ayarlarına göre
+// görüp göremeyeceğini belirler (gizli hesapların içeriklerini sadece takipçiler görür)
+func canViewAccountContent(userID, ownerID uint) bool {
+	if userID == ownerID {
+		return true
+	}
+	if isBlockedBetween(userID, ownerID) {
+		return false
+	}
+	var owner models.User
+	if err := database.DB.Select("id, is_private").First(&owner, ownerID).Error; err != nil {
+		return false
+	}
+	if !owner.IsPrivate {
+		return true
+	}
+	var count int64
+	database.DB.Model(&models.Follow{}).Where("follower_id = ? AND following_id = ?", userID, ownerID).Count(&count)
+	return count > 0
+}
+
+// repostBlockReason içeriğin kullanıcı tarafından yeniden paylaşılamama nedenini döndürür (paylaşılabiliyorsa boş).
+// Gizli hesapların ve yakın arkadaşlara özel içeriklerin takipçi dışına taşınmaması için sadece herkese açık
+// içerikler yeniden paylaşılabilir; sahibi kendi içeriğini her zaman paylaşabilir.
+func repostBlockReason(userID, ownerID uint, audience string) string {
+	if userID == ownerID {
+		return ""
+	}
+	if isBlockedBetween(userID, ownerID) {
+		return "Bu içerik yeniden paylaşılamaz"
+	}
+	if audience == models.AudienceCloseFriends {
+		return "Yakın arkadaşlara özel içerikler yeniden paylaşılamaz"
+	}
+	var owner models.User
+	if err := database.DB.Select("id, is_private").First(&owner, ownerID).Error; err != nil || owner.IsPrivate {
+		return "Gizli hesapların içerikleri yeniden paylaşılamaz"
+	}
+	return ""
+}
+
+// recordShare içeriğin paylaşım kaydını oluşturur ve paylaşım sayacını aynı transaction içinde artırır
+func recordShare(tx *gorm.DB, contentType string, contentID, userID uint, channel string, repostID *uint) error {
+	now := time.Now()
+	if contentType == models.ContentTypeReel {
+		share := models.ReelShare{ReelID: contentID, UserID: userID, Channel: channel, RepostID: repostID, CreatedAt: now}
+		if err := tx.Create(&share).Error; err != nil {
+			return err
+		}
+		return adjustCounter(tx, &models.Reels{}, shareCountColumn, 1, contentID)
+	}
+	share := models.PostShare{PostID: contentID, UserID: userID, Channel: channel, RepostID: repostID, CreatedAt: now}
+	if err := tx.Create(&share).Error; err != nil {
+		return err
+	}
+	return adjustCounter(tx, &models.Post{}, shareCountColumn, 1, contentID)
+}
+
+// removeRepostShares silinen yeniden paylaşım gönderisine ait paylaşım kaydını siler ve orijinalin sayacını azaltır
+func removeRepostShares(tx *gorm.DB, repost models.Post) error {
+	if repost.RepostOfID == nil {
+		return nil
+	}
+	if repost.RepostOfType == models.ContentTypeReel {
+		result := tx.Where("repost_id = ?", repost.ID).Delete(&models.ReelShare{})
+		if result.Error != nil {
+			return result.Error
+		}
+		return adjustCounter(tx, &models.Reels{}, shareCountColumn, -int(result.RowsAffected), *repost.RepostOfID)
+	}
+	result := tx.Where("repost_id = ?", repost.ID).Delete(&models.PostShare{})
+	if result.Error != nil {
+		return result.Error
+	}
+	return adjustCounter(tx, &models.Post{}, shareCountColumn, -int(result.RowsAffected), *repost.RepostOfID)
+}
+
+// deleteContentReposts silinen içeriğin paylaşım kayıtlarını ve metinsiz yeniden paylaşımlarını siler.
+// Alıntı gönderileri yazarlarının metnini taşıdığından kalır; orijinal yerine "kullanılamıyor" gösterilir.
+func deleteContentReposts(tx *gorm.DB, contentType string, contentID uint) error {
+	if contentType == models.ContentTypeReel {
+		if err := tx.Where("reel_id = ?", contentID).Delete(&models.ReelShare{}).Error; err != nil {
+			return err
+		}
+	} else if err := tx.Where("post_id = ?", contentID).Delete(&models.PostShare{}).Error; err != nil {
+		return err
+	}
+
+	var reposts []models.Post
+	if err := tx.Where("repost_of_type = ? AND repost_of_id = ?", contentType, contentID).Find(&reposts).Error; err != nil {
+		return err
+	}
+	for _, repost := range reposts {
+		if !isPlainRepost(repost) {
+			continue
+		}
+		if err := tx.Where("post_id = ?", repost.ID).Delete(&models.Like{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ?", repost.ID).Delete(&models.SavedPost{}).Error; err != nil {
+			return err
+		}
+		if err := deleteCollectionItems(tx, models.SavedItemPost, repost.ID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&repost).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repostReference yeniden paylaşılan içeriğin önizlemesini döndürür (yeniden paylaşım değilse nil).
+// Görünürlük okuma anında kontrol edilir: orijinal silinmişse, yayından kaldırılmışsa veya izleyici
+// artık göremiyorsa sadece "available: false" döner.
+func repostReference(viewerID uint, post models.Post) gin.H {
+	if post.RepostOfID == nil {
+		return nil
+	}
+	reference := gin.H{
+		"type":      post.RepostOfType,
+		"id":        *post.RepostOfID,
+		"quote":     !isPlainRepost(post),
+		"available": false,
+	}
+
+	if post.RepostOfType == models.ContentTypeReel {
+		if preview := reelPreview(viewerID, *post.RepostOfID); preview != nil {
+			reference["available"] = true
+			reference["reel"] = preview
+		}
+		return reference
+	}
+	if preview := postPreview(viewerID, *post.RepostOfID); preview != nil {
+		reference["available"] = true
+		reference["post"] = preview
+	}
+	return reference
+}
+
+// reelPreview reelin gömülü önizlemesini döndürür; izleyici reeli göremiyorsa nil döner
+func reelPreview(viewerID, reelID uint) gin.H {
+	var reel models.Reels
+	if err := database.DB.Preload("User").First(&reel, reelID).Error; err != nil ||
+		reel.Status != models.ReelStatusReady || !canViewReel(viewerID, reel) ||
+		!isPublished(reel.PublishStatus) || !canViewAccountContent(viewerID, reel.UserID) {
+		return nil
+	}
+	return formatReelPreview(reel)
+}
+
+// formatReelPreview görünürlüğü kontrol edilmiş reelin önizlemesini oluşturur (User yüklenmiş olmalı)
+func formatReelPreview(reel models.Reels) gin.H {
+	return gin.H{
+		"id":           reel.ID,
+		"caption":      reel.Caption,
+		"thumbnailURL": reel.ThumbnailURL,
+		"videoURL":     reel.VideoURL,
+		"hlsURL":       reel.HLSURL,
+		"duration":     reel.Duration,
+		"likeCount":    reel.LikeCount,
+		"shareCount":   reel.ShareCount,
+		"createdAt":    reel.CreatedAt,
+		"user": gin.H{
+			"id":           reel.User.ID,
+			"username":     reel.User.Username,
+			"profileImage": reel.User.ProfileImage,
+		},
+	}
+}
+
+// postPreview gönderinin gömülü önizlemesini döndürür; izleyici gönderiyi göremiyorsa nil döner
+func postPreview(viewerID, postID uint) gin.H {
+	var post models.Post
+	if err := database.DB.Preload("User").Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&post, postID).Error; err != nil ||
+		!CanViewPost(viewerID, post) || !isPublished(post.PublishStatus) ||
+		!canViewAccountContent(viewerID, post.UserID) {
+		return nil
+	}
+	return formatPostPreview(post)
+}
+
+// formatPostPreview görünürlüğü kontrol edilmiş gönderinin önizlemesini oluşturur (User ve Images yüklenmiş olmalı)
+func formatPostPreview(post models.Post) gin.H {
+	return gin.H{
+		"id":        post.ID,
+		"content":   post.Content,
+		"caption":   post.Caption,
+		"images":    postImageURLs(post),
+		"likes":     post.LikeCount,
+		"comments":  post.CommentCount,
+		"shares":    post.ShareCount,
+		"createdAt": formatTimeAgo(post.CreatedAt),
+		"user": gin.H{
+			"id":           post.User.ID,
+			"username":     post.User.Username,
+			"profileImage": post.User.ProfileImage,
+		},
+	}
+}
+
+// notifyRepost orijinal içeriğin sahibine yeniden paylaşım veya alıntı bildirimi gönderir
+func notifyRepost(ctx context.Context, reposter models.User, ownerID uint, repost models.Post) {
+	if ownerID == reposter.ID || repost.RepostOfID == nil {
+		return
+	}
+
+	target := "gönderini"
+	if repost.RepostOfType == models.ContentTypeReel {
+		target = "reelini"
+	}
+	message := fmt.Sprintf("%s %s yeniden paylaştı", reposter.Username, target)
+	if !isPlainRepost(repost) {
+		message = fmt.Sprintf("%s %s alıntıladı", reposter.Username, target)
+	}
+
+	notification := models.Notification{
+		ToUserID:   ownerID,
+		FromUserID: reposter.ID,
+		Type:       "repost",
+		Message:    message,
+		IsRead:     false,
+		CreatedAt:  time.Now(),
+	}
+	if err := database.DB.Create(&notification).Error; err != nil {
+		log.Printf("Yeniden paylaşım bildirimi oluşturulamadı: %v", err)
+		return
+	}
+
+	if notifService != nil {
+		if err := notifService.CreateRepostNotification(ctx,
+			fmt.Sprintf("%d", ownerID),
+			fmt.Sprintf("%d", reposter.ID),
+			reposter.FullName,
+			reposter.Username,
+			reposter.ProfileImage,
+			repost.RepostOfType,
+			fmt.Sprintf("%d", *repost.RepostOfID),
+			fmt.Sprintf("%d", repost.ID),
+			message,
+		); err != nil {
+			log.Printf("WebSocket yeniden paylaşım bildirimi gönderilemedi: %v", err)
+		}
+	}
+}
@@ -0,0 +1,408 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	maxSearchTerms     = 8
+)
+
+// type parametresinde kabul edilen değerler
+var searchTypeParams = map[string]string{
+	"user": models.SearchTypeUser, "users": models.SearchTypeUser,
+	"post": models.SearchTypePost, "posts": models.SearchTypePost,
+	"reel": models.SearchTypeReel, "reels": models.SearchTypeReel,
+	"tag": models.SearchTypeTag, "tags": models.SearchTypeTag,
+}
+
+// searchHit arama dizininden dönen tek bir eşleşme
+type searchHit struct {
+	EntityType string
+	EntityID   uint
+	Score      float64
+}
+
+// Search - Kullanıcı, gönderi, reel ve etiketlerde birleşik arama yapar
+// Parametreler: q (arama metni), type (users,posts,reels,tags - virgülle ayrılmış), page, limit
+func Search(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		query = strings.TrimSpace(c.Query("query"))
+	}
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Arama terimi gerekli",
+		})
+		return
+	}
+
+	types, ok := parseSearchTypes(c.Query("type"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Geçersiz arama türü. Kullanılabilir türler: users, posts, reels, tags",
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSearchLimit)))
+	if limit < 1 || limit > maxSearchLimit {
+		limit = defaultSearchLimit
+	}
+
+	if models.SearchIndexTable == "" {
+		c.JSON(http.StatusServiceUnavailable, Response{
+			Success: false,
+			Message: "Arama şu anda kullanılamıyor",
+		})
+		return
+	}
+
+	// Önce tüm kelimelerin önekleriyle tam arama yapılır; hiç sonuç yoksa
+	// yazım hatalarını tolere eden gevşek aramaya geçilir
+	fuzzy := !searchHasMatch(userID, terms, types, false)
+
+	hits, err := runSearch(userID, terms, types, fuzzy, limit+1, (page-1)*limit)
+	if err != nil {
+		fmt.Printf("Arama hatası: %v\n", err)
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Message: "Arama sırasında bir hata oluştu",
+		})
+		return
+	}
+
+	// Sadece ilk sayfa istekleri geçmişe kaydedilir (ayar açıksa)
+	if page == 1 {
+		recordSearchHistory(userID, query, "", nil)
+	}
+
+	hasMore := len(hits) > limit
+	if hasMore {
+		hits = hits[:limit]
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Arama tamamlandı",
+		Data: gin.H{
+			"results": hydrateSearchHits(hits),
+			"page":    page,
+			"limit":   limit,
+			"hasMore": hasMore,
+			"fuzzy":   fuzzy,
+			// false ise sunucu FTS5 olmadan derlenmiştir ve arama kısıtlı moddadır (sıralama yok)
+			"fullText": models.SearchIndexFullText,
+		},
+	})
+}
+
+// searchTerms arama metnini küçük harfli kelimelere böler
+func searchTerms(query string) []string {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	return terms
+}
+
+// parseSearchTypes type parametresini dizin türlerine çevirir (boşsa tüm türler)
+func parseSearchTypes(param string) ([]string, bool) {
+	if strings.TrimSpace(param) == "" {
+		return []string{models.SearchTypeUser, models.SearchTypePost, models.SearchTypeReel, models.SearchTypeTag}, true
+	}
+
+	seen := make(map[string]bool)
+	var types []string
+	for _, part := range strings.Split(param, ",") {
+		entityType, ok := searchTypeParams[strings.ToLower(strings.TrimSpace(part))]
+		if !ok {
+			return nil, false
+		}
+		if !seen[entityType] {
+			seen[entityType] = true
+			types = append(types, entityType)
+		}
+	}
+	return types, true
+}
+
+// fuzzyTerm yazım hatası toleransı için kelimenin ilk yarısını önek olarak bırakır (en az 3 harf)
+func fuzzyTerm(term string) string {
+	runes := []rune(term)
+	keep := (len(runes) + 1) / 2
+	if keep < 3 {
+		keep = 3
+	}
+	if keep >= len(runes) {
+		return term
+	}
+	return string(runes[:keep])
+}
+
+// searchMatchClause metin eşleşme koşulunu ve sıralama ifadesini üretir
+func searchMatchClause(terms []string, fuzzy bool) (string, []interface{}, string, []interface{}) {
+	if models.SearchIndexFullText {
+		parts := make([]string, 0, len(terms))
+		for _, term := range terms {
+			if fuzzy {
+				term = fuzzyTerm(term)
+			}
+			parts = append(parts, `"`+term+`"*`)
+		}
+		separator := " "
+		if fuzzy {
+			separator = " OR "
+		}
+		// Başlık (kullanıcı adı, açıklama, etiket adı) gövdeden daha ağırlıklı
+		return models.SearchIndexTable + " MATCH ?", []interface{}{strings.Join(parts, separator)},
+			"bm25(" + models.SearchIndexTable + ", 0.0, 0.0, 0.0, 10.0, 1.0)", nil
+	}
+
+	// FTS5 yoksa LIKE ile arama
+	conditions := make([]string, 0, len(terms))
+	var args []interface{}
+	for _, term := range terms {
+		if fuzzy {
+			term = fuzzyTerm(term)
+		}
+		pattern := "%" + term + "%"
+		conditions = append(conditions, "(title LIKE ? OR body LIKE ?)")
+		args = append(args, pattern, pattern)
+	}
+	separator := " AND "
+	if fuzzy {
+		separator = " OR "
+	}
+
+	first := terms[0]
+	score := "CASE WHEN LOWER(title) = ? THEN 0 WHEN LOWER(title) LIKE ? THEN 1 WHEN LOWER(title) LIKE ? THEN 2 ELSE 3 END"
+	return "(" + strings.Join(conditions, separator) + ")", args,
+		score, []interface{}{first, first + "%", "%" + first + "%"}
+}
+
+// searchVisibilityClause gizlilik ve engelleme kurallarını uygulayan koşulu üretir
+func searchVisibilityClause(userID uint, types []string) (string, []interface{}) {
+	clause := `entity_type IN ?
+		AND (entity_type = 'tag' OR owner_id IN (SELECT id FROM users WHERE deleted_at IS NULL))
+		AND NOT (entity_type = 'user' AND entity_id = ?)
+		AND owner_id NOT IN (` + blockedUserIDsSQL + `)
+		AND (entity_type IN ('user', 'tag')
+			OR owner_id = ?
+			OR owner_id IN (SELECT id FROM users WHERE is_private = 0)
+			OR owner_id IN (SELECT following_id FROM follows WHERE follower_id = ?))`
+	return clause, []interface{}{types, userID, userID, userID, userID, userID}
+}
+
+// visibleSearchHits canlı aramadaki görünürlük kuralını (engeller, gizli hesaplar, silinmiş ve
+// arşivlenmiş içerikler) verilen eşleşmelere uygular ve sadece görünür olanları sırasıyla döndürür
+func visibleSearchHits(userID uint, hits []searchHit) []searchHit {
+	idsByType := make(map[string][]uint)
+	var types []string
+	for _, hit := range hits {
+		if _, ok := idsByType[hit.EntityType]; !ok {
+			types = append(types, hit.EntityType)
+		}
+		idsByType[hit.EntityType] = append(idsByType[hit.EntityType], hit.EntityID)
+	}
+	if len(types) == 0 {
+		return nil
+	}
+
+	var conditions []string
+	var args []interface{}
+	for _, entityType := range types {
+		conditions = append(conditions, "(entity_type = ? AND entity_id IN ?)")
+		args = append(args, entityType, idsByType[entityType])
+	}
+	visibilitySQL, visibilityArgs := searchVisibilityClause(userID, types)
+
+	var visible []searchHit
+	if err := database.DB.Raw(
+		"SELECT entity_type, entity_id FROM "+models.SearchIndexTable+
+			" WHERE ("+strings.Join(conditions, " OR ")+") AND "+visibilitySQL,
+		append(args, visibilityArgs...)...,
+	).Scan(&visible).Error; err != nil {
+		return nil
+	}
+	allowed := make(map[string]bool, len(visible))
+	for _, hit := range visible {
+		allowed[fmt.Sprintf("%s:%d", hit.EntityType, hit.EntityID)] = true
+	}
+
+	filtered := make([]searchHit, 0, len(visible))
+	for _, hit := range hits {
+		if allowed[fmt.Sprintf("%s:%d", hit.EntityType, hit.EntityID)] {
+			filtered = append(filtered, hit)
+		}
+	}
+	return filtered
+}
+
+// searchHasMatch verilen terimlerle en az bir görünür sonuç olup olmadığını kontrol eder
+func searchHasMatch(userID uint, terms []string, types []string, fuzzy bool) bool {
+	matchSQL, matchArgs, _, _ := searchMatchClause(terms, fuzzy)
+	visibilitySQL, visibilityArgs := searchVisibilityClause(userID, types)
+
+	var count int64
+	database.DB.Raw(
+		"SELECT COUNT(*) FROM (SELECT 1 FROM "+models.SearchIndexTable+" WHERE "+matchSQL+" AND "+visibilitySQL+" LIMIT 1)",
+		append(matchArgs, visibilityArgs...)...,
+	).Scan(&count)
+	return count > 0
+}
+
+// runSearch arama dizininde sıralı ve sayfalanmış sorgu çalıştırır
+func runSearch(userID uint, terms []string, types []string, fuzzy bool, limit, offset int) ([]searchHit, error) {
+	matchSQL, matchArgs, scoreSQL, scoreArgs := searchMatchClause(terms, fuzzy)
+	visibilitySQL, visibilityArgs := searchVisibilityClause(userID, types)
+
+	args := append([]interface{}{}, scoreArgs...)
+	args = append(args, matchArgs...)
+	args = append(args, visibilityArgs...)
+	args = append(args, limit, offset)
+
+	var hits []searchHit
+	err := database.DB.Raw(
+		"SELECT entity_type, entity_id, "+scoreSQL+" AS score FROM "+models.SearchIndexTable+
+			" WHERE "+matchSQL+" AND "+visibilitySQL+
+			" ORDER BY score, entity_id DESC LIMIT ? OFFSET ?",
+		args...,
+	).Scan(&hits).Error
+	return hits, err
+}
+
+// hydrateSearchHits eşleşmeleri türlerine göre toplu yükler ve sıralamayı koruyarak yanıtı oluşturur
+func hydrateSearchHits(hits []searchHit) []gin.H {
+	ids := make(map[string][]uint)
+	for _, hit := range hits {
+		ids[hit.EntityType] = append(ids[hit.EntityType], hit.EntityID)
+	}
+
+	data := make(map[string]map[uint]gin.H)
+	for _, entityType := range []string{models.SearchTypeUser, models.SearchTypePost, models.SearchTypeReel, models.SearchTypeTag} {
+		data[entityType] = make(map[uint]gin.H)
+	}
+
+	if len(ids[models.SearchTypeUser]) > 0 {
+		var users []models.User
+		database.DB.Select("id, username, full_name, profile_image, bio, is_private, is_verified").
+			Where("id IN ?", ids[models.SearchTypeUser]).Find(&users)
+		for _, user := range users {
+			data[models.SearchTypeUser][user.ID] = gin.H{
+				"id":           user.ID,
+				"username":     user.Username,
+				"fullName":     user.FullName,
+				"profileImage": user.ProfileImage,
+				"bio":          user.Bio,
+				"isPrivate":    user.IsPrivate,
+				"isVerified":   user.IsVerified,
+			}
+		}
+	}
+
+	if len(ids[models.SearchTypePost]) > 0 {
+		var posts []models.Post
+		database.DB.Preload("User").Preload("Images").Where("id IN ?", ids[models.SearchTypePost]).Find(&posts)
+		for _, post := range posts {
+			imageURLs := []string{}
+			for _, image := range post.Images {
+				imageURLs = append(imageURLs, image.URL)
+			}
+			data[models.SearchTypePost][post.ID] = gin.H{
+				"id":        post.ID,
+				"content":   post.Content,
+				"caption":   post.Caption,
+				"tags":      splitTagsString(post.TagsString),
+				"likes":     post.LikeCount,
+				"comments":  post.CommentCount,
+				"createdAt": formatTimeAgo(post.CreatedAt),
+				"images":    imageURLs,
+				"user": gin.H{
+					"id":           post.User.ID,
+					"username":     post.User.Username,
+					"profileImage": post.User.ProfileImage,
+				},
+			}
+		}
+	}
+
+	if len(ids[models.SearchTypeReel]) > 0 {
+		var reels []models.Reels
+		database.DB.Preload("User").Where("id IN ? AND status = ?", ids[models.SearchTypeReel], models.ReelStatusReady).Find(&reels)
+		for _, reel := range reels {
+			data[models.SearchTypeReel][reel.ID] = gin.H{
+				"id":           reel.ID,
+				"caption":      reel.Caption,
+				"thumbnailURL": reel.ThumbnailURL,
+				"videoURL":     reel.VideoURL,
+				"hlsURL":       reel.HLSURL,
+				"likeCount":    reel.LikeCount,
+				"viewCount":    reel.ViewCount,
+				"createdAt":    reel.CreatedAt,
+				"user": gin.H{
+					"id":           reel.User.ID,
+					"username":     reel.User.Username,
+					"profileImage": reel.User.ProfileImage,
+				},
+			}
+		}
+	}
+
+	if len(ids[models.SearchTypeTag]) > 0 {
+		var tags []struct {
+			ID        uint
+			Name      string
+			PostCount int
+		}
+		database.DB.Table("tags").
+			Select("tags.id, tags.name, COUNT(post_tags.post_id) AS post_count").
+			Joins("LEFT JOIN post_tags ON post_tags.tag_id = tags.id").
+			Where("tags.id IN ?", ids[models.SearchTypeTag]).
+			Group("tags.id").
+			Scan(&tags)
+		for _, tag := range tags {
+			data[models.SearchTypeTag][tag.ID] = gin.H{
+				"id":        tag.ID,
+				"name":      tag.Name,
+				"postCount": tag.PostCount,
+			}
+		}
+	}
+
+	results := []gin.H{}
+	for _, hit := range hits {
+		item, ok := data[hit.EntityType][hit.EntityID]
+		if !ok {
+			continue
+		}
+		results = append(results, gin.H{
+			"type":  hit.EntityType,
+			"id":    hit.EntityID,
+			"score": hit.Score,
+			"data":  item,
+		})
+	}
+	return results
+}
@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSearchHistoryLimit = 20
+	maxSearchHistoryEntries   = 100 // Kullanıcı başına saklanan en fazla kayıt
+)
+
+// searchHistoryEnabled kullanıcının arama geçmişi ayarını döndürür (kayıt yoksa varsayılan açık)
+func searchHistoryEnabled(userID uint) bool {
+	var settings models.DataPrivacySettings
+	err := database.DB.Select("search_history").Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	return settings.SearchHistory
+}
+
+// recordSearchHistory arama sorgusunu veya tıklanan sonucu geçmişe ekler.
+// Ayar kapalıysa hiçbir şey kaydedilmez; hatalar asıl isteği etkilemez.
+func recordSearchHistory(userID uint, query string, resultType string, resultID *uint) {
+	query = strings.TrimSpace(query)
+	if userID == 0 || (query == "" && resultID == nil) || !searchHistoryEnabled(userID) {
+		return
+	}
+	if len(query) > 200 {
+		query = query[:200]
+	}
+
+	// Aynı sorgu art arda tekrarlanırsa yeni kayıt yerine tarih güncellenir
+	var last models.SearchHistoryEntry
+	if resultID == nil && database.DB.Where("user_id = ?", userID).Order("created_at desc").First(&last).Error == nil &&
+		last.ResultID == nil && strings.EqualFold(last.Query, query) {
+		database.DB.Model(&last).Update("created_at", time.Now())
+		return
+	}
+
+	entry := models.SearchHistoryEntry{
+		UserID:     userID,
+		Query:      query,
+		ResultType: resultType,
+		ResultID:   resultID,
+		CreatedAt:  time.Now(),
+	}
+	if err := database.DB.Create(&entry).Error; err != nil {
+		fmt.Printf("Arama geçmişi kaydedilemedi (UserID: %d): %v\n", userID, err)
+		return
+	}
+
+	// Eski kayıtları temizle
+	database.DB.Where("user_id = ? AND id NOT IN (?)", userID,
+		database.DB.Model(&models.SearchHistoryEntry{}).Select("id").
+			Where("user_id = ?", userID).Order("created_at desc").Limit(maxSearchHistoryEntries),
+	).Delete(&models.SearchHistoryEntry{})
+}
+
+// GetSearchHistory - Kullanıcının son aramalarını getirir
+func GetSearchHistory(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSearchHistoryLimit)))
+	if limit < 1 || limit > maxSearchHistoryEntries {
+		limit = defaultSearchHistoryLimit
+	}
+
+	var entries []models.SearchHistoryEntry
+	if err := database.DB.Where("user_id = ?", userID).
+		Order("created_at desc").Limit(limit).Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Arama geçmişi alınamadı"})
+		return
+	}
+
+	// Tıklanan sonuçların özet bilgilerini ekle. Sonradan görünmez olan içerikler (engellenen veya gizli
+	// hesaba geçen kullanıcılar, arşivlenen gönderiler) canlı aramadaki kuralla elenir.
+	var hits []searchHit
+	for _, entry := range entries {
+		if entry.ResultID != nil {
+			hits = append(hits, searchHit{EntityType: entry.ResultType, EntityID: *entry.ResultID})
+		}
+	}
+	results := make(map[string]gin.H)
+	for _, result := range hydrateSearchHits(visibleSearchHits(userID, hits)) {
+		results[fmt.Sprintf("%v:%v", result["type"], result["id"])] = result["data"].(gin.H)
+	}
+
+	history := []gin.H{}
+	for _, entry := range entries {
+		item := gin.H{
+			"id":        entry.ID,
+			"query":     entry.Query,
+			"createdAt": entry.CreatedAt,
+		}
+		if entry.ResultID != nil {
+			result, ok := results[fmt.Sprintf("%s:%d", entry.ResultType, *entry.ResultID)]
+			if !ok {
+				continue // Silinmiş veya artık görülemeyen içerikler gösterilmez
+			}
+			item["resultType"] = entry.ResultType
+			item["resultId"] = *entry.ResultID
+			item["result"] = result
+		}
+		history = append(history, item)
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Arama geçmişi getirildi",
+		Data: gin.H{
+			"history": history,
+			"enabled": searchHistoryEnabled(userID),
+		},
+	})
+}
+
+// AddSearchHistory - Arama sonuçlarında tıklanan içeriği geçmişe ekler
+func AddSearchHistory(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		Query      string `json:"query"`
+		ResultType string `json:"resultType"`
+		ResultID   *uint  `json:"resultId"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	resultType := ""
+	if request.ResultID != nil {
+		var ok bool
+		if resultType, ok = searchTypeParams[strings.ToLower(request.ResultType)]; !ok {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz sonuç türü"})
+			return
+		}
+	} else if strings.TrimSpace(request.Query) == "" {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Sorgu veya sonuç bilgisi gerekli"})
+		return
+	}
+
+	if !searchHistoryEnabled(userID) {
+		c.JSON(http.StatusOK, Response{Success: true, Message: "Arama geçmişi kapalı, kayıt yapılmadı"})
+		return
+	}
+
+	recordSearchHistory(userID, request.Query, resultType, request.ResultID)
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Arama geçmişine eklendi"})
+}
+
+// DeleteSearchHistoryEntry - Arama geçmişinden tek bir kaydı siler
+func DeleteSearchHistoryEntry(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	entryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz kayıt ID'si"})
+		return
+	}
+
+	result := database.DB.Where("id = ? AND user_id = ?", entryID, userID).Delete(&models.SearchHistoryEntry{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Kayıt silinirken hata oluştu"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Kayıt bulunamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Kayıt arama geçmişinden silindi"})
+}
+
+// ClearSearchHistory - Kullanıcının tüm arama geçmişini siler
+func ClearSearchHistory(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	if err := database.DB.Where("user_id = ?", userID).Delete(&models.SearchHistoryEntry{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Arama geçmişi temizlenirken hata oluştu"})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Arama geçmişi temizlendi"})
+}
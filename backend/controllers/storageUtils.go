@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"social-media-app/backend/models"
+	"social-media-app/backend/storage"
+	"strings"
+)
+
+// blobStore yüklenen tüm medya dosyalarının saklandığı depo
+var blobStore storage.BlobStore
+
+// SetBlobStore medya deposunu ayarlar
+func SetBlobStore(store storage.BlobStore) {
+	blobStore = store
+}
+
+// Depodaki anahtar önekleri
+const (
+	imagesKeyPrefix     = "images/"
+	videosKeyPrefix     = "videos/"
+	thumbnailsKeyPrefix = "thumbnails/"
+	hlsKeyPrefix        = videosKeyPrefix + "hls/" // videos/hls/<reelID>/...
+)
+
+// storageURLPrefixes eski ve yeni medya URL'lerinin depo anahtarlarına karşılıkları
+var storageURLPrefixes = []struct {
+	urlPrefix string
+	keyPrefix string
+}{
+	{"/uploads/", ""},
+	{"/api/files/", ""},
+	{"/api/images/", imagesKeyPrefix},
+	{"/api/videos/", videosKeyPrefix},
+	{"/api/thumbnails/", thumbnailsKeyPrefix},
+}
+
+// storageKeyFromURL uygulamanın ürettiği bir medya URL'sinden depo anahtarını çıkarır.
+// Harici URL'ler için boş döner.
+func storageKeyFromURL(mediaURL string) string {
+	if i := strings.IndexAny(mediaURL, "?#"); i >= 0 {
+		mediaURL = mediaURL[:i]
+	}
+	for _, prefix := range storageURLPrefixes {
+		if strings.HasPrefix(mediaURL, prefix.urlPrefix) {
+			key, err := storage.CleanKey(prefix.keyPrefix + strings.TrimPrefix(mediaURL, prefix.urlPrefix))
+			if err != nil {
+				return ""
+			}
+			return key
+		}
+	}
+	return ""
+}
+
+// storeUploadedFile multipart ile gelen dosyayı verilen anahtarla depoya yazar ve sahiplik kaydını döndürür.
+// Aynı içerik daha önce yüklendiyse mevcut dosyanın kaydı döner.
+func storeUploadedFile(ctx context.Context, ownerID uint, key, url string, header *multipart.FileHeader, contentType string) (*models.Media, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if contentType == "" {
+		contentType = header.Header.Get("Content-Type")
+	}
+	return storeMedia(ctx, ownerID, key, url, contentType, file, func() error {
+		_, err := blobStore.Put(ctx, key, file, header.Size, contentType)
+		return err
+	})
+}
+
+// sniffUploadedFile multipart dosyanın ilk baytlarından içerik tipini belirler; istemcinin bildirdiği
+// Content-Type'a güvenilmez. Uzantıdaki tip ancak dosya o formatın imzasını taşıyorsa kabul edilir.
+func sniffUploadedFile(header *multipart.FileHeader) (string, error) {
+	file, err := header.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return mediaContentType(header.Filename, head[:n]), nil
+}
+
+// deleteStoredMedia medya dosyasını ve varsa görsel varyantlarını depodan siler; hatalar yalnızca loglanır.
+// Dosyanın başka içerikte kullanılmadığından emin olunmalıdır (bkz. RunMediaGarbageCollection).
+func deleteStoredMedia(ctx context.Context, key string) {
+	if err := blobStore.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		fmt.Printf("Medya dosyası silinirken hata: %s - %s\n", key, err.Error())
+	}
+	if strings.HasPrefix(key, imagesKeyPrefix) {
+		deleteImageVariants(ctx, key)
+	}
+}
@@ -0,0 +1,518 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultStoryViewerLimit = 50
+	maxStoryViewerLimit     = 200
+
+	// Hikaye sırası yakınlık ağırlıkları (son 30 gün)
+	storyAffinityWindow        = 30 * 24 * time.Hour
+	storyMessageAffinityWeight = 3
+	storyCommentAffinityWeight = 2
+	storyLikeAffinityWeight    = 1
+	storyViewAffinityWeight    = 1
+)
+
+// storyResponse hikayeyi istemci formatına çevirir; görüntülenme sayısı sadece sahibine gösterilir
+func storyResponse(story models.Story, viewerID uint, seen bool) gin.H {
+	response := gin.H{
+		"id":        story.ID,
+		"userId":    story.UserID,
+		"mediaUrl":  story.MediaURL,
+		"mediaType": story.MediaType,
+		"caption":   story.Caption,
+		"audience":  story.Audience,
+		"seen":      seen,
+		"createdAt": story.CreatedAt,
+		"expiresAt": story.ExpiresAt,
+	}
+	if story.UserID == viewerID {
+		response["viewCount"] = story.ViewCount
+	}
+	return response
+}
+
+// canViewStory kullanıcının hikayeyi görüp göremeyeceğini belirler: sahibi her zaman görür; diğerleri
+// sahibini takip etmeli, aralarında engel olmamalı ve yakın arkadaş hikayelerinde listede bulunmalıdır
+func canViewStory(viewerID uint, story models.Story) bool {
+	if story.UserID == viewerID {
+		return true
+	}
+	if time.Now().After(story.ExpiresAt) || isBlockedBetween(viewerID, story.UserID) {
+		return false
+	}
+	var follow models.Follow
+	if err := database.DB.Where("follower_id = ? AND following_id = ?", viewerID, story.UserID).First(&follow).Error; err != nil {
+		return false
+	}
+	return story.Audience != models.StoryAudienceCloseFriends || follow.IsCloseFriend
+}
+
+// followedStoriesQuery izleyicinin takip ettiği kullanıcıların görebileceği aktif hikayelerini seçer.
+// canViewStory'nin SQL karşılığıdır; liste uç noktalarında hikaye başına sorgu yapılmasını önler.
+func followedStoriesQuery(viewerID uint, now time.Time) *gorm.DB {
+	return database.DB.Table("stories").
+		Select("stories.*").
+		Joins("JOIN follows ON follows.following_id = stories.user_id AND follows.follower_id = ?", viewerID).
+		Where("stories.expires_at > ?", now).
+		Where("stories.audience <> ? OR follows.is_close_friend = ?", models.StoryAudienceCloseFriends, true).
+		Where("stories.user_id NOT IN ("+blockedUserIDsSQL+")", viewerID, viewerID)
+}
+
+// findViewableStory :id parametresindeki aktif hikayeyi getirir, görme yetkisi yoksa 404 döner
+func findViewableStory(c *gin.Context, viewerID uint) (models.Story, bool) {
+	var story models.Story
+	storyID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err == nil {
+		err = database.DB.Where("id = ? AND expires_at > ?", storyID, time.Now()).First(&story).Error
+	}
+	if err != nil || !canViewStory(viewerID, story) {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Hikaye bulunamadı"})
+		return story, false
+	}
+	return story, true
+}
+
+// seenStoryIDs kullanıcının verilen hikayelerden gördüklerini döndürür
+func seenStoryIDs(viewerID uint, storyIDs []uint) map[uint]bool {
+	seen := make(map[uint]bool)
+	if len(storyIDs) == 0 {
+		return seen
+	}
+	var ids []uint
+	database.DB.Model(&models.StoryView{}).
+		Where("viewer_id = ? AND story_id IN ?", viewerID, storyIDs).
+		Pluck("story_id", &ids)
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return seen
+}
+
+// CreateStory - Yeni hikaye paylaşır. Medya, tamamlanmış devam ettirilebilir yüklemenin kimliği (uploadId)
+// veya kullanıcının kendi yüklediği bir dosyanın URL'si (mediaUrl) ile verilir; dış adresler kabul edilmez.
+func CreateStory(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var request struct {
+		MediaURL  string `json:"mediaUrl"`
+		UploadID  string `json:"uploadId"`
+		MediaType string `json:"mediaType" binding:"required"`
+		Caption   string `json:"caption"`
+		Audience  string `json:"audience"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz hikaye verisi: " + err.Error()})
+		return
+	}
+
+	if request.MediaType != "image" && request.MediaType != "video" {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Medya türü image veya video olmalı"})
+		return
+	}
+	if request.Audience == "" {
+		request.Audience = models.StoryAudienceFollowers
+	}
+	if request.Audience != models.StoryAudienceFollowers && request.Audience != models.StoryAudienceCloseFriends {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Kitle followers veya close_friends olmalı"})
+		return
+	}
+
+	var uploadSession *models.UploadSession
+	if request.UploadID != "" {
+		session, err := consumeUpload(userID, request.UploadID, request.MediaType)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Yüklenen dosya kullanılamıyor: " + err.Error()})
+			return
+		}
+		uploadSession = session
+		request.MediaURL = session.URL
+	}
+	if strings.TrimSpace(request.MediaURL) == "" {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Hikaye için medya gereklidir"})
+		return
+	}
+	if uploadSession == nil {
+		media, found := ownedMedia(userID, request.MediaURL)
+		if !found {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Hikaye medyası sizin yüklediğiniz bir dosya olmalı"})
+			return
+		}
+		if !strings.HasPrefix(media.MimeType, request.MediaType+"/") {
+			c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Dosya türü medya türüyle uyuşmuyor"})
+			return
+		}
+	}
+	if err := checkStorageQuota(userID); err != nil {
+		releaseUpload(uploadSession)
+		respondQuotaError(c, err)
+		return
+	}
+
+	now := time.Now()
+	story := models.Story{
+		UserID:    userID,
+		MediaURL:  request.MediaURL,
+		MediaType: request.MediaType,
+		Caption:   request.Caption,
+		Audience:  request.Audience,
+		ExpiresAt: now.Add(models.StoryLifetime),
+		CreatedAt: now,
+	}
+	if err := database.DB.Create(&story).Error; err != nil {
+		releaseUpload(uploadSession)
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Hikaye oluşturulamadı: " + err.Error()})
+		return
+	}
+	addMediaReferences(database.DB, models.MediaRefStory, story.ID, story.MediaURL)
+
+	c.JSON(http.StatusCreated, Response{
+		Success: true,
+		Message: "Hikaye paylaşıldı",
+		Data:    storyResponse(story, userID, false),
+	})
+}
+
+// GetUserStories - Bir kullanıcının görülebilen aktif hikayelerini eskiden yeniye getirir
+func GetUserStories(c *gin.Context) {
+	viewerID := c.GetUint("userID")
+	ownerID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz kullanıcı ID"})
+		return
+	}
+
+	// Sahibi tüm aktif hikayelerini görür; diğerleri için görünürlük sorguda uygulanır
+	var stories []models.Story
+	if uint(ownerID) == viewerID {
+		database.DB.Where("user_id = ? AND expires_at > ?", ownerID, time.Now()).
+			Order("created_at ASC").Find(&stories)
+	} else {
+		followedStoriesQuery(viewerID, time.Now()).Where("stories.user_id = ?", ownerID).
+			Order("stories.created_at ASC").Find(&stories)
+	}
+
+	ids := make([]uint, 0, len(stories))
+	for _, story := range stories {
+		ids = append(ids, story.ID)
+	}
+	seen := seenStoryIDs(viewerID, ids)
+
+	result := []gin.H{}
+	for _, story := range stories {
+		result = append(result, storyResponse(story, viewerID, seen[story.ID]))
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Hikayeler getirildi",
+		Data:    result,
+	})
+}
+
+// GetStoryTray - Takip edilen ve görülmemiş hikayesi olan kullanıcıları yakınlığa göre sıralı getirir
+func GetStoryTray(c *gin.Context) {
+	userID := c.GetUint("userID")
+	now := time.Now()
+
+	// Takip edilen kullanıcıların görülebilen aktif hikayeleri
+	var stories []models.Story
+	followedStoriesQuery(userID, now).Order("stories.created_at ASC").Find(&stories)
+
+	ids := make([]uint, 0, len(stories))
+	for _, story := range stories {
+		ids = append(ids, story.ID)
+	}
+	seen := seenStoryIDs(userID, ids)
+
+	type trayEntry struct {
+		UserID      uint
+		StoryCount  int
+		UnseenCount int
+		FirstUnseen uint // İzlemeye buradan başlanır
+		LatestAt    time.Time
+		Affinity    int
+	}
+	entries := make(map[uint]*trayEntry)
+	for _, story := range stories {
+		entry := entries[story.UserID]
+		if entry == nil {
+			entry = &trayEntry{UserID: story.UserID}
+			entries[story.UserID] = entry
+		}
+		entry.StoryCount++
+		if story.CreatedAt.After(entry.LatestAt) {
+			entry.LatestAt = story.CreatedAt
+		}
+		if !seen[story.ID] {
+			if entry.UnseenCount == 0 {
+				entry.FirstUnseen = story.ID
+			}
+			entry.UnseenCount++
+		}
+	}
+
+	affinity := storyAffinityScores(userID)
+	var tray []*trayEntry
+	for _, entry := range entries {
+		if entry.UnseenCount == 0 {
+			continue
+		}
+		entry.Affinity = affinity[entry.UserID]
+		tray = append(tray, entry)
+	}
+	sort.Slice(tray, func(i, j int) bool {
+		if tray[i].Affinity != tray[j].Affinity {
+			return tray[i].Affinity > tray[j].Affinity
+		}
+		return tray[i].LatestAt.After(tray[j].LatestAt)
+	})
+
+	userIDs := make([]uint, 0, len(tray))
+	for _, entry := range tray {
+		userIDs = append(userIDs, entry.UserID)
+	}
+	users := make(map[uint]models.User)
+	if len(userIDs) > 0 {
+		var found []models.User
+		database.DB.Select("id, username, full_name, profile_image, is_verified").Where("id IN ?", userIDs).Find(&found)
+		for _, user := range found {
+			users[user.ID] = user
+		}
+	}
+
+	result := []gin.H{}
+	for _, entry := range tray {
+		user, ok := users[entry.UserID]
+		if !ok {
+			continue
+		}
+		result = append(result, gin.H{
+			"user": gin.H{
+				"id":           user.ID,
+				"username":     user.Username,
+				"fullName":     user.FullName,
+				"profileImage": user.ProfileImage,
+				"isVerified":   user.IsVerified,
+			},
+			"storyCount":       entry.StoryCount,
+			"unseenCount":      entry.UnseenCount,
+			"firstUnseenStory": entry.FirstUnseen,
+			"latestStoryAt":    entry.LatestAt,
+			"affinityScore":    entry.Affinity,
+		})
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Hikaye sırası getirildi",
+		Data:    result,
+	})
+}
+
+// storyAffinityScores kullanıcının son 30 gündeki mesajlaşma, yorum, beğeni ve hikaye izleme
+// etkileşimlerine göre diğer kullanıcılara yakınlık puanı hesaplar. Hikaye izlemeleri hikayeler
+// silinene kadar tutulduğundan sadece yayındaki hikayeleri kapsar.
+func storyAffinityScores(userID uint) map[uint]int {
+	since := time.Now().Add(-storyAffinityWindow)
+	var rows []struct {
+		UserID uint
+		Total  int
+	}
+	database.DB.Raw(`SELECT user_id, SUM(weight) AS total FROM (
+			SELECT receiver_id AS user_id, ? AS weight FROM messages WHERE sender_id = ? AND sent_at > ?
+			UNION ALL SELECT sender_id, ? FROM messages WHERE receiver_id = ? AND sent_at > ?
+			UNION ALL SELECT posts.user_id, ? FROM comments JOIN posts ON posts.id = comments.post_id
+				WHERE comments.user_id = ? AND comments.created_at > ? AND comments.deleted_at IS NULL
+			UNION ALL SELECT posts.user_id, ? FROM likes JOIN posts ON posts.id = likes.post_id
+				WHERE likes.user_id = ? AND likes.created_at > ?
+			UNION ALL SELECT stories.user_id, ? FROM story_views JOIN stories ON stories.id = story_views.story_id
+				WHERE story_views.viewer_id = ? AND story_views.viewed_at > ?
+		) AS interactions GROUP BY user_id`,
+		storyMessageAffinityWeight, userID, since,
+		storyMessageAffinityWeight, userID, since,
+		storyCommentAffinityWeight, userID, since,
+		storyLikeAffinityWeight, userID, since,
+		storyViewAffinityWeight, userID, since,
+	).Scan(&rows)
+
+	scores := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		scores[row.UserID] = row.Total
+	}
+	return scores
+}
+
+// MarkStoryViewed - Hikayeyi görüldü olarak işaretler; aynı kullanıcı için yalnızca bir kez sayılır
+func MarkStoryViewed(c *gin.Context) {
+	userID := c.GetUint("userID")
+	story, ok := findViewableStory(c, userID)
+	if !ok {
+		return
+	}
+
+	if story.UserID != userID {
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.StoryView{
+				StoryID:  story.ID,
+				ViewerID: userID,
+				ViewedAt: time.Now(),
+			})
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			return adjustCounter(tx, &models.Story{}, viewCountColumn, 1, story.ID)
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Görüntülenme kaydedilemedi"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Hikaye görüldü olarak işaretlendi"})
+}
+
+// GetStoryViewers - Hikayeyi görüntüleyenleri en yeniden eskiye listeler (sadece hikaye sahibi)
+func GetStoryViewers(c *gin.Context) {
+	userID := c.GetUint("userID")
+	story, ok := findViewableStory(c, userID)
+	if !ok {
+		return
+	}
+	if story.UserID != userID {
+		c.JSON(http.StatusForbidden, Response{Success: false, Message: "Sadece hikaye sahibi görüntüleyenleri görebilir"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultStoryViewerLimit)))
+	if limit < 1 || limit > maxStoryViewerLimit {
+		limit = defaultStoryViewerLimit
+	}
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	var views []models.StoryView
+	database.DB.Preload("Viewer").Where("story_id = ?", story.ID).
+		Order("viewed_at DESC").Limit(limit).Offset(offset).Find(&views)
+
+	viewers := []gin.H{}
+	for _, view := range views {
+		viewers = append(viewers, gin.H{
+			"id":           view.Viewer.ID,
+			"username":     view.Viewer.Username,
+			"fullName":     view.Viewer.FullName,
+			"profileImage": view.Viewer.ProfileImage,
+			"viewedAt":     view.ViewedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Görüntüleyenler getirildi",
+		Data: gin.H{
+			"viewCount": story.ViewCount,
+			"viewers":   viewers,
+		},
+	})
+}
+
+// ReplyToStory - Hikayeye yanıt verir; yanıt hikaye sahibine direkt mesaj olarak gönderilir
+func ReplyToStory(c *gin.Context) {
+	userID := c.GetUint("userID")
+	story, ok := findViewableStory(c, userID)
+	if !ok {
+		return
+	}
+	if story.UserID == userID {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Kendi hikayenize yanıt veremezsiniz"})
+		return
+	}
+
+	var input struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz yanıt verisi: " + err.Error()})
+		return
+	}
+
+	response, err := sendDirectMessage(&models.Message{
+		SenderID:   userID,
+		ReceiverID: story.UserID,
+		Content:    input.Content,
+		StoryID:    &story.ID,
+		SentAt:     time.Now(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yanıt gönderilemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Yanıt gönderildi",
+		Data:    response,
+	})
+}
+
+// DeleteStory - Hikayeyi süresi dolmadan siler (sadece hikaye sahibi)
+func DeleteStory(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var story models.Story
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&story).Error; err != nil {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Hikaye bulunamadı veya silme yetkiniz yok"})
+		return
+	}
+
+	deleteStories([]models.Story{story})
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Hikaye silindi"})
+}
+
+// deleteStories hikayeleri görüntülenme kayıtları ve medya referanslarıyla birlikte siler.
+// Medya dosyaları başka içerikte kullanılmıyorsa çöp toplayıcı tarafından silinir.
+func deleteStories(stories []models.Story) {
+	for _, story := range stories {
+		database.DB.Where("story_id = ?", story.ID).Delete(&models.StoryView{})
+		removeMediaReferences(database.DB, models.MediaRefStory, story.ID)
+		database.DB.Delete(&story)
+	}
+}
+
+// StartStoryCleanup süresi dolan hikayeleri periyodik olarak temizler
+func StartStoryCleanup() {
+	go func() {
+		ticker := time.NewTicker(15 * time.Minute)
+		defer ticker.Stop()
+		for {
+			if deleted := cleanupExpiredStories(time.Now()); deleted > 0 {
+				fmt.Printf("%d süresi dolmuş hikaye temizlendi\n", deleted)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// cleanupExpiredStories now anında süresi dolmuş hikayeleri siler ve silinen hikaye sayısını döndürür
+func cleanupExpiredStories(now time.Time) int {
+	var expired []models.Story
+	database.DB.Where("expires_at <= ?", now).Find(&expired)
+	deleteStories(expired)
+	return len(expired)
+}
@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 50
+
+	// Sinyal ağırlıkları
+	friendOfFriendWeight  = 3.0
+	mutualFollowerWeight  = 2.0
+	tagAffinityWeight     = 0.5
+	maxTagAffinityScore   = 10 // Etiket benzerliği tek başına sıralamayı domine etmesin
+	followsYouBonus       = 2.0
+	popularFallbackWeight = 0.01
+)
+
+// userSuggestion bir öneri adayının puanını ve gerekçelerini tutar
+type userSuggestion struct {
+	UserID           uint
+	Score            float64
+	FriendsOfFriends int
+	MutualFollowers  int
+	SharedTags       int
+	FollowsYou       bool
+}
+
+// suggestedUsersEnabled kullanıcının önerilen kullanıcılar ayarını döndürür (kayıt yoksa varsayılan açık)
+func suggestedUsersEnabled(userID uint) bool {
+	var settings models.AISettings
+	err := database.DB.Select("suggested_users").Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	return settings.SuggestedUsers
+}
+
+// GetSuggestedUsers - Takip grafiği ve etiket benzerliğine göre kullanıcı önerileri getirir
+func GetSuggestedUsers(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSuggestionLimit)))
+	if limit < 1 || limit > maxSuggestionLimit {
+		limit = defaultSuggestionLimit
+	}
+
+	// Ayar kapalıysa öneri yapılmaz
+	if !suggestedUsersEnabled(userID) {
+		c.JSON(http.StatusOK, Response{
+			Success: true,
+			Message: "Kullanıcı önerileri kapalı",
+			Data:    []gin.H{},
+		})
+		return
+	}
+
+	candidates := make(map[uint]*userSuggestion)
+	candidate := func(id uint) *userSuggestion {
+		if candidates[id] == nil {
+			candidates[id] = &userSuggestion{UserID: id}
+		}
+		return candidates[id]
+	}
+
+	type signalRow struct {
+		UserID uint
+		Total  int
+	}
+
+	// Takip ettiklerimin takip ettikleri
+	var friendsOfFriends []signalRow
+	database.DB.Raw(`SELECT f2.following_id AS user_id, COUNT(DISTINCT f1.following_id) AS total
+		FROM follows f1
+		JOIN follows f2 ON f2.follower_id = f1.following_id
+		WHERE f1.follower_id = ?
+		GROUP BY f2.following_id`, userID).Scan(&friendsOfFriends)
+	for _, row := range friendsOfFriends {
+		candidate(row.UserID).FriendsOfFriends = row.Total
+	}
+
+	// Beni takip edenlerin de takip ettiği kullanıcılar (ortak takipçiler)
+	var mutualFollowers []signalRow
+	database.DB.Raw(`SELECT f2.following_id AS user_id, COUNT(DISTINCT f1.follower_id) AS total
+		FROM follows f1
+		JOIN follows f2 ON f2.follower_id = f1.follower_id
+		WHERE f1.following_id = ?
+		GROUP BY f2.following_id`, userID).Scan(&mutualFollowers)
+	for _, row := range mutualFollowers {
+		candidate(row.UserID).MutualFollowers = row.Total
+	}
+
+	// Ortak etiket ilgisi
+	var sharedTags []signalRow
+	database.DB.Raw(`SELECT t2.user_id AS user_id, SUM(MIN(t1.count, t2.count)) AS total
+		FROM user_tags t1
+		JOIN user_tags t2 ON t2.tag_id = t1.tag_id AND t2.user_id <> t1.user_id
+		WHERE t1.user_id = ?
+		GROUP BY t2.user_id`, userID).Scan(&sharedTags)
+	for _, row := range sharedTags {
+		candidate(row.UserID).SharedTags = row.Total
+	}
+
+	// Beni takip eden ama benim takip etmediğim kullanıcılar
+	var followersOfMe []uint
+	database.DB.Model(&models.Follow{}).Where("following_id = ?", userID).Pluck("follower_id", &followersOfMe)
+	for _, id := range followersOfMe {
+		candidate(id).FollowsYou = true
+	}
+
+	excluded := suggestionExclusions(userID)
+
+	// Hiç sinyal yoksa (yeni kullanıcı) en çok takip edilen kullanıcılara düş
+	if len(candidates) == 0 {
+		var popular []signalRow
+		database.DB.Raw(`SELECT following_id AS user_id, COUNT(*) AS total
+			FROM follows GROUP BY following_id ORDER BY total DESC LIMIT ?`, maxSuggestionLimit+len(excluded)).Scan(&popular)
+		for _, row := range popular {
+			candidate(row.UserID).Score = float64(row.Total) * popularFallbackWeight
+		}
+	}
+
+	var ranked []*userSuggestion
+	for id, suggestion := range candidates {
+		if excluded[id] {
+			continue
+		}
+		tagScore := suggestion.SharedTags
+		if tagScore > maxTagAffinityScore {
+			tagScore = maxTagAffinityScore
+		}
+		suggestion.Score += friendOfFriendWeight*float64(suggestion.FriendsOfFriends) +
+			mutualFollowerWeight*float64(suggestion.MutualFollowers) +
+			tagAffinityWeight*float64(tagScore)
+		if suggestion.FollowsYou {
+			suggestion.Score += followsYouBonus
+		}
+		ranked = append(ranked, suggestion)
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].UserID < ranked[j].UserID
+	})
+
+	// Silinmiş kullanıcılar sorguda elendiği için limitten fazlasını yükle
+	ids := make([]uint, 0, limit*2)
+	for _, suggestion := range ranked {
+		if len(ids) >= limit*2 {
+			break
+		}
+		ids = append(ids, suggestion.UserID)
+	}
+
+	users := make(map[uint]models.User)
+	if len(ids) > 0 {
+		var found []models.User
+		database.DB.Select("id, username, full_name, profile_image, is_verified, is_private").
+			Where("id IN ?", ids).Find(&found)
+		for _, user := range found {
+			users[user.ID] = user
+		}
+	}
+
+	suggestions := []gin.H{}
+	for _, suggestion := range ranked {
+		if len(suggestions) >= limit {
+			break
+		}
+		user, ok := users[suggestion.UserID]
+		if !ok {
+			continue
+		}
+		suggestions = append(suggestions, gin.H{
+			"id":           user.ID,
+			"username":     user.Username,
+			"fullName":     user.FullName,
+			"profileImage": user.ProfileImage,
+			"isVerified":   user.IsVerified,
+			"isPrivate":    user.IsPrivate,
+			"score":        suggestion.Score,
+			"reasons": gin.H{
+				"friendsOfFriends": suggestion.FriendsOfFriends,
+				"mutualFollowers":  suggestion.MutualFollowers,
+				"sharedTags":       suggestion.SharedTags,
+				"followsYou":       suggestion.FollowsYou,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Kullanıcı önerileri getirildi",
+		Data:    suggestions,
+	})
+}
+
+// suggestionExclusions önerilmeyecek kullanıcıları döndürür: kendisi, takip edilenler,
+// bekleyen istek gönderilenler, engellenenler ve önerilerden kaldırılanlar
+func suggestionExclusions(userID uint) map[uint]bool {
+	excluded := map[uint]bool{userID: true}
+
+	var ids []uint
+	database.DB.Model(&models.Follow{}).Where("follower_id = ?", userID).Pluck("following_id", &ids)
+	for _, id := range ids {
+		excluded[id] = true
+	}
+
+	ids = nil
+	database.DB.Model(&models.FollowRequest{}).Where("follower_id = ? AND status = ?", userID, "pending").
+		Pluck("following_id", &ids)
+	for _, id := range ids {
+		excluded[id] = true
+	}
+
+	ids = nil
+	database.DB.Raw(blockedUserIDsSQL, userID, userID).Scan(&ids)
+	for _, id := range ids {
+		excluded[id] = true
+	}
+
+	ids = nil
+	database.DB.Model(&models.DismissedSuggestion{}).Where("user_id = ?", userID).Pluck("suggested_user_id", &ids)
+	for _, id := range ids {
+		excluded[id] = true
+	}
+
+	return excluded
+}
+
+// DismissSuggestion - Bir kullanıcıyı önerilerden kalıcı olarak kaldırır
+func DismissSuggestion(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	suggestedID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçersiz kullanıcı ID'si"})
+		return
+	}
+	if uint(suggestedID) == userID {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Kendinizi önerilerden kaldıramazsınız"})
+		return
+	}
+
+	var count int64
+	database.DB.Model(&models.User{}).Where("id = ?", suggestedID).Count(&count)
+	if count == 0 {
+		c.JSON(http.StatusNotFound, Response{Success: false, Message: "Kullanıcı bulunamadı"})
+		return
+	}
+
+	dismissed := models.DismissedSuggestion{
+		UserID:          userID,
+		SuggestedUserID: uint(suggestedID),
+		CreatedAt:       time.Now(),
+	}
+	if err := database.DB.Where("user_id = ? AND suggested_user_id = ?", userID, suggestedID).
+		FirstOrCreate(&dismissed).Error; err != nil {
+		fmt.Printf("Öneri kaldırılırken hata (UserID: %d): %v\n", userID, err)
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Öneri kaldırılırken hata oluştu"})
+		return
+	}
+
+	c.JSON(http.StatusOK, Response{Success: true, Message: "Kullanıcı önerilerden kaldırıldı"})
+}
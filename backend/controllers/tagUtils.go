@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/services"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Otomatik etiketlemede ana etiket olarak kullanılacak ilk etiket sayısı
+const primaryGeneratedTagCount = 4
+
+// Gönderi etiketleme işleminin zaman aşımı
+const postTaggingTimeout = 30 * time.Second
+
+// Gönderi etiketleyicisi (routes tarafından ortama göre ayarlanır)
+var postTagger services.Tagger = services.NewKeywordTagger()
+
+// pendingTagging arka planda devam eden etiketleme işleri (testlerde veritabanı değişmeden önce beklenir)
+var pendingTagging sync.WaitGroup
+
+// SetPostTagger gönderi etiketleyicisini ayarlar
+func SetPostTagger(tagger services.Tagger) {
+	postTagger = tagger
+}
+
+// splitTagsString virgülle ayrılmış etiket metnini temizlenmiş bir listeye çevirir
+func splitTagsString(tagsString string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(tagsString, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// mergeTags mevcut etiketlere yenilerini büyük/küçük harf duyarsız tekrar etmeden ekler
+func mergeTags(existing []string, extra []string) []string {
+	seen := make(map[string]bool)
+	merged := []string{}
+	for _, tag := range append(append([]string{}, existing...), extra...) {
+		key := strings.ToLower(strings.TrimSpace(tag))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, strings.TrimSpace(tag))
+	}
+	return merged
+}
+
+// attachPostTags etiketleri bulur/oluşturur ve gönderiyle PostTag ilişkisini kurar.
+// Gönderide zaten bulunan etiketler atlanır. Hata olsa bile diğer etiketlerle devam edilir.
+func attachPostTags(tx *gorm.DB, postID uint, tagNames []string, tagType string) {
+	for _, tagName := range tagNames {
+		tagName = strings.TrimSpace(tagName)
+		if tagName == "" {
+			continue
+		}
+
+		var tag models.Tag
+		if err := tx.Where("name = ?", tagName).FirstOrCreate(&tag, models.Tag{
+			Name:      tagName,
+			Type:      tagType,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}).Error; err != nil {
+			fmt.Printf("Etiket oluşturulurken hata: %v\n", err)
+			continue
+		}
+
+		var existing int64
+		tx.Model(&models.PostTag{}).Where("post_id = ? AND tag_id = ?", postID, tag.ID).Count(&existing)
+		if existing > 0 {
+			continue
+		}
+
+		postTag := models.PostTag{
+			PostID:    postID,
+			TagID:     tag.ID,
+			TagType:   tagType,
+			CreatedAt: time.Now(),
+		}
+		if err := tx.Create(&postTag).Error; err != nil {
+			fmt.Printf("Post-Tag ilişkisi oluşturulurken hata: %v\n", err)
+			continue
+		}
+		if err := adjustCounter(tx, &models.Tag{}, postCountColumn, 1, tag.ID); err != nil {
+			fmt.Printf("Etiket gönderi sayısı güncellenirken hata: %v\n", err)
+		}
+	}
+}
+
+// syncPostTags gönderinin PostTag ilişkilerini verilen etiket listesine eşitler. Listede olmayan
+// etiketler kaldırılır, yeniler ana etiket olarak eklenir; gönderiyi beğenmiş kullanıcıların
+// etiket sayaçları da buna göre güncellenir.
+func syncPostTags(tx *gorm.DB, postID uint, tagNames []string) {
+	wanted := make(map[string]bool)
+	for _, name := range tagNames {
+		wanted[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	var current []models.Tag
+	if err := tx.Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+		Where("post_tags.post_id = ?", postID).Find(&current).Error; err != nil {
+		fmt.Printf("Gönderi etiketleri alınamadı: %v\n", err)
+		return
+	}
+
+	existing := make(map[string]bool)
+	var removed []models.Tag
+	var removedIDs []uint
+	for _, tag := range current {
+		key := strings.ToLower(tag.Name)
+		existing[key] = true
+		if !wanted[key] {
+			removed = append(removed, tag)
+			removedIDs = append(removedIDs, tag.ID)
+		}
+	}
+	if len(removedIDs) > 0 {
+		if err := tx.Where("post_id = ? AND tag_id IN ?", postID, removedIDs).Delete(&models.PostTag{}).Error; err != nil {
+			fmt.Printf("Gönderi etiketleri kaldırılırken hata: %v\n", err)
+		} else {
+			adjustCounter(tx, &models.Tag{}, postCountColumn, -1, removedIDs...)
+			adjustLikerTags(tx, postID, removed, -1)
+		}
+	}
+
+	var added []string
+	for _, name := range mergeTags(nil, tagNames) {
+		if !existing[strings.ToLower(name)] {
+			added = append(added, name)
+		}
+	}
+	if len(added) > 0 {
+		attachPostTags(tx, postID, added, "primary")
+		var addedTags []models.Tag
+		tx.Where("name IN ?", added).Find(&addedTags)
+		adjustLikerTags(tx, postID, addedTags, 1)
+	}
+}
+
+// adjustLikerTags gönderiyi beğenmiş kullanıcıların etiket sayaçlarını gönderiye eklenen (delta 1)
+// veya gönderiden çıkarılan (delta -1) etiketlere göre günceller. Sayacı sıfırlanan etiket silinir.
+func adjustLikerTags(tx *gorm.DB, postID uint, tags []models.Tag, delta int) {
+	var likerIDs []uint
+	tx.Model(&models.Like{}).Where("post_id = ?", postID).Pluck("user_id", &likerIDs)
+	if len(likerIDs) == 0 || len(tags) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, tag := range tags {
+		if delta < 0 {
+			tx.Where("tag_id = ? AND user_id IN ? AND count <= 1", tag.ID, likerIDs).Delete(&models.UserTag{})
+			tx.Model(&models.UserTag{}).Where("tag_id = ? AND user_id IN ?", tag.ID, likerIDs).
+				Updates(map[string]interface{}{"count": gorm.Expr("count - 1"), "updated_at": now})
+			continue
+		}
+
+		for _, likerID := range likerIDs {
+			userTag := models.UserTag{
+				UserID:      likerID,
+				TagID:       tag.ID,
+				TagName:     tag.Name,
+				TagType:     "primary",
+				Count:       1,
+				LastAddedAt: now,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}, {Name: "tag_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"count":         gorm.Expr("user_tags.count + 1"),
+					"last_added_at": now,
+					"updated_at":    now,
+				}),
+			}).Create(&userTag).Error; err != nil {
+				fmt.Printf("Kullanıcı etiketi güncellenemedi: %v\n", err)
+			}
+		}
+	}
+}
+
+// tagPostAsync gönderiyi arka planda etiketler; istemciye dönen yanıtı bekletmez
+func tagPostAsync(post models.Post, imageURLs []string) {
+	pendingTagging.Add(1)
+	go func() {
+		defer pendingTagging.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), postTaggingTimeout)
+		defer cancel()
+
+		if err := tagPost(ctx, post, imageURLs); err != nil {
+			log.Printf("Gönderi %d etiketlenemedi: %v", post.ID, err)
+		}
+	}()
+}
+
+// tagPost etiketleyiciden gelen önerileri gönderiye ana ve yardımcı etiket olarak ekler.
+// Yazarın yapay zeka üretimi ayarı kapalıysa hiçbir şey yapmaz.
+func tagPost(ctx context.Context, post models.Post, imageURLs []string) error {
+	if postTagger == nil || !aiGenerationEnabled(post.UserID) {
+		return nil
+	}
+
+	tags, err := postTagger.SuggestTags(ctx, services.TagInput{
+		Content:   post.Content,
+		Caption:   post.Caption,
+		ImageURLs: imageURLs,
+	})
+	if err != nil {
+		return err
+	}
+	if len(tags) > services.MaxGeneratedTags {
+		tags = tags[:services.MaxGeneratedTags]
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	primaryCount := primaryGeneratedTagCount
+	if len(tags) < primaryCount {
+		primaryCount = len(tags)
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		attachPostTags(tx, post.ID, tags[:primaryCount], "primary")
+		attachPostTags(tx, post.ID, tags[primaryCount:], "auxiliary")
+
+		// Güncel etiket metnine yeni etiketleri ekle
+		var current models.Post
+		if err := tx.Select("id, tags_string").First(&current, post.ID).Error; err != nil {
+			return err
+		}
+		tagsString := strings.Join(mergeTags(splitTagsString(current.TagsString), tags), ",")
+		return tx.Model(&current).Update("tags_string", tagsString).Error
+	})
+}
+
+// aiGenerationEnabled kullanıcının yapay zeka üretimi ayarını döndürür (kayıt yoksa varsayılan açık)
+func aiGenerationEnabled(userID uint) bool {
+	var settings models.AISettings
+	err := database.DB.Where("user_id = ?", userID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true
+	}
+	if err != nil {
+		log.Printf("Yapay zeka ayarları alınamadı: %v", err)
+		return false
+	}
+	return settings.AIGeneration
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/services"
+)
+
+// recordingTagger sabit etiketler döndürür ve kaç kez çağrıldığını sayar
+type recordingTagger struct {
+	tags  []string
+	calls int
+}
+
+func (t *recordingTagger) SuggestTags(ctx context.Context, input services.TagInput) ([]string, error) {
+	t.calls++
+	return t.tags, nil
+}
+
+// setupTagTest geçici bir veritabanı ve etiketleyici kurar, bir kullanıcı ile gönderisini oluşturur
+func setupTagTest(t *testing.T) (*recordingTagger, models.Post) {
+	t.Helper()
+
+	setupTestDatabase(t)
+
+	tagger := &recordingTagger{tags: []string{"doğa", "deniz", "seyahat", "kahve", "gün batımı"}}
+	previous := postTagger
+	SetPostTagger(tagger)
+	t.Cleanup(func() { SetPostTagger(previous) })
+
+	user := createTestUser(t, "yazar")
+	post := models.Post{UserID: user.ID, Content: "Sahilde gün batımı"}
+	if err := database.DB.Create(&post).Error; err != nil {
+		t.Fatalf("Gönderi oluşturulamadı: %v", err)
+	}
+	return tagger, post
+}
+
+func TestTagPostAddsPrimaryAndAuxiliaryTags(t *testing.T) {
+	tagger, post := setupTagTest(t)
+
+	if err := tagPost(context.Background(), post, nil); err != nil {
+		t.Fatalf("tagPost: %v", err)
+	}
+	if tagger.calls != 1 {
+		t.Fatalf("etiketleyici %d kez çağrıldı, 1 bekleniyordu", tagger.calls)
+	}
+
+	var postTags []models.PostTag
+	database.DB.Where("post_id = ?", post.ID).Find(&postTags)
+	if len(postTags) != len(tagger.tags) {
+		t.Fatalf("%d etiket eklendi, %d bekleniyordu", len(postTags), len(tagger.tags))
+	}
+	primary := 0
+	for _, postTag := range postTags {
+		if postTag.TagType == "primary" {
+			primary++
+		}
+	}
+	if primary != primaryGeneratedTagCount {
+		t.Errorf("%d ana etiket eklendi, %d bekleniyordu", primary, primaryGeneratedTagCount)
+	}
+
+	var stored models.Post
+	database.DB.First(&stored, post.ID)
+	if stored.TagsString != "doğa,deniz,seyahat,kahve,gün batımı" {
+		t.Errorf("tags_string = %q", stored.TagsString)
+	}
+}
+
+func TestTagPostRespectsAIGenerationSetting(t *testing.T) {
+	tagger, post := setupTagTest(t)
+
+	settings := models.AISettings{UserID: post.UserID}
+	if err := database.DB.Create(&settings).Error; err != nil {
+		t.Fatalf("Ayarlar oluşturulamadı: %v", err)
+	}
+	// AIGeneration varsayılanı true olduğundan false değeri ayrıca yazılmalı
+	if err := database.DB.Model(&settings).Update("ai_generation", false).Error; err != nil {
+		t.Fatalf("Ayar güncellenemedi: %v", err)
+	}
+
+	if err := tagPost(context.Background(), post, nil); err != nil {
+		t.Fatalf("tagPost: %v", err)
+	}
+	if tagger.calls != 0 {
+		t.Fatalf("yapay zeka üretimi kapalıyken etiketleyici %d kez çağrıldı", tagger.calls)
+	}
+
+	var count int64
+	database.DB.Model(&models.PostTag{}).Where("post_id = ?", post.ID).Count(&count)
+	if count != 0 {
+		t.Errorf("yapay zeka üretimi kapalıyken %d etiket eklendi", count)
+	}
+	var stored models.Post
+	database.DB.First(&stored, post.ID)
+	if stored.TagsString != "" {
+		t.Errorf("tags_string = %q, boş bekleniyordu", stored.TagsString)
+	}
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/storage"
+)
+
+// setupTestDatabase testi geçici dizindeki boş bir SQLite veritabanına bağlar
+func setupTestDatabase(t *testing.T) {
+	t.Helper()
+	t.Setenv("SQLITE_DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	database.ConnectDatabase()
+	// Arka plan işleri bir sonraki testin veritabanına yazmasın
+	t.Cleanup(pendingTagging.Wait)
+}
+
+// createTestUser verilen kullanıcı adıyla bir kullanıcı oluşturur
+func createTestUser(t *testing.T, username string) models.User {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com", Password: "x"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("Kullanıcı oluşturulamadı: %v", err)
+	}
+	return user
+}
+
+// setupTestBlobStore medya deposunu testin geçici dizinindeki bir LocalStore ile değiştirir
+func setupTestBlobStore(t *testing.T) *storage.LocalStore {
+	t.Helper()
+	store, err := storage.NewLocalStore(t.TempDir(), "/api/files", []byte("test-signing-key"))
+	if err != nil {
+		t.Fatalf("LocalStore oluşturulamadı: %v", err)
+	}
+	previous := blobStore
+	SetBlobStore(store)
+	t.Cleanup(func() { SetBlobStore(previous) })
+	return store
+}
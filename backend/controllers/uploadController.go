@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+	"path"
 	"path/filepath"
+	"social-media-app/backend/services"
 	"strings"
 	"time"
 
@@ -28,61 +31,6 @@ func UploadImage(c *gin.Context) {
 	userID, _ := c.Get("userID")
 	fmt.Printf("Kullanıcı %v için görsel yükleme işlemi başlatıldı\n", userID)
 
-	// Çalışma dizinini al ve yazdır
-	workDir, err := os.Getwd()
-	if err != nil {
-		fmt.Printf("Çalışma dizini alınamadı: %s\n", err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Çalışma dizini alınamadı: " + err.Error(),
-		})
-		return
-	}
-	fmt.Printf("Çalışma dizini: %s\n", workDir)
-
-	// Upload dizinini tam yolunu oluştur
-	uploadDir := filepath.Join(workDir, "uploads", "images")
-	fmt.Printf("Upload dizini: %s\n", uploadDir)
-
-	// Dizinin varlığını kontrol et
-	_, statErr := os.Stat(uploadDir)
-	if os.IsNotExist(statErr) {
-		fmt.Printf("Upload dizini mevcut değil, oluşturuluyor: %s\n", uploadDir)
-		// Dizini oluştur (gerekiyorsa üst dizinleri de)
-		if err := os.MkdirAll(uploadDir, 0755); err != nil {
-			fmt.Printf("Upload dizini oluşturma hatası: %s\n", err.Error())
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"message": "Upload dizini oluşturulamadı: " + err.Error(),
-			})
-			return
-		}
-		fmt.Printf("Upload dizini başarıyla oluşturuldu\n")
-	} else if statErr != nil {
-		// Başka bir hata oluştu
-		fmt.Printf("Dizin kontrolü sırasında hata: %s\n", statErr.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Dizin kontrolü sırasında hata: " + statErr.Error(),
-		})
-		return
-	}
-
-	// Dizin izinlerini kontrol et
-	testFile := filepath.Join(uploadDir, "test.txt")
-	testData := []byte("test")
-
-	if err := os.WriteFile(testFile, testData, 0644); err != nil {
-		fmt.Printf("Dizin yazma izni kontrolü başarısız: %s\n", err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Upload dizinine yazma izniniz yok: " + err.Error(),
-		})
-		return
-	}
-	os.Remove(testFile) // Test dosyasını sil
-	fmt.Printf("Dizin yazma izni kontrolü başarılı\n")
-
 	// 10MB maksimum boyut sınırlaması
 	if err := c.Request.ParseMultipartForm(maxUploadSize); err != nil {
 		fmt.Printf("Form verisi işleme hatası: %s\n", err.Error())
@@ -118,7 +66,13 @@ func UploadImage(c *gin.Context) {
 		return
 	}
 
-	// Dosya içeriğini hafızada kontrol et (ilk birkaç byte)
+	// Kullanıcının depolama kotasını ve günlük yükleme sınırını kontrol et
+	if err := checkUploadQuota(userID.(uint), fileHeader.Size); err != nil {
+		respondQuotaError(c, err)
+		return
+	}
+
+	// Dosya içeriğini hafızada kontrol et (ilk birkaç byte)
 	buff := make([]byte, 512)
 	_, err = file.Read(buff)
 	if err != nil {
@@ -156,44 +110,42 @@ func UploadImage(c *gin.Context) {
 	fileName := fmt.Sprintf("%s-%s%s", uuid.New().String(), time.Now().Format("20060102150405"), fileExt)
 	fmt.Printf("Yeni dosya adı: %s\n", fileName)
 
-	// Dosyanın tam yolunu oluştur
-	uploadPath := filepath.Join(uploadDir, fileName)
-	fmt.Printf("Dosya kaydedilecek: %s\n", uploadPath)
-
-	// Dosyayı kaydet
-	out, err := os.Create(uploadPath)
+	data, err := io.ReadAll(file)
 	if err != nil {
-		fmt.Printf("Dosya oluşturma hatası: %s\n", err.Error())
+		fmt.Printf("Dosya okuma hatası: %s\n", err.Error())
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"message": "Dosya oluşturulurken bir hata oluştu: " + err.Error(),
+			"message": "Dosya içeriği okunamadı: " + err.Error(),
 		})
 		return
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, file)
+	// Görseli işle (yön düzeltme, meta veri temizleme) ve depoya kaydet; varyantlar arka planda üretilir.
+	// Aynı görsel daha önce yüklendiyse mevcut dosya kullanılır.
+	imageKey := imagesKeyPrefix + fileName
+	media, err := storeMedia(c.Request.Context(), userID.(uint), imageKey, "/uploads/images/"+fileName, fileContentType, bytes.NewReader(data), func() error {
+		return storeProcessedImage(c.Request.Context(), imageKey, data)
+	})
 	if err != nil {
-		fmt.Printf("Dosya kopyalama hatası: %s\n", err.Error())
+		fmt.Printf("Görsel işleme/kaydetme hatası: %s\n", err.Error())
+		if errors.Is(err, services.ErrUnsupportedImage) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Görsel çözümlenemedi, dosya bozuk olabilir",
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"message": "Dosya kaydedilirken bir hata oluştu: " + err.Error(),
+			"message": "Dosya kaydedilirken bir hata oluştu: " + err.Error(),
 		})
 		return
 	}
 
-	fmt.Printf("Dosya başarıyla kaydedildi: %s\n", uploadPath)
-
-	// Dosya izinlerini kontrol et
-	fileInfo, err := os.Stat(uploadPath)
-	if err != nil {
-		fmt.Printf("Kaydedilen dosya bilgileri alınamadı: %s\n", err.Error())
-	} else {
-		fmt.Printf("Kaydedilen dosya boyutu: %d bytes, izinleri: %v\n", fileInfo.Size(), fileInfo.Mode())
-	}
+	fmt.Printf("Dosya başarıyla kaydedildi: %s\n", media.StorageKey)
 
-	// Görsel URL'sini döndür
-	imageURL := "/uploads/images/" + fileName
+	// Görsel URL'sini döndür
+	imageURL := media.URL
 	fmt.Printf("Oluşturulan URL: %s\n", imageURL)
 
 	c.JSON(http.StatusOK, gin.H{
@@ -226,36 +178,6 @@ func UploadVideo(c *gin.Context) {
 
 	fmt.Printf("Video yükleme işlemi başlatıldı. Kullanıcı ID: %v\n", userID)
 
-	// Çalışma dizinini al
-	workDir, err := os.Getwd()
-	if err != nil {
-		fmt.Printf("Çalışma dizini alınamadı: %s\n", err.Error())
-		c.JSON(http.StatusInternalServerError, Response{
-			Success: false,
-			Message: "Çalışma dizini alınamadı: " + err.Error(),
-		})
-		return
-	}
-
-	// Upload dizininin tam yolunu oluştur
-	uploadDir := filepath.Join(workDir, "uploads", "videos")
-	fmt.Printf("Video upload dizini: %s\n", uploadDir)
-
-	// Klasörün var olup olmadığını kontrol et, yoksa oluştur
-	if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
-		fmt.Println("uploads/videos klasörü bulunamadı, oluşturuluyor...")
-		err = os.MkdirAll(uploadDir, 0755)
-		if err != nil {
-			fmt.Printf("Klasör oluşturma hatası: %s\n", err.Error())
-			c.JSON(http.StatusInternalServerError, Response{
-				Success: false,
-				Message: "Video yükleme klasörü oluşturulamadı: " + err.Error(),
-			})
-			return
-		}
-		fmt.Println("uploads/videos klasörü başarıyla oluşturuldu")
-	}
-
 	// Request formundan dosyayı al
 	file, header, err := c.Request.FormFile("video")
 	if err != nil {
@@ -313,27 +235,43 @@ func UploadVideo(c *gin.Context) {
 		return
 	}
 
-	// Benzersiz dosya adı oluştur
-	saveFilename := uuid.New().String() + fileExt
-	savePath := filepath.Join(uploadDir, saveFilename)
-	fmt.Printf("Video kaydedilecek konum: %s\n", savePath)
+	// Kullanıcının depolama kotasını ve günlük yükleme sınırını kontrol et
+	if err := checkUploadQuota(userID.(uint), header.Size); err != nil {
+		respondQuotaError(c, err)
+		return
+	}
 
-	// Dosyayı kaydet
-	out, err := os.Create(savePath)
-	if err != nil {
-		fmt.Printf("Dosya oluşturma hatası: %s\n", err.Error())
+	// Gerçek süreyi ve kodekleri doğrula (bozuk veya kullanıcının sınırından uzun videoları reddet)
+	maxDuration := userStorageQuota(userID.(uint)).MaxVideoDuration()
+	if _, err := probeUploadedVideo(c.Request.Context(), file, maxDuration); err != nil {
+		fmt.Printf("Video doğrulama hatası: %s\n", err.Error())
+		if errors.Is(err, services.ErrCorruptVideo) || errors.Is(err, services.ErrVideoTooLong) {
+			c.JSON(http.StatusBadRequest, Response{
+				Success: false,
+				Message: "Video kabul edilmedi: " + explainVideoRejection(err, maxDuration).Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
-			Message: "Video dosyası oluşturulamadı: " + err.Error(),
+			Message: "Video doğrulanamadı: " + err.Error(),
 		})
 		return
 	}
-	defer out.Close()
 
-	// Dosya içeriğini kopyala
-	_, err = io.Copy(out, file)
+	// Benzersiz dosya adı oluştur
+	saveFilename := uuid.New().String() + fileExt
+	videoKey := videosKeyPrefix + saveFilename
+	fmt.Printf("Video kaydedilecek anahtar: %s\n", videoKey)
+
+	// Dosyayı depoya kaydet (aynı video daha önce yüklendiyse mevcut dosya kullanılır)
+	contentType := header.Header.Get("Content-Type")
+	media, err := storeMedia(c.Request.Context(), userID.(uint), videoKey, "/uploads/videos/"+saveFilename, contentType, file, func() error {
+		_, err := blobStore.Put(c.Request.Context(), videoKey, file, header.Size, contentType)
+		return err
+	})
 	if err != nil {
-		fmt.Printf("Dosya kopyalama hatası: %s\n", err.Error())
+		fmt.Printf("Video kaydetme hatası: %s\n", err.Error())
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
 			Message: "Video kaydedilemedi: " + err.Error(),
@@ -341,8 +279,8 @@ func UploadVideo(c *gin.Context) {
 		return
 	}
 
-	// Video URL'sini oluştur
-	videoURL := "/uploads/videos/" + saveFilename
+	// Video URL'sini oluştur
+	videoURL := media.URL
 	fmt.Printf("Video başarıyla yüklendi. URL: %s\n", videoURL)
 
 	// Başarılı cevap döndür
@@ -351,225 +289,7 @@ func UploadVideo(c *gin.Context) {
 		Message: "Video başarıyla yüklendi",
 		Data: map[string]interface{}{
 			"videoUrl": videoURL,
-			"fileName": saveFilename,
+			"fileName": path.Base(media.StorageKey),
 		},
 	})
 }
-
-// ServeUploadedImage - Yüklenen görselleri servis et
-func ServeUploadedImage(c *gin.Context) {
-	imageName := c.Param("name")
-	fmt.Printf("İstenen görsel: %s\n", imageName)
-
-	// Güvenlik: Dosya adını temizle
-	cleanImageName := filepath.Base(imageName)
-	if cleanImageName != imageName {
-		fmt.Printf("Güvenlik uyarısı: Dosya adı temizlendi %s -> %s\n", imageName, cleanImageName)
-		imageName = cleanImageName
-	}
-
-	// Çalışma dizinini al
-	workDir, err := os.Getwd()
-	if err != nil {
-		fmt.Printf("Çalışma dizini alınamadı: %s\n", err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Çalışma dizini alınamadı",
-		})
-		return
-	}
-
-	// Görsel tam yolunu oluştur
-	imagePath := filepath.Join(workDir, "uploads", "images", imageName)
-	fmt.Printf("Görsel dosya yolu: %s\n", imagePath)
-
-	// Güvenlik: Yol enjeksiyonunu önle
-	uploadDir := filepath.Join(workDir, "uploads", "images")
-	if !strings.HasPrefix(filepath.Clean(imagePath), uploadDir) {
-		fmt.Printf("Güvenlik uyarısı: Geçersiz dosya yolu: %s\n", imagePath)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Geçersiz görsel yolu",
-		})
-		return
-	}
-
-	// Dosyanın varlığını kontrol et
-	fileInfo, err := os.Stat(imagePath)
-	if os.IsNotExist(err) {
-		fmt.Printf("Dosya bulunamadı: %s\n", imagePath)
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"message": "Görsel bulunamadı",
-		})
-		return
-	} else if err != nil {
-		fmt.Printf("Dosya bilgisi alınamadı: %s\n", err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Dosya bilgisi alınamadı",
-		})
-		return
-	}
-
-	// Dosya izinlerini kontrol et
-	if fileInfo.Mode().Perm()&0444 == 0 {
-		fmt.Printf("Dosya okuma izni yok: %s\n", imagePath)
-		c.JSON(http.StatusForbidden, gin.H{
-			"success": false,
-			"message": "Görsel dosyasına erişim izni yok",
-		})
-		return
-	}
-
-	// Dosyayı servis et
-	c.File(imagePath)
-}
-
-// ServeUploadedVideo - Yüklenen videoları servis et
-func ServeUploadedVideo(c *gin.Context) {
-	// Görsel adını al
-	filename := c.Param("name")
-
-	// Path injection koruması
-	if strings.Contains(filename, "..") {
-		c.JSON(http.StatusForbidden, gin.H{
-			"success": false,
-			"message": "Geçersiz dosya adı",
-		})
-		return
-	}
-
-	// Çalışma dizinini al
-	workDir, err := os.Getwd()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Sunucu hatası",
-		})
-		return
-	}
-
-	// Dosya yolunu oluştur
-	filePath := filepath.Join(workDir, "uploads", "videos", filename)
-
-	// Dosyanın varlığını kontrol et
-	_, err = os.Stat(filePath)
-	if os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"message": "Video bulunamadı",
-		})
-		return
-	}
-
-	// Mime tipini belirle
-	var contentType string
-	switch filepath.Ext(filename) {
-	case ".mp4":
-		contentType = "video/mp4"
-	case ".webm":
-		contentType = "video/webm"
-	case ".mov":
-		contentType = "video/quicktime"
-	case ".avi":
-		contentType = "video/x-msvideo"
-	default:
-		contentType = "video/mp4" // Varsayılan
-	}
-
-	// Dosyayı servis et
-	c.Header("Content-Description", "Video File")
-	c.Header("Content-Transfer-Encoding", "binary")
-	c.Header("Content-Disposition", "inline; filename="+filename)
-	c.Header("Content-Type", contentType)
-	c.File(filePath)
-}
-
-// ServeUploadedThumbnail - Yüklenen thumbnail dosyalarını servis eder
-func ServeUploadedThumbnail(c *gin.Context) {
-	filename := c.Param("name")
-	if filename == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Dosya adı belirtilmedi"})
-		return
-	}
-
-	thumbnailPath := filepath.Join("./uploads/thumbnails", filename)
-
-	// Dosya var mı kontrol et
-	if _, err := os.Stat(thumbnailPath); os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Thumbnail bulunamadı"})
-		return
-	}
-
-	c.File(thumbnailPath)
-}
-
-// ClearOldUploads - Eski yüklenen dosyaları temizler (zamanlanmış görevle çalıştırılabilir)
-func ClearOldUploads(c *gin.Context) {
-	// Admin yetkisi kontrolü
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"message": "Yetkilendirme başarısız",
-		})
-		return
-	}
-
-	// Bu kısma admin kontrolü eklenebilir
-	fmt.Printf("Temizleme isteği - kullanıcı ID: %v\n", userID)
-
-	// Çalışma dizinini al
-	workDir, err := os.Getwd()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Çalışma dizini alınamadı",
-		})
-		return
-	}
-
-	uploadDir := filepath.Join(workDir, "uploads", "images")
-
-	// Dizindeki tüm dosyaları listele
-	files, err := os.ReadDir(uploadDir)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Upload dizini okunamadı: " + err.Error(),
-		})
-		return
-	}
-
-	// 30 gün öncesinden eski olan dosyaları sil
-	cutoffTime := time.Now().AddDate(0, 0, -30)
-	deletedCount := 0
-
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-
-		filePath := filepath.Join(uploadDir, file.Name())
-		fileInfo, err := file.Info()
-		if err != nil {
-			fmt.Printf("Dosya bilgileri alınamadı: %s - %s\n", file.Name(), err.Error())
-			continue
-		}
-
-		if fileInfo.ModTime().Before(cutoffTime) {
-			if err := os.Remove(filePath); err != nil {
-				fmt.Printf("Dosya silinemedi: %s - %s\n", file.Name(), err.Error())
-			} else {
-				deletedCount++
-				fmt.Printf("Eski dosya silindi: %s\n", file.Name())
-			}
-		}
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": fmt.Sprintf("%d eski dosya temizlendi", deletedCount),
-	})
-}
@@ -0,0 +1,515 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/services"
+	"social-media-app/backend/storage"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// tus protokol sabitleri
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,expiration,termination"
+	tusChunkType  = "application/offset+octet-stream"
+)
+
+const (
+	maxResumableVideoSize = 100 << 20 // Reel videoları için en fazla boyut
+	uploadSessionTTL      = 24 * time.Hour
+	uploadPartsKeyPrefix  = "upload-parts/"
+)
+
+// errUploadRetryable depo veya disk kaynaklı geçici bir hata nedeniyle yüklemenin tamamlanamadığını belirtir.
+// Oturum uploading durumunda kalır; aynı konuma gönderilen boş PATCH birleştirmeyi yeniden dener.
+var errUploadRetryable = errors.New("yükleme geçici bir hata nedeniyle tamamlanamadı")
+
+// resumableUploadKinds uzantıya göre yükleme türü ve boyut sınırı
+var resumableUploadKinds = map[string]struct {
+	kind    string
+	maxSize int64
+}{
+	".jpg":  {"image", maxUploadSize},
+	".jpeg": {"image", maxUploadSize},
+	".png":  {"image", maxUploadSize},
+	".gif":  {"image", maxUploadSize},
+	".mp4":  {"video", maxResumableVideoSize},
+	".webm": {"video", maxResumableVideoSize},
+	".mov":  {"video", maxResumableVideoSize},
+}
+
+// SetTusHeaders tus keşif (OPTIONS) yanıtı için sunucu yeteneklerini başlıklara yazar
+func SetTusHeaders(c *gin.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	c.Header("Tus-Max-Size", strconv.Itoa(maxResumableVideoSize))
+}
+
+// checkTusVersion istemci farklı bir tus sürümü gönderdiyse 412 döndürür. Başlık olmadan
+// gelen istekler (tus kullanmayan istemciler) kabul edilir.
+func checkTusVersion(c *gin.Context) bool {
+	c.Header("Tus-Resumable", tusVersion)
+	if version := c.GetHeader("Tus-Resumable"); version != "" && version != tusVersion {
+		c.Header("Tus-Version", tusVersion)
+		c.AbortWithStatus(http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+// parseUploadMetadata "anahtar base64deger,anahtar2 base64deger2" biçimindeki Upload-Metadata başlığını çözer
+func parseUploadMetadata(header string) map[string]string {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), " ", 2)
+		if parts[0] == "" {
+			continue
+		}
+		value := ""
+		if len(parts) == 2 {
+			if decoded, err := base64.StdEncoding.DecodeString(parts[1]); err == nil {
+				value = string(decoded)
+			}
+		}
+		metadata[parts[0]] = value
+	}
+	return metadata
+}
+
+// findUploadSession oturumu getirir; başka kullanıcıya ait oturumlar bulunamadı sayılır
+func findUploadSession(c *gin.Context) (*models.UploadSession, bool) {
+	var session models.UploadSession
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("userID")).
+		First(&session).Error; err != nil {
+		c.AbortWithStatus(http.StatusNotFound)
+		return nil, false
+	}
+	if session.Status == models.UploadStatusUploading && time.Now().After(session.ExpiresAt) {
+		c.AbortWithStatus(http.StatusGone)
+		return nil, false
+	}
+	return &session, true
+}
+
+func setUploadStateHeaders(c *gin.Context, session *models.UploadSession) {
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(session.Length, 10))
+	c.Header("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "no-store")
+}
+
+// CreateUploadSession - Yeni bir devam ettirilebilir yükleme oturumu açar (tus creation)
+// Başlıklar: Upload-Length, Upload-Metadata (filename, filetype)
+func CreateUploadSession(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+	userID := c.GetUint("userID")
+
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || length <= 0 {
+		c.JSON(http.StatusBadRequest, Response{Success: false, Message: "Geçerli bir Upload-Length başlığı gerekli"})
+		return
+	}
+
+	metadata := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	filename := filepath.Base(metadata["filename"])
+	kind, ok := resumableUploadKinds[strings.ToLower(filepath.Ext(filename))]
+	if !ok {
+		c.JSON(http.StatusBadRequest, Response{
+			Success: false,
+			Message: "Desteklenmeyen dosya türü. Desteklenen formatlar: JPG, PNG, GIF, MP4, WebM, MOV",
+		})
+		return
+	}
+	if length > kind.maxSize {
+		c.JSON(http.StatusRequestEntityTooLarge, Response{
+			Success: false,
+			Message: fmt.Sprintf("Dosya çok büyük, en fazla %d MB yükleyebilirsiniz", kind.maxSize>>20),
+		})
+		return
+	}
+
+	// Dosyanın tamamı için kota baştan ayrılır; devam eden yüklemeler kullanımın parçası sayılır
+	if err := checkUploadQuota(userID, length); err != nil {
+		respondQuotaError(c, err)
+		return
+	}
+
+	session := models.UploadSession{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Kind:      kind.kind,
+		Filename:  filename,
+		FileType:  metadata["filetype"],
+		Length:    length,
+		Status:    models.UploadStatusUploading,
+		ExpiresAt: time.Now().Add(uploadSessionTTL),
+	}
+	if err := database.DB.Create(&session).Error; err != nil {
+		fmt.Printf("Yükleme oturumu oluşturulamadı (UserID: %d): %v\n", userID, err)
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Yükleme oturumu oluşturulamadı"})
+		return
+	}
+
+	c.Header("Location", "/api/uploads/"+session.ID)
+	setUploadStateHeaders(c, &session)
+	c.JSON(http.StatusCreated, Response{
+		Success: true,
+		Message: "Yükleme oturumu oluşturuldu",
+		Data:    session,
+	})
+}
+
+// HeadUploadSession - Yüklemenin mevcut konumunu (Upload-Offset) döndürür
+func HeadUploadSession(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+	session, ok := findUploadSession(c)
+	if !ok {
+		return
+	}
+	setUploadStateHeaders(c, session)
+	c.Status(http.StatusOK)
+}
+
+// GetUploadSession - Yükleme oturumunun durumunu JSON olarak döndürür
+func GetUploadSession(c *gin.Context) {
+	session, ok := findUploadSession(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, Response{
+		Success: true,
+		Message: "Yükleme oturumu getirildi",
+		Data:    session,
+	})
+}
+
+// PatchUploadSession - Upload-Offset konumundan itibaren bir parça ekler; dosya tamamlandığında
+// parçaları birleştirip doğrular ve kalıcı medya olarak kaydeder
+func PatchUploadSession(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+	if c.ContentType() != tusChunkType {
+		c.AbortWithStatus(http.StatusUnsupportedMediaType)
+		return
+	}
+	session, ok := findUploadSession(c)
+	if !ok {
+		return
+	}
+	if session.Status != models.UploadStatusUploading {
+		c.JSON(http.StatusConflict, Response{Success: false, Message: "Yükleme zaten tamamlandı"})
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset != session.Offset {
+		setUploadStateHeaders(c, session)
+		c.AbortWithStatus(http.StatusConflict)
+		return
+	}
+
+	// Tüm baytlar alınmış ama birleştirme geçici bir hatayla yarım kalmışsa parça beklenmez,
+	// birleştirme yeniden denenir
+	if session.Offset < session.Length && !appendUploadPart(c, session, offset) {
+		return
+	}
+
+	if session.Offset == session.Length {
+		if err := finalizeUploadSession(c.Request.Context(), session); err != nil {
+			fmt.Printf("Yükleme tamamlanamadı (Session: %s): %v\n", session.ID, err)
+			setUploadStateHeaders(c, session)
+			switch {
+			case errors.Is(err, errUploadRetryable):
+				c.Header("Retry-After", "5")
+				c.JSON(http.StatusServiceUnavailable, Response{Success: false, Message: "Dosya şu an işlenemedi, lütfen tekrar deneyin"})
+			case errors.Is(err, services.ErrCorruptVideo) || errors.Is(err, services.ErrVideoTooLong) ||
+				errors.Is(err, services.ErrUnsupportedImage):
+				c.JSON(http.StatusUnprocessableEntity, Response{Success: false, Message: "Dosya kabul edilmedi: " + err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Dosya kabul edilmedi: " + err.Error()})
+			}
+			return
+		}
+	}
+
+	setUploadStateHeaders(c, session)
+	c.Status(http.StatusNoContent)
+}
+
+// appendUploadPart istek gövdesini offset konumundaki yeni bir parça olarak kaydeder ve oturumun konumunu ilerletir.
+// Yanıt yazıldıysa (çakışma, boş parça veya hata) false döner.
+func appendUploadPart(c *gin.Context, session *models.UploadSession, offset int64) bool {
+	// Parçayı depoya ayrı bir nesne olarak yaz; kalan boyuttan fazlası kabul edilmez
+	remaining := session.Length - session.Offset
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, remaining)
+	partKey := fmt.Sprintf("%s%s/%020d-%s", uploadPartsKeyPrefix, session.ID, offset, uuid.New().String()[:8])
+	info, err := blobStore.Put(c.Request.Context(), partKey, body, -1, tusChunkType)
+	if err != nil {
+		blobStore.Delete(context.Background(), partKey)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, Response{Success: false, Message: "Parça, dosyanın kalan boyutunu aşıyor"})
+			return false
+		}
+		fmt.Printf("Yükleme parçası kaydedilemedi (Session: %s): %v\n", session.ID, err)
+		c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Parça kaydedilemedi"})
+		return false
+	}
+	if info.Size == 0 {
+		blobStore.Delete(context.Background(), partKey)
+		setUploadStateHeaders(c, session)
+		c.Status(http.StatusNoContent)
+		return false
+	}
+
+	// Aynı konuma eşzamanlı yazmalarda sadece biri kabul edilir
+	newOffset := offset + info.Size
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.UploadSession{}).
+			Where("id = ? AND upload_offset = ?", session.ID, offset).
+			Updates(map[string]interface{}{
+				"upload_offset": newOffset,
+				"expires_at":    time.Now().Add(uploadSessionTTL),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(&models.UploadPart{
+			SessionID:  session.ID,
+			Offset:     offset,
+			Size:       info.Size,
+			StorageKey: partKey,
+			CreatedAt:  time.Now(),
+		}).Error
+	})
+	if err != nil {
+		blobStore.Delete(context.Background(), partKey)
+		database.DB.First(session, "id = ?", session.ID)
+		setUploadStateHeaders(c, session)
+		c.AbortWithStatus(http.StatusConflict)
+		return false
+	}
+	session.Offset = newOffset
+	session.ExpiresAt = time.Now().Add(uploadSessionTTL)
+	return true
+}
+
+// DeleteUploadSession - Yüklemeyi iptal eder ve parçaları siler (tus termination)
+func DeleteUploadSession(c *gin.Context) {
+	if !checkTusVersion(c) {
+		return
+	}
+	session, ok := findUploadSession(c)
+	if !ok {
+		return
+	}
+	removeUploadSession(c.Request.Context(), session)
+	c.Status(http.StatusNoContent)
+}
+
+// finalizeUploadSession parçaları sırayla birleştirir, dosyayı türüne göre doğrular/işler ve
+// oturumu complete (veya failed) olarak işaretler. Geçici hatalarda oturum uploading durumunda kalır
+// ve errUploadRetryable döner; parçalar silinmediği için birleştirme yeniden denenebilir.
+func finalizeUploadSession(ctx context.Context, session *models.UploadSession) error {
+	fail := func(err error) error {
+		session.Status = models.UploadStatusFailed
+		session.Error = err.Error()
+		database.DB.Model(session).Updates(map[string]interface{}{"status": session.Status, "error": session.Error})
+		deleteUploadParts(ctx, session.ID)
+		return err
+	}
+	retry := func(err error) error {
+		return fmt.Errorf("%w: %v", errUploadRetryable, err)
+	}
+
+	tmp, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return retry(err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	var parts []models.UploadPart
+	database.DB.Where("session_id = ?", session.ID).Order("upload_offset").Find(&parts)
+	var written int64
+	for _, part := range parts {
+		if part.Offset != written {
+			return fail(fmt.Errorf("parça sırası bozuk (beklenen %d, gelen %d)", written, part.Offset))
+		}
+		reader, _, err := blobStore.Get(ctx, part.StorageKey)
+		if errors.Is(err, storage.ErrNotFound) {
+			return fail(fmt.Errorf("parça depoda bulunamadı (konum %d)", part.Offset))
+		}
+		if err != nil {
+			return retry(err)
+		}
+		n, err := io.Copy(tmp, reader)
+		reader.Close()
+		if err != nil {
+			return retry(err)
+		}
+		written += n
+	}
+	if written != session.Length {
+		return fail(fmt.Errorf("dosya boyutu uyuşmuyor (beklenen %d, birleşen %d)", session.Length, written))
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return retry(err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(session.Filename))
+	fileName := fmt.Sprintf("%s-%s%s", uuid.New().String(), time.Now().Format("20060102150405"), ext)
+
+	// Aynı içerik daha önce yüklendiyse mevcut dosya kullanılır
+	var media *models.Media
+	switch session.Kind {
+	case "image":
+		data, err := io.ReadAll(tmp)
+		if err != nil {
+			return retry(err)
+		}
+		key := imagesKeyPrefix + fileName
+		media, err = storeMedia(ctx, session.UserID, key, "/uploads/"+key, session.FileType, bytes.NewReader(data), func() error {
+			return storeProcessedImage(ctx, key, data)
+		})
+		if errors.Is(err, services.ErrUnsupportedImage) {
+			return fail(err)
+		}
+		if err != nil {
+			return retry(err)
+		}
+	case "video":
+		maxDuration := userStorageQuota(session.UserID).MaxVideoDuration()
+		if _, err := probeUploadedVideo(ctx, tmp, maxDuration); err != nil {
+			return fail(explainVideoRejection(err, maxDuration))
+		}
+		key := videosKeyPrefix + fileName
+		media, err = storeMedia(ctx, session.UserID, key, "/uploads/"+key, session.FileType, tmp, func() error {
+			_, err := blobStore.Put(ctx, key, tmp, session.Length, session.FileType)
+			return err
+		})
+		if err != nil {
+			return retry(err)
+		}
+	default:
+		return fail(fmt.Errorf("bilinmeyen yükleme türü: %s", session.Kind))
+	}
+
+	now := time.Now()
+	session.Status = models.UploadStatusComplete
+	session.URL = media.URL
+	session.CompletedAt = &now
+	if err := database.DB.Model(session).Updates(map[string]interface{}{
+		"status":       session.Status,
+		"url":          session.URL,
+		"completed_at": session.CompletedAt,
+	}).Error; err != nil {
+		session.Status = models.UploadStatusUploading
+		session.URL = ""
+		session.CompletedAt = nil
+		return retry(err)
+	}
+	deleteUploadParts(ctx, session.ID)
+	return nil
+}
+
+// deleteUploadParts oturuma ait parça nesnelerini ve kayıtlarını siler
+func deleteUploadParts(ctx context.Context, sessionID string) {
+	objects, err := blobStore.List(ctx, uploadPartsKeyPrefix+sessionID)
+	if err != nil {
+		fmt.Printf("Yükleme parçaları listelenemedi (Session: %s): %v\n", sessionID, err)
+	}
+	for _, object := range objects {
+		blobStore.Delete(ctx, object.Key)
+	}
+	database.DB.Where("session_id = ?", sessionID).Delete(&models.UploadPart{})
+}
+
+// removeUploadSession oturumu ve parçalarını tamamen siler
+func removeUploadSession(ctx context.Context, session *models.UploadSession) {
+	deleteUploadParts(ctx, session.ID)
+	database.DB.Delete(session)
+}
+
+// consumeUpload tamamlanmış bir yüklemeyi verilen türde bir içerikte kullanmak için çözümler ve
+// kullanıldı olarak işaretler. Her yükleme yalnızca bir kez kullanılabilir.
+func consumeUpload(userID uint, uploadID string, kind string) (*models.UploadSession, error) {
+	var session models.UploadSession
+	if err := database.DB.Where("id = ? AND user_id = ?", uploadID, userID).First(&session).Error; err != nil {
+		return nil, errors.New("yükleme bulunamadı")
+	}
+	if session.Kind != kind {
+		return nil, fmt.Errorf("yükleme türü %s olmalı", kind)
+	}
+	if session.Status != models.UploadStatusComplete {
+		return nil, errors.New("yükleme henüz tamamlanmadı")
+	}
+
+	result := database.DB.Model(&models.UploadSession{}).
+		Where("id = ? AND used_at IS NULL", session.ID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("yükleme zaten kullanılmış")
+	}
+	return &session, nil
+}
+
+// releaseUpload içerik oluşturulamadığında yüklemeyi tekrar kullanılabilir yapar
+func releaseUpload(session *models.UploadSession) {
+	if session == nil {
+		return
+	}
+	database.DB.Model(&models.UploadSession{}).Where("id = ?", session.ID).Update("used_at", nil)
+}
+
+// StartUploadSessionCleanup süresi dolan yarım veya hiç kullanılmamış yüklemeleri saatlik olarak temizler.
+// Kullanılmayan dosyalar medya çöp toplayıcısı tarafından silinir.
+func StartUploadSessionCleanup() {
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			cleanupExpiredUploadSessions()
+			<-ticker.C
+		}
+	}()
+}
+
+func cleanupExpiredUploadSessions() {
+	var expired []models.UploadSession
+	database.DB.Where("(status <> ? OR used_at IS NULL) AND expires_at < ?", models.UploadStatusComplete, time.Now()).Find(&expired)
+	for i := range expired {
+		removeUploadSession(context.Background(), &expired[i])
+	}
+	if len(expired) > 0 {
+		fmt.Printf("%d süresi dolmuş yükleme oturumu temizlendi\n", len(expired))
+	}
+}
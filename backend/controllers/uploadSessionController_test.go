@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// flakyBlobStore ilk failGets okumada hata döndüren, sonra gerçek depoya geçen sarmalayıcı
+type flakyBlobStore struct {
+	storage.BlobStore
+	failGets int
+}
+
+func (s *flakyBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, storage.ObjectInfo, error) {
+	if s.failGets > 0 {
+		s.failGets--
+		return nil, storage.ObjectInfo{}, errors.New("depo geçici olarak erişilemez")
+	}
+	return s.BlobStore.Get(ctx, key)
+}
+
+// patchUpload oturuma verilen konumdan itibaren bir parça gönderir
+func patchUpload(router *gin.Engine, sessionID string, offset int, chunk string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPatch, "/uploads/"+sessionID, strings.NewReader(chunk))
+	request.Header.Set("Content-Type", tusChunkType)
+	request.Header.Set("Upload-Offset", strconv.Itoa(offset))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestPatchUploadSessionRetriesFinalizeAfterStorageError(t *testing.T) {
+	setupTestDatabase(t)
+	store := &flakyBlobStore{BlobStore: setupTestBlobStore(t), failGets: 1}
+	SetBlobStore(store)
+	user := createTestUser(t, "yukleyici")
+
+	previous := videoProcessor
+	videoProcessor = nil // ffmpeg kurulu değil, video doğrulaması atlanır
+	t.Cleanup(func() { videoProcessor = previous })
+
+	session := models.UploadSession{
+		ID:        "oturum-1",
+		UserID:    user.ID,
+		Kind:      "video",
+		Filename:  "klip.mp4",
+		FileType:  "video/mp4",
+		Length:    int64(len(mp4Header)),
+		Status:    models.UploadStatusUploading,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	if err := database.DB.Create(&session).Error; err != nil {
+		t.Fatalf("Oturum oluşturulamadı: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PATCH("/uploads/:id", func(c *gin.Context) { c.Set("userID", user.ID) }, PatchUploadSession)
+
+	// Son parça alınır ama birleştirme depo hatasıyla yarım kalır
+	first := patchUpload(router, session.ID, 0, mp4Header)
+	if first.Code != http.StatusServiceUnavailable {
+		t.Fatalf("ilk istek durumu %d, beklenen %d: %s", first.Code, http.StatusServiceUnavailable, first.Body.String())
+	}
+	database.DB.First(&session, "id = ?", session.ID)
+	if session.Status != models.UploadStatusUploading || session.Offset != session.Length {
+		t.Fatalf("oturum %q konum %d/%d, tüm baytlar alınmış uploading beklenirdi", session.Status, session.Offset, session.Length)
+	}
+
+	// Aynı konuma boş PATCH birleştirmeyi yeniden dener
+	retry := patchUpload(router, session.ID, len(mp4Header), "")
+	if retry.Code != http.StatusNoContent {
+		t.Fatalf("tekrar deneme durumu %d, beklenen %d: %s", retry.Code, http.StatusNoContent, retry.Body.String())
+	}
+	database.DB.First(&session, "id = ?", session.ID)
+	if session.Status != models.UploadStatusComplete || session.URL == "" {
+		t.Errorf("oturum %q, URL %q; tamamlanmış olmalı", session.Status, session.URL)
+	}
+
+	var parts int64
+	database.DB.Model(&models.UploadPart{}).Where("session_id = ?", session.ID).Count(&parts)
+	if parts != 0 {
+		t.Errorf("%d parça kaydı kaldı, tamamlanınca silinmeli", parts)
+	}
+}
+
+func TestFinalizeUploadSessionFailsWhenPartIsMissing(t *testing.T) {
+	setupTestDatabase(t)
+	setupTestBlobStore(t)
+	user := createTestUser(t, "yukleyici")
+
+	session := models.UploadSession{
+		ID:        "oturum-2",
+		UserID:    user.ID,
+		Kind:      "video",
+		Filename:  "klip.mp4",
+		Length:    4,
+		Offset:    4,
+		Status:    models.UploadStatusUploading,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	database.DB.Create(&session)
+	database.DB.Create(&models.UploadPart{SessionID: session.ID, Size: 4, StorageKey: uploadPartsKeyPrefix + "oturum-2/kayip"})
+
+	err := finalizeUploadSession(context.Background(), &session)
+	if err == nil || errors.Is(err, errUploadRetryable) {
+		t.Fatalf("hata %v, kalıcı bir hata beklenirdi", err)
+	}
+	database.DB.First(&session, "id = ?", session.ID)
+	if session.Status != models.UploadStatusFailed {
+		t.Errorf("oturum %q, failed olmalı", session.Status)
+	}
+}
@@ -1005,6 +1005,22 @@ func UserAuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// AdminAuthMiddleware - Sadece admin kullanıcıların erişebileceği rotalar için (UserAuthMiddleware'den sonra kullanılır)
+func AdminAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var user models.User
+		if err := database.DB.Select("id", "is_admin").First(&user, c.GetUint("userID")).Error; err != nil || !user.IsAdmin {
+			c.JSON(http.StatusForbidden, Response{
+				Success: false,
+				Message: "Bu işlem için admin yetkisi gerekiyor",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 // GetUserProfile - Oturum açmış kullanıcının kendi profil bilgilerini getirir
 func GetUserProfile(c *gin.Context) {
 	userID, _ := c.Get("userID")
@@ -1028,9 +1044,10 @@ func GetUserProfile(c *gin.Context) {
 	followerCount := len(user.Followers)
 	followingCount := len(user.Following)
 
-	// Gönderi sayısını hesapla
+	// Gönderi sayısını hesapla (taslak, zamanlanmış ve arşivlenmiş gönderiler sayılmaz)
 	var postCount int64
-	database.DB.Model(&models.Post{}).Where("user_id = ?", user.ID).Count(&postCount)
+	database.DB.Model(&models.Post{}).Where("user_id = ? AND COALESCE(publish_status, ?) = ? AND archived_at IS NULL", user.ID,
+		models.PublishStatusPublished, models.PublishStatusPublished).Count(&postCount)
 
 	c.JSON(http.StatusOK, Response{
 		Success: true,
@@ -1056,6 +1073,7 @@ func GetUserProfile(c *gin.Context) {
 				"postCount":         postCount,      // Eklendi
 				"createdAt":         user.CreatedAt,
 				"lastLogin":         user.LastLogin,
+				"storage":           userStorageUsage(user.ID), // Depolama kullanımı ve kotalar
 			},
 		},
 	})
@@ -1074,6 +1092,9 @@ func GetUserByUsername(c *gin.Context) {
 		return
 	}
 
+	// Profil ziyaretini içgörüler için kaydet
+	recordProfileVisit(c.GetUint("userID"), user.ID)
+
 	// Takipçi ve takip edilenlerin sayısını al
 	var followerCount int64
 	database.DB.Model(&models.Follow{}).Where("following_id = ?", user.ID).Count(&followerCount)
@@ -1081,9 +1102,10 @@ func GetUserByUsername(c *gin.Context) {
 	var followingCount int64
 	database.DB.Model(&models.Follow{}).Where("follower_id = ?", user.ID).Count(&followingCount)
 
-	// Gönderi sayısını al
+	// Gönderi sayısını al (taslak, zamanlanmış ve arşivlenmiş gönderiler sayılmaz)
 	var postCount int64
-	database.DB.Model(&models.Post{}).Where("user_id = ?", user.ID).Count(&postCount)
+	database.DB.Model(&models.Post{}).Where("user_id = ? AND COALESCE(publish_status, ?) = ? AND archived_at IS NULL", user.ID,
+		models.PublishStatusPublished, models.PublishStatusPublished).Count(&postCount)
 
 	// Takip Durumu ve Gizlilik Kontrolü
 	followStatus := "none" // none, following, pending, self
@@ -1202,12 +1224,17 @@ func GetUserPosts(c *gin.Context) {
 	var responsePosts []map[string]interface{}
 
 	if canViewPosts {
+		// Taslaklar ve arşivlenmiş gönderiler profilde yer almaz, yakın arkadaşlara özel gönderiler sadece
+		// listedekilere gösterilir; sabitlenen gönderiler en son sabitlenen önde olacak şekilde en üstte yer alır
+		viewerID, _ := currentUserID.(uint)
+		visibilitySQL, visibilityArgs := contentVisibilityClause("posts", viewerID)
 		if err := database.DB.Where("user_id = ?", user.ID).
+			Where(visibilitySQL, visibilityArgs...).
 			Preload("Images").
 			Preload("User", func(db *gorm.DB) *gorm.DB { // Kullanıcı bilgisini de alalım
 				return db.Select("id, username, profile_image")
 			}).
-			Order("created_at DESC").
+			Order("pinned_at IS NULL, pinned_at DESC, created_at DESC").
 			Find(&posts).Error; err != nil {
 			c.JSON(http.StatusInternalServerError, Response{
 				Success: false,
@@ -1216,7 +1243,8 @@ func GetUserPosts(c *gin.Context) {
 			return
 		}
 
-		// Yanıt için post dizisi oluştur
+		// Yanıt için post dizisi oluştur; mention'lar tek sorguda çözümlenir
+		mentionIDs := mentionUserIDs(postTexts(posts)...)
 		for _, post := range posts {
 			// Kullanıcının gönderiyi beğenip beğenmediğini kontrol et (eğer oturum açıksa)
 			isLiked := false
@@ -1253,14 +1281,21 @@ func GetUserPosts(c *gin.Context) {
 
 			// Yanıt oluştur
 			postResponse := map[string]interface{}{
-				"id":        post.ID,
-				"content":   post.Content,
-				"likes":     post.LikeCount,
-				"comments":  post.CommentCount,
-				"createdAt": UserFormatTimeAgo(post.CreatedAt),
-				"liked":     isLiked,
-				"saved":     isSaved,
-				"images":    imageURLs,
+				"id":              post.ID,
+				"content":         post.Content,
+				"contentEntities": textEntities(post.Content, mentionIDs),
+				"likes":           post.LikeCount,
+				"comments":        post.CommentCount,
+				"shares":          post.ShareCount,
+				"repostOf":        repostReference(viewerID, post),
+				"poll":            postPoll(viewerID, post),
+				"createdAt":       UserFormatTimeAgo(post.CreatedAt),
+				"liked":           isLiked,
+				"saved":           isSaved,
+				"images":          imageURLs,
+				"audience":        post.Audience,
+				"pinned":          post.PinnedAt != nil,
+				"edited":          post.EditedAt != nil,
 				"user": map[string]interface{}{ // Postun sahibinin bilgileri
 					"id":           post.User.ID,
 					"username":     post.User.Username,
@@ -1409,6 +1444,10 @@ func UpdateProfile(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, Response{Success: false, Message: "Profil güncellenirken bir hata oluştu: " + err.Error()})
 			return
 		}
+		// Profil fotoğrafı değiştiyse eski fotoğrafın referansı kaldırılır
+		if profileImage, ok := updates["ProfileImage"].(string); ok {
+			setMediaReferences(database.DB, models.MediaRefAvatar, user.ID, profileImage)
+		}
 	} else {
 		c.JSON(http.StatusOK, Response{Success: true, Message: "Profilde güncellenecek bir bilgi bulunamadı"})
 		return
@@ -1540,15 +1579,9 @@ func DeleteAccount(c *gin.Context) {
 func SearchUsers(c *gin.Context) {
 	// Gelen query parametresini al
 	query := c.Query("query")
-	currentUserID := c.Query("currentUserId")
 
-	// Mevcut kullanıcı ID'sini integer'a çevir
-	var currentUserIDInt uint
-	if currentUserID != "" {
-		if id, err := strconv.ParseUint(currentUserID, 10, 32); err == nil {
-			currentUserIDInt = uint(id)
-		}
-	}
+	// Mevcut kullanıcı ID'si token'dan alınır
+	currentUserIDInt := c.GetUint("userID")
 
 	fmt.Printf("Gelen istek: GET %s\n", c.Request.URL.Path)
 	fmt.Printf("Filtrelenecek kullanıcı ID: %d\n", currentUserIDInt)
@@ -1568,6 +1601,7 @@ func SearchUsers(c *gin.Context) {
 
 	if err := database.DB.Select("id, username, full_name, profile_image, bio").
 		Where("(username LIKE ? OR full_name LIKE ?) AND deleted_at IS NULL", searchPattern, searchPattern).
+		Where("id NOT IN ("+blockedUserIDsSQL+")", currentUserIDInt, currentUserIDInt).
 		Limit(20).Find(&users).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, Response{
 			Success: false,
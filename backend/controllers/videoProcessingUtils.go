@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"social-media-app/backend/database"
+	"social-media-app/backend/models"
+	"social-media-app/backend/services"
+	"strings"
+	"time"
+)
+
+// Tek bir videonun işlenmesi için en fazla süre
+const videoJobTimeout = 15 * time.Minute
+
+var (
+	videoProcessor *services.VideoProcessor
+	videoJobs      = make(chan uint, 100)
+)
+
+// StartVideoProcessing video işleme kuyruğunu ve işçilerini başlatır.
+// Sunucu yeniden başladığında "processing" durumunda kalmış reeller tekrar kuyruğa alınır.
+func StartVideoProcessing(processor *services.VideoProcessor, workers int) {
+	videoProcessor = processor
+	if !processor.Available() {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for reelID := range videoJobs {
+				processReelVideo(reelID)
+			}
+		}()
+	}
+
+	var pending []uint
+	database.DB.Model(&models.Reels{}).Where("status = ?", models.ReelStatusProcessing).Pluck("id", &pending)
+	for _, reelID := range pending {
+		enqueueReelProcessing(reelID)
+	}
+}
+
+// enqueueReelProcessing reeli işleme kuyruğuna ekler. ffmpeg yoksa reel doğrulanmadan yayına alınmaz;
+// processing durumunda kalır ve ffmpeg kullanılabilir olduğunda StartVideoProcessing tarafından işlenir.
+func enqueueReelProcessing(reelID uint) {
+	if !videoProcessor.Available() {
+		return
+	}
+	go func() { videoJobs <- reelID }()
+}
+
+// probeUploadedVideo yüklenen videoyu geçici dosyaya yazıp kodeklerini ve süresinin maxDuration'ı
+// aşmadığını doğrular. ffmpeg yoksa doğrulama atlanır.
+func probeUploadedVideo(ctx context.Context, file io.ReadSeeker, maxDuration time.Duration) (services.VideoProbe, error) {
+	if !videoProcessor.Available() {
+		return services.VideoProbe{}, nil
+	}
+
+	tmp, err := os.CreateTemp("", "probe-*")
+	if err != nil {
+		return services.VideoProbe{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, file)
+	tmp.Close()
+	if err != nil {
+		return services.VideoProbe{}, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return services.VideoProbe{}, err
+	}
+
+	probe, err := videoProcessor.Probe(ctx, tmp.Name())
+	if err != nil {
+		return probe, err
+	}
+	return probe, videoProcessor.Validate(probe, maxDuration)
+}
+
+// explainVideoRejection süre sınırı aşıldığında hataya izin verilen süreyi ekler
+func explainVideoRejection(err error, maxDuration time.Duration) error {
+	if errors.Is(err, services.ErrVideoTooLong) && maxDuration > 0 {
+		return fmt.Errorf("%w (en fazla %d saniye)", err, int(maxDuration.Seconds()))
+	}
+	return err
+}
+
+// processReelVideo reel videosunu doğrular, HLS olarak paketler, gerekirse kapak çıkarır
+// ve reelin durumunu ready veya failed olarak günceller
+func processReelVideo(reelID uint) {
+	ctx, cancel := context.WithTimeout(context.Background(), videoJobTimeout)
+	defer cancel()
+
+	var reel models.Reels
+	if err := database.DB.First(&reel, reelID).Error; err != nil || reel.Status != models.ReelStatusProcessing {
+		return
+	}
+
+	updates, err := transcodeReel(ctx, &reel)
+	if err != nil {
+		fmt.Printf("Reel videosu işlenemedi (ReelID: %d): %v\n", reelID, err)
+		message := "Video işlenirken bir hata oluştu"
+		if errors.Is(err, services.ErrCorruptVideo) || errors.Is(err, services.ErrVideoTooLong) {
+			message = err.Error()
+		}
+		database.DB.Model(&reel).Updates(map[string]interface{}{
+			"status":        models.ReelStatusFailed,
+			"process_error": message,
+		})
+		return
+	}
+
+	updates["status"] = models.ReelStatusReady
+	updates["process_error"] = ""
+	if err := database.DB.Model(&reel).Updates(updates).Error; err != nil {
+		fmt.Printf("Reel işleme sonucu kaydedilemedi (ReelID: %d): %v\n", reelID, err)
+	}
+}
+
+// transcodeReel işleme adımlarını çalıştırır ve reel için güncellenecek alanları döndürür
+func transcodeReel(ctx context.Context, reel *models.Reels) (map[string]interface{}, error) {
+	sourceKey := storageKeyFromURL(reel.VideoURL)
+	if sourceKey == "" {
+		return nil, fmt.Errorf("video deposu anahtarı çözümlenemedi: %s", reel.VideoURL)
+	}
+
+	workDir, err := os.MkdirTemp("", fmt.Sprintf("reel-%d-", reel.ID))
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+
+	// Kaynağı depodan geçici dizine indir
+	input := filepath.Join(workDir, "source"+path.Ext(sourceKey))
+	if err := downloadStoredObject(ctx, sourceKey, input); err != nil {
+		return nil, err
+	}
+
+	probe, err := videoProcessor.Probe(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := videoProcessor.Validate(probe, userStorageQuota(reel.UserID).MaxVideoDuration()); err != nil {
+		return nil, err
+	}
+
+	// HLS paketle ve tüm dosyaları depoya yükle
+	hlsDir := filepath.Join(workDir, "hls")
+	if err := os.MkdirAll(hlsDir, 0755); err != nil {
+		return nil, err
+	}
+	if err := videoProcessor.PackageHLS(ctx, input, hlsDir, probe); err != nil {
+		return nil, err
+	}
+	hlsPrefix := fmt.Sprintf("%s%d/", hlsKeyPrefix, reel.ID)
+	entries, err := os.ReadDir(hlsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		contentType := "video/mp2t"
+		if strings.HasSuffix(entry.Name(), ".m3u8") {
+			contentType = "application/vnd.apple.mpegurl"
+		}
+		if err := uploadLocalFile(ctx, filepath.Join(hlsDir, entry.Name()), hlsPrefix+entry.Name(), contentType); err != nil {
+			return nil, err
+		}
+	}
+
+	updates := map[string]interface{}{
+		"duration":    int(math.Ceil(probe.Duration)),
+		"video_codec": probe.VideoCodec,
+		"hls_url":     "/uploads/" + hlsPrefix + services.HLSMasterPlaylist,
+	}
+
+	// Kapak yüklenmediyse videonun ilk saniyesinden (kısa videolarda ortasından) kare çıkar
+	if reel.ThumbnailURL == "" {
+		posterName := fmt.Sprintf("reel_%d_poster.jpg", reel.ID)
+		posterPath := filepath.Join(workDir, posterName)
+		if err := videoProcessor.ExtractPoster(ctx, input, posterPath, math.Min(1, probe.Duration/2)); err != nil {
+			fmt.Printf("Reel kapağı çıkarılamadı (ReelID: %d): %v\n", reel.ID, err)
+		} else if err := uploadLocalFile(ctx, posterPath, thumbnailsKeyPrefix+posterName, "image/jpeg"); err != nil {
+			fmt.Printf("Reel kapağı kaydedilemedi (ReelID: %d): %v\n", reel.ID, err)
+		} else {
+			posterURL := "/api/thumbnails/" + posterName
+			if info, err := blobStore.Stat(ctx, thumbnailsKeyPrefix+posterName); err == nil {
+				sum, _ := hashStoredObject(ctx, info.Key)
+				registerMedia(reel.UserID, info.Key, posterURL, sum, info.Size, "image/jpeg", time.Now())
+				addMediaReferences(database.DB, models.MediaRefReel, reel.ID, posterURL)
+			}
+			updates["thumbnail_url"] = posterURL
+		}
+	}
+
+	return updates, nil
+}
+
+// downloadStoredObject depodaki nesneyi yerel bir dosyaya kopyalar
+func downloadStoredObject(ctx context.Context, key, dest string) error {
+	reader, _, err := blobStore.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, reader); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// uploadLocalFile yerel bir dosyayı depoya yükler
+func uploadLocalFile(ctx context.Context, src, key, contentType string) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	_, err = blobStore.Put(ctx, key, file, info.Size(), contentType)
+	return err
+}
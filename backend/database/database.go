@@ -60,6 +60,12 @@ func ConnectDatabase() {
 	db.Exec("PRAGMA cache_size = 1000;")
 	db.Exec("PRAGMA foreign_keys = ON;")
 
+	// Benzersiz indeksler ve yeni sayaç sütunları eklenmeden önce eski kayıtları hazırla
+	dropReelViewWindowBuckets(db)
+	prepareLegacyReelViewCounts(db)
+	prepareMediaReferenceCounts(db)
+	mergeDuplicateDefaultCollections(db)
+
 	// Tabloları otomatik oluştur
 	err = db.AutoMigrate(
 		&models.User{},
@@ -71,12 +77,28 @@ func ConnectDatabase() {
 		&models.Comment{},
 		&models.CommentLike{},
 		&models.PostImage{},
+		&models.PostRevision{},
+		&models.PostRevisionImage{},
 		&models.Comment{},
 		&models.Like{},
 		&models.SavedPost{},
 		&models.Reels{},
 		&models.ReelLike{},
+		&models.ReelShare{},
+		&models.PostShare{},
+		&models.Poll{},
+		&models.PollOption{},
+		&models.PollVote{},
+		&models.PollVoteOption{},
+		&models.ReelView{},
+		&models.ContentImpression{},
+		&models.ProfileVisit{},
+		&models.DailyContentInsight{},
+		&models.DailyAccountInsight{},
+		&models.DailyAudienceInsight{},
 		&models.SavedReel{},
+		&models.SavedCollection{},
+		&models.SavedCollectionItem{},
 		&models.LoginActivity{},
 		&models.PasswordReset{},
 		&models.EmailVerification{},
@@ -97,6 +119,19 @@ func ConnectDatabase() {
 		&models.Tag{},
 		&models.PostTag{},
 		&models.UserTag{},
+		&models.Mention{},
+		&models.Block{},
+		&models.SearchHistoryEntry{},
+		&models.DismissedSuggestion{},
+		&models.ImageVariant{},
+		&models.UploadSession{},
+		&models.UploadPart{},
+		&models.Media{},
+		&models.MediaReference{},
+		&models.MediaOwner{},
+		&models.UserQuota{},
+		&models.Story{},
+		&models.StoryView{},
 	)
 
 	if err != nil {
@@ -104,6 +139,12 @@ func ConnectDatabase() {
 	}
 
 	log.Println("Veritabanı migration başarılı!")
+
+	// Paylaşılan medya dosyalarından önce yüklenmiş dosyaların sahiplik kayıtlarını oluştur
+	backfillMediaOwners(db)
+
+	// Arama dizinini hazırla
+	setupSearchIndex(db)
 }
 
 // .env'den değer al, boşsa default döndür
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"log"
+
+	"social-media-app/backend/models"
+
+	"gorm.io/gorm"
+)
+
+// prepareMediaReferenceCounts media.reference_count sütununu ekler ve mevcut referans kayıtlarından
+// doldurur. Böylece çöp toplayıcı eski dosyaları referanssız sanmaz.
+func prepareMediaReferenceCounts(db *gorm.DB) {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.Media{}) || migrator.HasColumn(&models.Media{}, "ReferenceCount") {
+		return
+	}
+	if err := migrator.AddColumn(&models.Media{}, "ReferenceCount"); err != nil {
+		log.Printf("media.reference_count eklenemedi: %v", err)
+		return
+	}
+	if !migrator.HasTable(&models.MediaReference{}) {
+		return
+	}
+	if err := db.Exec(`UPDATE media SET reference_count =
+		(SELECT COUNT(*) FROM media_references WHERE media_references.media_id = media.id)`).Error; err != nil {
+		log.Printf("Medya referans sayıları doldurulamadı: %v", err)
+	}
+}
+
+// backfillMediaOwners sahiplik tablosundan önce yüklenmiş dosyalar için ilk yükleyenin sahiplik kaydını oluşturur
+func backfillMediaOwners(db *gorm.DB) {
+	if err := db.Exec(`INSERT INTO media_owners (media_id, user_id, created_at)
+		SELECT media.id, media.owner_id, media.created_at FROM media
+		WHERE media.owner_id <> 0 AND NOT EXISTS (
+			SELECT 1 FROM media_owners WHERE media_owners.media_id = media.id AND media_owners.user_id = media.owner_id)`).Error; err != nil {
+		log.Printf("Medya sahiplik kayıtları oluşturulamadı: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"log"
+
+	"social-media-app/backend/models"
+
+	"gorm.io/gorm"
+)
+
+// dropReelViewWindowBuckets sabit pencere numaralarıyla tutulan izleme oturumlarından kalan
+// (reel, izleyici, pencere) benzersiz indeksini ve window_bucket sütununu kaldırır. Oturumlar artık
+// izleyicinin son olay zamanına göre eşleştirildiğinden aynı izleyicinin birden çok oturumu olabilir.
+func dropReelViewWindowBuckets(db *gorm.DB) {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.ReelView{}) {
+		return
+	}
+	if migrator.HasIndex(&models.ReelView{}, "idx_reel_view_session") {
+		if err := migrator.DropIndex(&models.ReelView{}, "idx_reel_view_session"); err != nil {
+			log.Printf("reel_views.idx_reel_view_session kaldırılamadı: %v", err)
+			return
+		}
+	}
+	if migrator.HasColumn(&models.ReelView{}, "window_bucket") {
+		if err := db.Exec("ALTER TABLE reel_views DROP COLUMN window_bucket").Error; err != nil {
+			log.Printf("reel_views.window_bucket kaldırılamadı: %v", err)
+		}
+	}
+}
+
+// prepareLegacyReelViewCounts izleme oturumları tutulmaya başlamadan önce sayılmış görüntülenmeleri
+// reels.legacy_view_count sütununa taşır. Sayaç uzlaştırması bu değeri oturum sayısına ekler; böylece
+// eski reellerin görüntülenme sayısı oturum sayısına (çoğunlukla 0) düşürülmez.
+func prepareLegacyReelViewCounts(db *gorm.DB) {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.Reels{}) || migrator.HasColumn(&models.Reels{}, "LegacyViewCount") {
+		return
+	}
+	if err := migrator.AddColumn(&models.Reels{}, "LegacyViewCount"); err != nil {
+		log.Printf("reels.legacy_view_count eklenemedi: %v", err)
+		return
+	}
+
+	sessions := "0"
+	if migrator.HasTable(&models.ReelView{}) {
+		sessions = "(SELECT COUNT(*) FROM reel_views WHERE reel_views.reel_id = reels.id)"
+	}
+	if err := db.Exec("UPDATE reels SET legacy_view_count = MAX(COALESCE(view_count, 0) - " + sessions + ", 0)").Error; err != nil {
+		log.Printf("Eski reel görüntülenmeleri taşınamadı: %v", err)
+	}
+}
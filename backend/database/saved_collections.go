@@ -0,0 +1,48 @@
+package database
+
+import (
+	"log"
+
+	"social-media-app/backend/models"
+
+	"gorm.io/gorm"
+)
+
+// mergeDuplicateDefaultCollections eşzamanlı isteklerle aynı kullanıcıya açılmış fazladan varsayılan
+// koleksiyonları en eskisiyle birleştirir. Böylece kullanıcı başına tek varsayılan koleksiyon
+// sağlayan benzersiz indeks mevcut kayıtlarla çakışmadan oluşturulur.
+func mergeDuplicateDefaultCollections(db *gorm.DB) {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.SavedCollection{}) || migrator.HasIndex(&models.SavedCollection{}, "idx_saved_collection_default") {
+		return
+	}
+
+	var duplicates []struct {
+		ID     uint
+		KeepID uint
+	}
+	if err := db.Raw(`SELECT saved_collections.id AS id, keep.id AS keep_id FROM saved_collections
+		JOIN (SELECT user_id, MIN(id) AS id FROM saved_collections WHERE is_default = 1 GROUP BY user_id) AS keep
+			ON keep.user_id = saved_collections.user_id
+		WHERE saved_collections.is_default = 1 AND saved_collections.id <> keep.id`).Scan(&duplicates).Error; err != nil {
+		log.Printf("Tekrarlanan varsayılan koleksiyonlar bulunamadı: %v", err)
+		return
+	}
+
+	for _, duplicate := range duplicates {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// Varsayılan koleksiyonda zaten olan içerikler taşınmaz, kopyalarıyla birlikte silinir
+			if err := tx.Exec("UPDATE OR IGNORE saved_collection_items SET collection_id = ? WHERE collection_id = ?",
+				duplicate.KeepID, duplicate.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("collection_id = ?", duplicate.ID).Delete(&models.SavedCollectionItem{}).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&models.SavedCollection{}, duplicate.ID).Error
+		})
+		if err != nil {
+			log.Printf("Varsayılan koleksiyon %d birleştirilemedi: %v", duplicate.ID, err)
+		}
+	}
+}
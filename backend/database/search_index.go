@@ -0,0 +1,87 @@
+package database
+
+import (
+	"log"
+
+	"social-media-app/backend/models"
+
+	"gorm.io/gorm"
+)
+
+// setupSearchIndex arama dizini tablosunu oluşturur ve mevcut verilerle yeniden doldurur.
+// FTS5 desteği için sqlite sürücüsü "-tags sqlite_fts5" ile derlenmelidir (bkz. Makefile); destek yoksa
+// kısıtlı moda geçilir: LIKE ile aranan, sıralama ve gerçek yazım hatası toleransı olmayan düz bir tablo.
+func setupSearchIndex(db *gorm.DB) {
+	err := db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS ` + models.SearchIndexFTSTable + ` USING fts5(
+		entity_type UNINDEXED,
+		entity_id UNINDEXED,
+		owner_id UNINDEXED,
+		title,
+		body,
+		tokenize = 'unicode61 remove_diacritics 2'
+	)`).Error
+
+	if err == nil {
+		models.SearchIndexTable = models.SearchIndexFTSTable
+		models.SearchIndexFullText = true
+	} else {
+		log.Printf("Uyarı: FTS5 kullanılamıyor (%v), arama kısıtlı modda (LIKE, sıralamasız) çalışacak. "+
+			"FTS5 için uygulamayı -tags sqlite_fts5 ile derleyin (make build).", err)
+
+		if err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + models.SearchIndexPlainTable + ` (
+			rowid INTEGER PRIMARY KEY,
+			entity_type TEXT NOT NULL,
+			entity_id INTEGER NOT NULL,
+			owner_id INTEGER NOT NULL,
+			title TEXT,
+			body TEXT
+		)`).Error; err != nil {
+			log.Printf("Arama dizini oluşturulamadı, arama devre dışı: %v", err)
+			return
+		}
+		models.SearchIndexTable = models.SearchIndexPlainTable
+		models.SearchIndexFullText = false
+	}
+
+	if err := RebuildSearchIndex(db); err != nil {
+		log.Printf("Arama dizini yeniden oluşturulamadı: %v", err)
+		return
+	}
+	log.Printf("Arama dizini hazır (%s)", models.SearchIndexTable)
+}
+
+// RebuildSearchIndex arama dizinini tamamen boşaltıp kaynak tablolardan yeniden doldurur.
+// Hook'ları tetiklemeyen toplu güncellemelerden sonra dizini tutarlı hale getirir.
+func RebuildSearchIndex(db *gorm.DB) error {
+	table := models.SearchIndexTable
+	if table == "" {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`DELETE FROM ` + table,
+			`INSERT INTO ` + table + ` (rowid, entity_type, entity_id, owner_id, title, body)
+				SELECT id * 4 + 0, 'user', id, id, username, COALESCE(full_name, '') || ' ' || COALESCE(bio, '')
+				FROM users WHERE deleted_at IS NULL`,
+			`INSERT INTO ` + table + ` (rowid, entity_type, entity_id, owner_id, title, body)
+				SELECT id * 4 + 1, 'post', id, user_id, COALESCE(caption, ''),
+					COALESCE(content, '') || ' ' || REPLACE(COALESCE(tags_string, ''), ',', ' ')
+				FROM posts WHERE deleted_at IS NULL AND COALESCE(audience, 'everyone') <> 'close_friends'
+					AND COALESCE(publish_status, 'published') = 'published' AND archived_at IS NULL`,
+			`INSERT INTO ` + table + ` (rowid, entity_type, entity_id, owner_id, title, body)
+				SELECT id * 4 + 2, 'reel', id, user_id, COALESCE(caption, ''), COALESCE(music, '')
+				FROM reels WHERE deleted_at IS NULL AND COALESCE(status, 'ready') = 'ready'
+					AND COALESCE(audience, 'everyone') <> 'close_friends' AND COALESCE(publish_status, 'published') = 'published'`,
+			`INSERT INTO ` + table + ` (rowid, entity_type, entity_id, owner_id, title, body)
+				SELECT id * 4 + 3, 'tag', id, 0, name, ''
+				FROM tags WHERE deleted_at IS NULL`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
+	"social-media-app/backend/controllers"
 	"social-media-app/backend/database"
 	"social-media-app/backend/routes"
+	"social-media-app/backend/storage"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -19,6 +24,21 @@ func main() {
 	// Veritabanı bağlantısı kur
 	database.ConnectDatabase()
 
+	// Yönetim komutları: go run . media-gc [-dry-run], go run . reconcile-counters [-dry-run],
+	// go run . aggregate-insights [-days N]
+	if len(os.Args) > 1 && os.Args[1] == "media-gc" {
+		runMediaGC(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "reconcile-counters" {
+		runCounterReconciliation(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "aggregate-insights" {
+		runInsightsAggregation(os.Args[2:])
+		return
+	}
+
 	// API rotalarını ayarla
 	router := routes.SetupRoutes()
 
@@ -30,3 +50,65 @@ func main() {
 	log.Println("Server running on port", port)
 	router.Run(":" + port)
 }
+
+// runMediaGC kullanılmayan medya dosyalarını sunucuyu başlatmadan temizler
+func runMediaGC(args []string) {
+	flags := flag.NewFlagSet("media-gc", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "Silinecek dosyaları listele, hiçbir şey silme")
+	flags.Parse(args)
+
+	blobStore, err := storage.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Medya deposu başlatılamadı: %v", err)
+	}
+	controllers.SetBlobStore(blobStore)
+
+	report, err := controllers.RunMediaGarbageCollection(context.Background(), *dryRun)
+	if err != nil {
+		log.Fatalf("Medya çöp toplama başarısız: %v", err)
+	}
+	action := "Silindi:"
+	if *dryRun {
+		action = "Silinecek:"
+	}
+	for _, key := range report.DeletedKeys {
+		log.Println(action, key)
+	}
+	log.Printf("Kayıt altına alınan: %d, eklenen referans: %d, kaldırılan referans: %d, silinen: %d dosya (%d bayt), HLS temizlenen reel: %d, kuru çalıştırma: %v",
+		report.Registered, report.ReferencesAdded, report.ReferencesRemoved, report.Deleted, report.DeletedBytes, report.DeletedHLSReels, report.DryRun)
+}
+
+// runCounterReconciliation etkileşim sayaçlarını sunucuyu başlatmadan kaynak tablolarla uzlaştırır
+func runCounterReconciliation(args []string) {
+	flags := flag.NewFlagSet("reconcile-counters", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "Sapmaları listele, hiçbir sayacı güncelleme")
+	flags.Parse(args)
+
+	report, err := controllers.RunCounterReconciliation(*dryRun)
+	if err != nil {
+		log.Fatalf("Sayaç uzlaştırma başarısız: %v", err)
+	}
+	for _, counter := range report.Counters {
+		log.Printf("%s: %d kayıtta sapma", counter.Counter, counter.Drifted)
+		for _, drift := range counter.Samples {
+			log.Printf("  id=%d kayıtlı=%d gerçek=%d", drift.ID, drift.Stored, drift.Actual)
+		}
+	}
+	log.Printf("Toplam sapma: %d kayıt, kuru çalıştırma: %v", report.TotalDrifted, report.DryRun)
+}
+
+// runInsightsAggregation son N günün içgörü özetlerini sunucuyu başlatmadan yeniden hesaplar
+func runInsightsAggregation(args []string) {
+	flags := flag.NewFlagSet("aggregate-insights", flag.ExitOnError)
+	days := flags.Int("days", 2, "Bugün dahil yeniden özetlenecek gün sayısı")
+	flags.Parse(args)
+
+	now := time.Now()
+	for i := *days - 1; i >= 0; i-- {
+		report, err := controllers.RunInsightsAggregation(now.AddDate(0, 0, -i))
+		if err != nil {
+			log.Fatalf("İçgörü özetleme başarısız: %v", err)
+		}
+		log.Printf("%s: %d içerik, %d hesap, %d kitle satırı", report.Day, report.ContentRows, report.AccountRows, report.AudienceRows)
+	}
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Block - Kullanıcı engelleme ilişkisi
+type Block struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	BlockerID uint      `gorm:"index:idx_blocker_blocked,unique:true;not null" json:"blockerId"`       // Engelleyen kullanıcı
+	BlockedID uint      `gorm:"index:idx_blocker_blocked,unique:true;index;not null" json:"blockedId"` // Engellenen kullanıcı
+	Blocked   User      `gorm:"foreignKey:BlockedID" json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
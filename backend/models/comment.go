@@ -6,6 +6,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// Yorum izinleri (User.CommentPermission ve gönderi/reel bazında geçersiz kılma)
+const (
+	CommentPermissionAll       = "all"
+	CommentPermissionFollowers = "followers"
+	CommentPermissionNone      = "none"
+)
+
 // Comment - Yorum modeli
 type Comment struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
@@ -19,6 +26,7 @@ type Comment struct {
 	ParentID  *uint          `json:"parentId,omitempty"`                           // Üst yorumun ID'si (yanıt yorumlar için)
 	Replies   []Comment      `gorm:"foreignKey:ParentID" json:"replies,omitempty"` // Alt yorumlar
 	IsLiked   bool           `gorm:"-" json:"isLiked"`                             // Geçici alan, veritabanında saklanmaz
+	Entities  []TextEntity   `gorm:"-" json:"entities,omitempty"`                  // Hashtag, mention ve URL ofsetleri
 	CreatedAt time.Time      `json:"createdAt"`
 	UpdatedAt time.Time      `json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -26,17 +34,18 @@ type Comment struct {
 
 // CommentResponse - API yanıtı için yorum yapısı
 type CommentResponse struct {
-	ID        uint      `json:"id"`
-	Content   string    `json:"content"`
-	UserID    uint      `json:"userId"`
-	PostID    *uint     `json:"postId,omitempty"`
-	ReelID    *uint     `json:"reelId,omitempty"`
-	ParentID  *uint     `json:"parentId,omitempty"`
-	User      User      `json:"user"`
-	Replies   []Comment `json:"replies,omitempty"`
-	LikeCount int       `json:"likeCount"`
-	IsLiked   bool      `json:"isLiked"`
-	CreatedAt time.Time `json:"createdAt"`
+	ID        uint         `json:"id"`
+	Content   string       `json:"content"`
+	UserID    uint         `json:"userId"`
+	PostID    *uint        `json:"postId,omitempty"`
+	ReelID    *uint        `json:"reelId,omitempty"`
+	ParentID  *uint        `json:"parentId,omitempty"`
+	User      User         `json:"user"`
+	Replies   []Comment    `json:"replies,omitempty"`
+	LikeCount int          `json:"likeCount"`
+	IsLiked   bool         `json:"isLiked"`
+	Entities  []TextEntity `json:"entities"`
+	CreatedAt time.Time    `json:"createdAt"`
 }
 
 // CommentLike - Yorum beğeni ilişkisi
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ImageVariant - Yüklenen bir görselin işlenmiş boyut/format varyantları
+type ImageVariant struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	SourceKey  string    `gorm:"index:idx_image_variant,unique:true;not null" json:"sourceKey"` // Orijinal görselin depo anahtarı
+	Variant    string    `gorm:"index:idx_image_variant,unique:true;size:20" json:"variant"`    // thumb, feed, full
+	Format     string    `gorm:"index:idx_image_variant,unique:true;size:10" json:"format"`     // jpeg, png, webp, avif
+	Width      int       `json:"width"`
+	Height     int       `json:"height"`
+	Size       int64     `json:"size"`
+	StorageKey string    `gorm:"not null" json:"storageKey"` // Varyantın depo anahtarı
+	CreatedAt  time.Time `json:"createdAt"`
+}
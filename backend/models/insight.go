@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// İçerik türleri (içgörü kayıtlarında kullanılır)
+const (
+	ContentTypePost = "post"
+	ContentTypeReel = "reel"
+)
+
+// Kitle dağılımı boyutları
+const (
+	AudienceDimensionLocation     = "location"     // Takipçilerin konumu
+	AudienceDimensionInterest     = "interest"     // Takipçilerin en çok etkileşim kurduğu etiketler
+	AudienceDimensionRelationship = "relationship" // Erişilen kullanıcıların takipçi olup olmadığı
+)
+
+// ContentImpression - Bir gönderinin bir kullanıcıya bir gün içindeki gösterimleri. Ham olay kaydıdır;
+// tekrarlanan gösterimler yeni satır açmak yerine Count'u artırır. Günlük içgörü işi tarafından özetlenir
+// ve saklama süresi dolunca silinir.
+type ContentImpression struct {
+	ID          uint      `gorm:"primaryKey"`
+	ContentType string    `gorm:"size:10;not null;index:idx_impression_content;uniqueIndex:idx_impression_viewer_day"`
+	ContentID   uint      `gorm:"not null;index:idx_impression_content;uniqueIndex:idx_impression_viewer_day"`
+	ViewerID    uint      `gorm:"not null;uniqueIndex:idx_impression_viewer_day"`
+	Day         string    `gorm:"size:10;not null;uniqueIndex:idx_impression_viewer_day"` // 2006-01-02
+	Count       int       `gorm:"not null;default:1"`                                     // Gün içindeki gösterim sayısı
+	CreatedAt   time.Time `gorm:"index"`                                                  // İlk gösterim anı
+}
+
+// ProfileVisit - Bir kullanıcının başka bir kullanıcının profilini ziyareti (ham olay kaydı)
+type ProfileVisit struct {
+	ID            uint      `gorm:"primaryKey"`
+	ProfileUserID uint      `gorm:"not null;index"`
+	VisitorID     uint      `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"index"`
+}
+
+// DailyContentInsight - Bir gönderi veya reelin günlük içgörü özeti
+type DailyContentInsight struct {
+	ID          uint   `gorm:"primaryKey" json:"-"`
+	OwnerID     uint   `gorm:"not null;index" json:"-"`
+	ContentType string `gorm:"size:10;not null;uniqueIndex:idx_daily_content" json:"contentType"`
+	ContentID   uint   `gorm:"not null;uniqueIndex:idx_daily_content" json:"contentId"`
+	Day         string `gorm:"size:10;not null;uniqueIndex:idx_daily_content;index" json:"day"` // 2006-01-02
+	Impressions int    `json:"impressions"`                                                     // Toplam gösterim
+	Reach       int    `json:"reach"`                                                           // Tekil erişilen kullanıcı
+	Likes       int    `json:"likes"`
+	Saves       int    `json:"saves"`
+	Comments    int    `json:"comments"`
+	Shares      int    `json:"shares"`
+}
+
+// DailyAccountInsight - Bir hesabın günlük takipçi ve profil ziyareti özeti
+type DailyAccountInsight struct {
+	ID            uint   `gorm:"primaryKey" json:"-"`
+	UserID        uint   `gorm:"not null;uniqueIndex:idx_daily_account" json:"-"`
+	Day           string `gorm:"size:10;not null;uniqueIndex:idx_daily_account;index" json:"day"`
+	FollowerCount int    `json:"followerCount"` // Gün sonundaki takipçi sayısı
+	NewFollowers  int    `json:"newFollowers"`
+	ProfileVisits int    `json:"profileVisits"`
+	UniqueVisits  int    `json:"uniqueVisits"`
+}
+
+// DailyAudienceInsight - Bir hesabın kitlesinin belirli bir boyuttaki günlük dağılımı
+type DailyAudienceInsight struct {
+	ID        uint   `gorm:"primaryKey" json:"-"`
+	UserID    uint   `gorm:"not null;index:idx_daily_audience" json:"-"`
+	Day       string `gorm:"size:10;not null;index:idx_daily_audience" json:"day"`
+	Dimension string `gorm:"size:20;not null" json:"dimension"`
+	Value     string `json:"value"`
+	Count     int    `json:"count"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Medya referans türleri
+const (
+	MediaRefPost         = "post"
+	MediaRefPostRevision = "post_revision" // Düzenleme geçmişinde gösterilen eski gönderi görselleri
+	MediaRefReel         = "reel"
+	MediaRefMessage      = "message"
+	MediaRefAvatar       = "avatar"
+	MediaRefStory        = "story"
+)
+
+// Media - Depoya yüklenmiş bir dosyanın içerik kaydı. Aynı içerik (SHA-256 ve boyut) bir kez saklanır ve
+// yükleyen tüm kullanıcılar arasında paylaşılır; kimlerin yüklediği MediaOwner kayıtlarında tutulur.
+type Media struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	OwnerID        uint      `gorm:"index" json:"ownerId"` // İlk yükleyen kullanıcı (eski dosyalarda 0 olabilir)
+	StorageKey     string    `gorm:"uniqueIndex;not null" json:"storageKey"`
+	URL            string    `json:"url"`
+	SHA256         string    `gorm:"size:64;index:idx_media_hash" json:"sha256"` // Yüklenen içeriğin özeti
+	Size           int64     `gorm:"index:idx_media_hash" json:"size"`
+	MimeType       string    `json:"mimeType"`
+	ReferenceCount int       `gorm:"not null;default:0" json:"referenceCount"` // Dosyayı kullanan içerik sayısı (MediaReference)
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"` // Son referans değişikliği; çöp toplama bekleme süresi buradan sayılır
+}
+
+// MediaOwner - Bir kullanıcının paylaşılan medya dosyasını yüklediğini gösterir. Dosya, yükleyen her
+// kullanıcının kotasından düşer ve her biri dosyayı kendi içeriklerinde kullanabilir.
+type MediaOwner struct {
+	ID        uint `gorm:"primaryKey"`
+	MediaID   uint `gorm:"not null;uniqueIndex:idx_media_owner"`
+	UserID    uint `gorm:"not null;uniqueIndex:idx_media_owner;index"`
+	CreatedAt time.Time
+}
+
+// MediaReference - Bir medya dosyasını kullanan içerik (gönderi, reel, mesaj, profil fotoğrafı, hikaye)
+type MediaReference struct {
+	ID        uint   `gorm:"primaryKey"`
+	MediaID   uint   `gorm:"not null;uniqueIndex:idx_media_reference"`
+	RefType   string `gorm:"size:20;not null;uniqueIndex:idx_media_reference;index:idx_media_reference_owner"`
+	RefID     uint   `gorm:"not null;uniqueIndex:idx_media_reference;index:idx_media_reference_owner"`
+	CreatedAt time.Time
+}
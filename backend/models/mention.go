@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// TextEntity - Metin içinde bulunan hashtag, mention ve URL varlıklarını temsil eder
+// Start/End ofsetleri JavaScript string indeksleriyle uyumlu olması için UTF-16 kod birimi cinsindendir
+type TextEntity struct {
+	Type   string `json:"type"`             // "hashtag", "mention" veya "url"
+	Text   string `json:"text"`             // Metindeki ham hali (#etiket, @kullanici, https://...)
+	Value  string `json:"value"`            // Normalleştirilmiş değer (etiket adı, kullanıcı adı, URL)
+	Start  int    `json:"start"`            // Başlangıç ofseti (dahil)
+	End    int    `json:"end"`              // Bitiş ofseti (hariç)
+	UserID *uint  `json:"userId,omitempty"` // Mention bir kullanıcıya çözümlendiyse kullanıcı ID'si
+}
+
+// Mention - Gönderi, reel veya yorum metninde @ ile bahsedilen kullanıcıları tutar
+type Mention struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	MentionedUserID uint      `gorm:"index;not null" json:"mentionedUserId"` // Bahsedilen kullanıcı
+	FromUserID      uint      `gorm:"index;not null" json:"fromUserId"`      // Metni yazan kullanıcı
+	PostID          *uint     `gorm:"index" json:"postId,omitempty"`
+	ReelID          *uint     `gorm:"index" json:"reelId,omitempty"`
+	CommentID       *uint     `gorm:"index" json:"commentId,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
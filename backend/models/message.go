@@ -4,6 +4,16 @@ import (
 	"time"
 )
 
+// Mesajla paylaşılabilen içerik türleri
+const (
+	SharedContentPost    = "post"
+	SharedContentReel    = "reel"
+	SharedContentProfile = "profile"
+)
+
+// MessageMediaTypeShared paylaşılan içerik taşıyan mesajların medya türü
+const MessageMediaTypeShared = "shared"
+
 // Message modeli - Kullanıcılar arası mesajlaşma
 type Message struct {
 	ID         uint      `gorm:"primaryKey" json:"id"`
@@ -12,6 +22,9 @@ type Message struct {
 	Content    string    `gorm:"type:text" json:"content"`
 	MediaURL   string    `json:"mediaUrl"`
 	MediaType  string    `json:"mediaType"`
+	StoryID    *uint     `gorm:"index" json:"storyId,omitempty"`                               // Hikayeye yanıt olarak gönderildiyse
+	SharedType string    `gorm:"size:10;index:idx_message_shared" json:"sharedType,omitempty"` // post, reel veya profile
+	SharedID   *uint     `gorm:"index:idx_message_shared" json:"sharedId,omitempty"`
 	SentAt     time.Time `gorm:"not null" json:"sentAt"`
 	IsRead     bool      `gorm:"default:false" json:"isRead"`
 	CreatedAt  time.Time `json:"createdAt"`
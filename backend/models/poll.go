@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Anket sınırları
+const (
+	PollMinOptions  = 2
+	PollMaxOptions  = 4
+	PollMinDuration = 5 * time.Minute
+	PollMaxDuration = 7 * 24 * time.Hour
+)
+
+// Poll - Gönderiye eklenen anket. Süre gönderi yayınlandığında başlar; taslak ve zamanlanmış
+// gönderilerde ExpiresAt yayın anına kadar boştur.
+type Poll struct {
+	ID              uint         `gorm:"primaryKey" json:"id"`
+	PostID          uint         `gorm:"not null;uniqueIndex" json:"postId"`
+	MultipleChoice  bool         `gorm:"default:false" json:"multipleChoice"`
+	DurationMinutes int          `gorm:"not null" json:"durationMinutes"`
+	ExpiresAt       *time.Time   `gorm:"index" json:"expiresAt"`
+	ClosedAt        *time.Time   `gorm:"index" json:"closedAt"`       // Kapanış işlenip sahibine bildirildiğinde doldurulur
+	VoterCount      int          `gorm:"default:0" json:"voterCount"` // Oy veren kullanıcı sayısı
+	Options         []PollOption `gorm:"foreignKey:PollID" json:"options"`
+	CreatedAt       time.Time    `json:"createdAt"`
+	UpdatedAt       time.Time    `json:"updatedAt"`
+}
+
+// PollOption - Anket seçeneği; VoteCount oylarla atomik olarak artırılır
+type PollOption struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	PollID    uint   `gorm:"not null;index" json:"pollId"`
+	Text      string `gorm:"size:100;not null" json:"text"`
+	Position  int    `gorm:"default:0" json:"position"`
+	VoteCount int    `gorm:"default:0" json:"voteCount"`
+}
+
+// PollVote - Kullanıcının ankete verdiği oy. Her kullanıcı bir ankete bir kez oy verebilir;
+// seçilen seçenekler (çoklu seçimli anketlerde birden fazla) PollVoteOption kayıtlarında tutulur.
+type PollVote struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	PollID    uint             `gorm:"not null;uniqueIndex:idx_poll_voter" json:"pollId"`
+	UserID    uint             `gorm:"not null;uniqueIndex:idx_poll_voter" json:"userId"`
+	Options   []PollVoteOption `gorm:"foreignKey:VoteID" json:"-"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+// PollVoteOption - Bir oyda seçilen seçenek
+type PollVoteOption struct {
+	ID       uint `gorm:"primaryKey"`
+	VoteID   uint `gorm:"not null;uniqueIndex:idx_poll_vote_option"`
+	OptionID uint `gorm:"not null;uniqueIndex:idx_poll_vote_option;index"`
+}
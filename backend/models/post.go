@@ -6,46 +6,99 @@ import (
 	"gorm.io/gorm"
 )
 
+// Gönderi ve reel kitleleri
+const (
+	AudienceEveryone     = "everyone"      // Hesap gizlilik ayarına göre görünür
+	AudienceCloseFriends = "close_friends" // Sadece sahibinin yakın arkadaş listesindekiler görür
+)
+
+// Gönderi ve reel yayın durumları
+const (
+	PublishStatusDraft     = "draft"     // Sadece yazar görür, yayın zamanı yok
+	PublishStatusScheduled = "scheduled" // ScheduledAt geldiğinde zamanlayıcı tarafından yayınlanır
+	PublishStatusPublished = "published"
+)
+
+// İçerik paylaşım kanalları (paylaşım kayıtlarında tutulur)
+const (
+	ShareChannelLink    = "link"    // Bağlantı olarak paylaşım
+	ShareChannelRepost  = "repost"  // Takipçilerle yeniden paylaşım
+	ShareChannelQuote   = "quote"   // Yorum eklenerek alıntılama
+	ShareChannelMessage = "message" // Direkt mesajla paylaşım
+)
+
+// MaxPinnedPosts profilin üstüne sabitlenebilecek en fazla gönderi sayısı
+const MaxPinnedPosts = 3
+
 // Post - Gönderi modeli
 type Post struct {
-	ID           uint `gorm:"primaryKey"`
-	UserID       uint `gorm:"not null"`
-	User         User `gorm:"foreignKey:UserID"`
-	Content      string
-	Caption      string      // Gönderi başlığı/açıklaması
-	Tags         []string    `gorm:"-"` // Tags verisi geçici olarak tutulacak
-	TagsString   string      // Virgülle ayrılmış etiketler veritabanında saklanacak
-	LikeCount    int         `gorm:"default:0"`
-	CommentCount int         `gorm:"default:0"`
-	Images       []PostImage `gorm:"foreignKey:PostID"`
-	LikedBy      []User      `gorm:"many2many:likes;"`
-	SavedBy      []User      `gorm:"many2many:saved_posts;"`
-	Comments     []Comment   `gorm:"foreignKey:PostID"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	DeletedAt    gorm.DeletedAt `gorm:"index"`
+	ID                uint `gorm:"primaryKey"`
+	UserID            uint `gorm:"not null"`
+	User              User `gorm:"foreignKey:UserID"`
+	Content           string
+	Caption           string      // Gönderi başlığı/açıklaması
+	Tags              []string    `gorm:"-"` // Tags verisi geçici olarak tutulacak
+	TagsString        string      // Virgülle ayrılmış etiketler veritabanında saklanacak
+	LikeCount         int         `gorm:"default:0"`
+	CommentCount      int         `gorm:"default:0"`
+	ShareCount        int         `gorm:"default:0"`                         // post_shares tablosundan hesaplanır
+	RepostOfType      string      `gorm:"size:10;index:idx_post_repost_of"`  // Yeniden paylaşılan içeriğin türü: post, reel
+	RepostOfID        *uint       `gorm:"index:idx_post_repost_of"`          // Yeniden paylaşılan içerik (metin varsa alıntı)
+	Audience          string      `gorm:"size:20;default:'everyone';index"`  // everyone, close_friends
+	CommentPermission string      `gorm:"size:20"`                           // all, followers, none; boşsa sahibinin hesap ayarı geçerli
+	PublishStatus     string      `gorm:"size:20;default:'published';index"` // draft, scheduled, published
+	ScheduledAt       *time.Time  `gorm:"index"`                             // Zamanlanmış yayın zamanı
+	HasPoll           bool        `gorm:"default:false"`                     // Gönderiye anket eklenmişse
+	ArchivedAt        *time.Time  `gorm:"index"`                             // Arşivlendiyse sadece sahibi görür
+	PinnedAt          *time.Time  `gorm:"index"`                             // Profilde sabitlendiyse (en fazla MaxPinnedPosts)
+	Images            []PostImage `gorm:"foreignKey:PostID"`
+	LikedBy           []User      `gorm:"many2many:likes;"`
+	SavedBy           []User      `gorm:"many2many:saved_posts;"`
+	Comments          []Comment   `gorm:"foreignKey:PostID"`
+	EditedAt          *time.Time  // Son düzenleme zamanı (hiç düzenlenmediyse nil)
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
 }
 
+// Reel işleme durumları
+const (
+	ReelStatusProcessing = "processing"
+	ReelStatusReady      = "ready"
+	ReelStatusFailed     = "failed"
+)
+
 // Reels - Table
 type Reels struct {
-	ID           uint `gorm:"primaryKey"`
-	UserID       uint `gorm:"not null"`
-	User         User `gorm:"foreignKey:UserID"`
-	Caption      string
-	VideoURL     string `gorm:"not null"`
-	ThumbnailURL string // Kapak fotoğrafı URL'si eklendi
-	Music        string
-	Duration     int       `gorm:"default:15"` // Saniye cinsinden süre
-	LikeCount    int       `gorm:"default:0"`
-	CommentCount int       `gorm:"default:0"`
-	ShareCount   int       `gorm:"default:0"`
-	ViewCount    int       `gorm:"default:0"`
-	LikedBy      []User    `gorm:"many2many:reel_likes;"`
-	SavedBy      []User    `gorm:"many2many:saved_reels;"`
-	Comments     []Comment `gorm:"-"` // Use - to tell GORM to ignore this field for now
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	DeletedAt    gorm.DeletedAt `gorm:"index"`
+	ID                uint `gorm:"primaryKey"`
+	UserID            uint `gorm:"not null"`
+	User              User `gorm:"foreignKey:UserID"`
+	Caption           string
+	VideoURL          string `gorm:"not null"`
+	ThumbnailURL      string // Kapak fotoğrafı URL'si eklendi
+	Music             string
+	Duration          int        `gorm:"default:15"` // Saniye cinsinden süre
+	LikeCount         int        `gorm:"default:0"`
+	CommentCount      int        `gorm:"default:0"`
+	ShareCount        int        `gorm:"default:0"`
+	ViewCount         int        `gorm:"default:0"`             // Tekilleştirilmiş görüntülenme sayısı
+	LegacyViewCount   int        `gorm:"default:0"`             // İzleme oturumları tutulmadan önce sayılan görüntülenmeler
+	WatchSeconds      int        `gorm:"default:0"`             // Tüm görüntülenmelerin toplam izleme süresi
+	CompletedCount    int        `gorm:"default:0"`             // Sonuna kadar izlenen görüntülenme sayısı
+	Status            string     `gorm:"default:'ready';index"` // processing, ready, failed
+	HLSURL            string     // İşlenmiş videonun HLS ana oynatma listesi
+	VideoCodec        string     // Orijinal dosyanın video kodeği
+	ProcessError      string     // İşleme başarısız olduysa nedeni
+	Audience          string     `gorm:"size:20;default:'everyone';index"`  // everyone, close_friends
+	CommentPermission string     `gorm:"size:20"`                           // all, followers, none; boşsa sahibinin hesap ayarı geçerli
+	PublishStatus     string     `gorm:"size:20;default:'published';index"` // draft, scheduled, published
+	ScheduledAt       *time.Time `gorm:"index"`                             // Zamanlanmış yayın zamanı
+	LikedBy           []User     `gorm:"many2many:reel_likes;"`
+	SavedBy           []User     `gorm:"many2many:saved_reels;"`
+	Comments          []Comment  `gorm:"-"` // Use - to tell GORM to ignore this field for now
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
 }
 
 // Like - Beğeni ilişkisi (ara tablo)
@@ -69,7 +122,27 @@ type ReelLike struct {
 	CreatedAt time.Time
 }
 
-// SavedReel - Kaydedilen reel ilişkisi (ara tablo)
+// ReelShare - Reel paylaşım kaydı (ShareCount bu tablodan hesaplanır)
+type ReelShare struct {
+	ID        uint   `gorm:"primaryKey"`
+	ReelID    uint   `gorm:"not null;index"`
+	UserID    uint   `gorm:"not null;index"`
+	Channel   string `gorm:"size:20;default:'link'"` // link, repost, quote
+	RepostID  *uint  `gorm:"index"`                  // Yeniden paylaşım veya alıntı gönderisi
+	CreatedAt time.Time
+}
+
+// PostShare - Gönderi paylaşım kaydı (ShareCount bu tablodan hesaplanır)
+type PostShare struct {
+	ID        uint   `gorm:"primaryKey"`
+	PostID    uint   `gorm:"not null;index"`
+	UserID    uint   `gorm:"not null;index"`
+	Channel   string `gorm:"size:20;default:'link'"` // link, repost, quote
+	RepostID  *uint  `gorm:"index"`                  // Yeniden paylaşım veya alıntı gönderisi
+	CreatedAt time.Time
+}
+
+// SavedReel - Kaydedilen reel ilişkisi (ara tablo)
 type SavedReel struct {
 	UserID    uint `gorm:"primaryKey"`
 	ReelID    uint `gorm:"primaryKey"`
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// PostRevision - Gönderinin düzenlenmeden önceki hali. Her düzenlemede bir önceki sürüm saklanır.
+type PostRevision struct {
+	ID         uint                `gorm:"primaryKey" json:"id"`
+	PostID     uint                `gorm:"not null;index" json:"postId"`
+	EditorID   uint                `gorm:"not null" json:"editorId"`
+	Content    string              `json:"content"`
+	Caption    string              `json:"caption"`
+	TagsString string              `json:"-"` // Virgülle ayrılmış etiketler
+	Images     []PostRevisionImage `gorm:"foreignKey:RevisionID" json:"-"`
+	CreatedAt  time.Time           `gorm:"index" json:"createdAt"` // Sürümün yerini yenisine bıraktığı an
+}
+
+// PostRevisionImage - Revizyondaki bir görsel. URL'ler virgül içerebildiğinden ayrı satırlarda saklanır.
+type PostRevisionImage struct {
+	ID         uint   `gorm:"primaryKey"`
+	RevisionID uint   `gorm:"not null;index"`
+	Position   int    `gorm:"not null"`
+	URL        string `gorm:"not null"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Reel izleme olayları
+const (
+	ReelViewEventStart    = "start"
+	ReelViewEventProgress = "progress"
+	ReelViewEventComplete = "complete"
+	ReelViewEventLoop     = "loop"
+)
+
+// ReelView - Bir kullanıcının bir reeli izleme oturumu. Oturum, izleyicinin son olayından itibaren
+// tekilleştirme penceresi boyunca açık kalır; bu sürede gelen start olayları aynı oturuma (tek
+// görüntülenmeye) eklenir, pencere dolduktan sonraki start yeni oturum açar.
+type ReelView struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ReelID       uint      `gorm:"not null;index:idx_reel_view_viewer" json:"reelId"`
+	ViewerID     uint      `gorm:"not null;index:idx_reel_view_viewer" json:"viewerId"`
+	Progress     int       `gorm:"default:0" json:"progress"`     // Mevcut döngüde izlenen en yüksek yüzde (0-100)
+	LoopCount    int       `gorm:"default:0" json:"loopCount"`    // Baştan sona izlenip yeniden başlayan döngü sayısı
+	WatchSeconds int       `gorm:"default:0" json:"watchSeconds"` // Oturumdaki toplam izleme süresi
+	Completed    bool      `gorm:"default:false" json:"completed"`
+	StartedAt    time.Time `gorm:"index" json:"startedAt"`
+	LastEventAt  time.Time `gorm:"index:idx_reel_view_viewer" json:"lastEventAt"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Koleksiyona kaydedilebilen içerik türleri
+const (
+	SavedItemPost = "post"
+	SavedItemReel = "reel"
+)
+
+// DefaultCollectionName kullanıcının tüm kayıtlarını içeren varsayılan koleksiyonun adı
+const DefaultCollectionName = "All"
+
+// SavedCollection - Kullanıcının kaydettiği gönderi ve reelleri gruplayan adlandırılmış koleksiyon.
+// Her kullanıcının silinemeyen bir varsayılan koleksiyonu vardır; kaydedilen her içerik ona da eklenir.
+type SavedCollection struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	UserID        uint      `gorm:"not null;index;uniqueIndex:idx_saved_collection_default,where:is_default = 1" json:"userId"` // Kullanıcı başına tek varsayılan koleksiyon
+	Name          string    `gorm:"size:100;not null" json:"name"`
+	IsDefault     bool      `gorm:"default:false" json:"isDefault"`
+	Position      int       `gorm:"default:0" json:"position"`    // Kullanıcının belirlediği sıra (küçükten büyüğe)
+	CoverItemType string    `gorm:"size:10" json:"coverItemType"` // Boşsa en son eklenen içerik kapak olur
+	CoverItemID   uint      `json:"coverItemId"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// SavedCollectionItem - Koleksiyondaki bir gönderi veya reel
+type SavedCollectionItem struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CollectionID uint      `gorm:"not null;uniqueIndex:idx_collection_item" json:"collectionId"`
+	UserID       uint      `gorm:"not null;index" json:"userId"`
+	ItemType     string    `gorm:"size:10;not null;uniqueIndex:idx_collection_item;index:idx_collection_item_ref" json:"itemType"`
+	ItemID       uint      `gorm:"not null;uniqueIndex:idx_collection_item;index:idx_collection_item_ref" json:"itemId"`
+	CreatedAt    time.Time `gorm:"index" json:"createdAt"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SearchHistoryEntry - Kullanıcının yaptığı aramalar ve arama sonuçlarından tıkladığı içerikler
+type SearchHistoryEntry struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"index;not null" json:"userId"`
+	Query      string    `gorm:"size:200" json:"query"`
+	ResultType string    `gorm:"size:20" json:"resultType,omitempty"` // Tıklanan sonuç türü: user, post, reel, tag (boşsa sadece sorgu)
+	ResultID   *uint     `json:"resultId,omitempty"`                  // Tıklanan sonucun ID'si
+	CreatedAt  time.Time `gorm:"index" json:"createdAt"`
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Arama dizinindeki içerik türleri
+const (
+	SearchTypeUser = "user"
+	SearchTypePost = "post"
+	SearchTypeReel = "reel"
+	SearchTypeTag  = "tag"
+)
+
+// Arama dizini tablo adları
+const (
+	SearchIndexFTSTable   = "search_index"     // SQLite FTS5 sanal tablosu
+	SearchIndexPlainTable = "search_documents" // FTS5 olmayan derlemelerde kullanılan düz tablo
+)
+
+// Arama dizini durumu database paketi tarafından bağlantı sırasında ayarlanır
+var (
+	SearchIndexTable    string // Boşsa dizin devre dışıdır
+	SearchIndexFullText bool   // true ise SearchIndexTable bir FTS5 tablosudur
+)
+
+// SearchRowID her içerik için dizinde sabit bir rowid üretir (tür başına ayrı aralık)
+func SearchRowID(entityType string, entityID uint) int64 {
+	offset := map[string]int64{SearchTypeUser: 0, SearchTypePost: 1, SearchTypeReel: 2, SearchTypeTag: 3}[entityType]
+	return int64(entityID)*4 + offset
+}
+
+// upsertSearchDocument içeriği arama dizinine ekler veya günceller.
+// Dizin hataları asıl işlemi geri almasın diye sadece loglanır.
+func upsertSearchDocument(tx *gorm.DB, entityType string, entityID, ownerID uint, title string, body ...string) {
+	if SearchIndexTable == "" || entityID == 0 {
+		return
+	}
+
+	db := tx.Session(&gorm.Session{NewDB: true})
+	rowID := SearchRowID(entityType, entityID)
+	if err := db.Exec("DELETE FROM "+SearchIndexTable+" WHERE rowid = ?", rowID).Error; err != nil {
+		log.Printf("Arama dizini güncellenemedi (%s %d): %v", entityType, entityID, err)
+		return
+	}
+	if err := db.Exec(
+		"INSERT INTO "+SearchIndexTable+" (rowid, entity_type, entity_id, owner_id, title, body) VALUES (?, ?, ?, ?, ?, ?)",
+		rowID, entityType, entityID, ownerID, title, strings.Join(body, " "),
+	).Error; err != nil {
+		log.Printf("Arama dizini güncellenemedi (%s %d): %v", entityType, entityID, err)
+	}
+}
+
+// deleteSearchDocument içeriği arama dizininden kaldırır
+func deleteSearchDocument(tx *gorm.DB, entityType string, entityID uint) {
+	if SearchIndexTable == "" || entityID == 0 {
+		return
+	}
+
+	db := tx.Session(&gorm.Session{NewDB: true})
+	if err := db.Exec("DELETE FROM "+SearchIndexTable+" WHERE rowid = ?", SearchRowID(entityType, entityID)).Error; err != nil {
+		log.Printf("Arama dizininden silinemedi (%s %d): %v", entityType, entityID, err)
+	}
+}
+
+// Kısmi güncellemelerde (ör. sadece sayaç) modelde tüm alanlar bulunmadığından
+// hook'lar kaydın güncel halini veritabanından yeniden okur.
+
+// AfterSave kullanıcıyı arama dizininde günceller
+func (u *User) AfterSave(tx *gorm.DB) error {
+	if SearchIndexTable == "" || u.ID == 0 {
+		return nil
+	}
+	var user User
+	if err := tx.Session(&gorm.Session{NewDB: true}).Unscoped().
+		Select("id, username, full_name, bio, deleted_at").First(&user, u.ID).Error; err != nil {
+		return nil
+	}
+	if user.DeletedAt.Valid {
+		deleteSearchDocument(tx, SearchTypeUser, user.ID)
+		return nil
+	}
+	upsertSearchDocument(tx, SearchTypeUser, user.ID, user.ID, user.Username, user.FullName, user.Bio)
+	return nil
+}
+
+// AfterDelete kullanıcıyı arama dizininden kaldırır
+func (u *User) AfterDelete(tx *gorm.DB) error {
+	deleteSearchDocument(tx, SearchTypeUser, u.ID)
+	return nil
+}
+
+// AfterSave gönderiyi arama dizininde günceller
+func (p *Post) AfterSave(tx *gorm.DB) error {
+	if SearchIndexTable == "" || p.ID == 0 {
+		return nil
+	}
+	var post Post
+	if err := tx.Session(&gorm.Session{NewDB: true}).Unscoped().
+		Select("id, user_id, content, caption, tags_string, audience, publish_status, archived_at, deleted_at").First(&post, p.ID).Error; err != nil {
+		return nil
+	}
+	// Silinmiş, arşivlenmiş, henüz yayınlanmamış ve yakın arkadaşlara özel gönderiler aramada görünmez
+	if post.DeletedAt.Valid || post.ArchivedAt != nil || (post.PublishStatus != "" && post.PublishStatus != PublishStatusPublished) ||
+		post.Audience == AudienceCloseFriends {
+		deleteSearchDocument(tx, SearchTypePost, post.ID)
+		return nil
+	}
+	upsertSearchDocument(tx, SearchTypePost, post.ID, post.UserID, post.Caption,
+		post.Content, strings.ReplaceAll(post.TagsString, ",", " "))
+	return nil
+}
+
+// AfterDelete gönderiyi arama dizininden kaldırır
+func (p *Post) AfterDelete(tx *gorm.DB) error {
+	deleteSearchDocument(tx, SearchTypePost, p.ID)
+	return nil
+}
+
+// AfterSave reeli arama dizininde günceller
+func (r *Reels) AfterSave(tx *gorm.DB) error {
+	if SearchIndexTable == "" || r.ID == 0 {
+		return nil
+	}
+	var reel Reels
+	if err := tx.Session(&gorm.Session{NewDB: true}).Unscoped().
+		Select("id, user_id, caption, music, status, audience, publish_status, deleted_at").First(&reel, r.ID).Error; err != nil {
+		return nil
+	}
+	// Silinmiş, henüz işlenmemiş, yayınlanmamış veya yakın arkadaşlara özel reeller aramada görünmez
+	if reel.DeletedAt.Valid || (reel.Status != "" && reel.Status != ReelStatusReady) ||
+		(reel.PublishStatus != "" && reel.PublishStatus != PublishStatusPublished) || reel.Audience == AudienceCloseFriends {
+		deleteSearchDocument(tx, SearchTypeReel, reel.ID)
+		return nil
+	}
+	upsertSearchDocument(tx, SearchTypeReel, reel.ID, reel.UserID, reel.Caption, reel.Music)
+	return nil
+}
+
+// AfterDelete reeli arama dizininden kaldırır
+func (r *Reels) AfterDelete(tx *gorm.DB) error {
+	deleteSearchDocument(tx, SearchTypeReel, r.ID)
+	return nil
+}
+
+// AfterSave etiketi arama dizininde günceller
+func (t *Tag) AfterSave(tx *gorm.DB) error {
+	if SearchIndexTable == "" || t.ID == 0 {
+		return nil
+	}
+	var tag Tag
+	if err := tx.Session(&gorm.Session{NewDB: true}).Unscoped().
+		Select("id, name, deleted_at").First(&tag, t.ID).Error; err != nil {
+		return nil
+	}
+	if tag.DeletedAt.Valid {
+		deleteSearchDocument(tx, SearchTypeTag, tag.ID)
+		return nil
+	}
+	upsertSearchDocument(tx, SearchTypeTag, tag.ID, 0, tag.Name)
+	return nil
+}
+
+// AfterDelete etiketi arama dizininden kaldırır
+func (t *Tag) AfterDelete(tx *gorm.DB) error {
+	deleteSearchDocument(tx, SearchTypeTag, t.ID)
+	return nil
+}
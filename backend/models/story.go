@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Hikaye kitleleri
+const (
+	StoryAudienceFollowers    = "followers"
+	StoryAudienceCloseFriends = "close_friends"
+)
+
+// StoryLifetime hikayelerin yayında kaldığı süre
+const StoryLifetime = 24 * time.Hour
+
+// Story - 24 saat sonra kaybolan görsel veya video hikaye
+type Story struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"userId"`
+	User      User      `gorm:"foreignKey:UserID" json:"-"`
+	MediaURL  string    `gorm:"not null" json:"mediaUrl"`
+	MediaType string    `gorm:"size:10;not null" json:"mediaType"` // image, video
+	Caption   string    `json:"caption"`
+	Audience  string    `gorm:"size:20;default:'followers'" json:"audience"` // followers, close_friends
+	ViewCount int       `gorm:"default:0" json:"viewCount"`
+	ExpiresAt time.Time `gorm:"index" json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// StoryView - Bir hikayeyi görüntüleyen kullanıcı kaydı
+type StoryView struct {
+	ID       uint      `gorm:"primaryKey"`
+	StoryID  uint      `gorm:"not null;uniqueIndex:idx_story_viewer"`
+	ViewerID uint      `gorm:"not null;uniqueIndex:idx_story_viewer;index"`
+	Viewer   User      `gorm:"foreignKey:ViewerID"`
+	ViewedAt time.Time `gorm:"index"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// DismissedSuggestion - Kullanıcının önerilerden kaldırdığı kullanıcılar
+type DismissedSuggestion struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	UserID          uint      `gorm:"index:idx_dismissed_suggestion,unique:true;not null" json:"userId"`          // Öneriyi kaldıran kullanıcı
+	SuggestedUserID uint      `gorm:"index:idx_dismissed_suggestion,unique:true;not null" json:"suggestedUserId"` // Kaldırılan öneri
+	CreatedAt       time.Time `json:"createdAt"`
+}
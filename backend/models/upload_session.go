@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Yükleme oturumu durumları
+const (
+	UploadStatusUploading = "uploading"
+	UploadStatusComplete  = "complete"
+	UploadStatusFailed    = "failed"
+)
+
+// UploadSession - Parça parça devam ettirilebilen (tus uyumlu) dosya yükleme oturumu
+type UploadSession struct {
+	ID          string     `gorm:"primaryKey;size:36" json:"id"`
+	UserID      uint       `gorm:"index;not null" json:"userId"`
+	Kind        string     `gorm:"size:10" json:"kind"` // image, video
+	Filename    string     `json:"filename"`
+	FileType    string     `json:"fileType"`
+	Length      int64      `json:"length"`                             // Toplam dosya boyutu
+	Offset      int64      `gorm:"column:upload_offset" json:"offset"` // Şu ana kadar alınan bayt sayısı
+	Status      string     `gorm:"size:20;index" json:"status"`
+	URL         string     `json:"url,omitempty"` // Tamamlanan dosyanın medya adresi
+	Error       string     `json:"error,omitempty"`
+	ExpiresAt   time.Time  `gorm:"index" json:"expiresAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	UsedAt      *time.Time `json:"usedAt,omitempty"` // Bir reel veya gönderide kullanıldığı zaman
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// UploadPart - Bir yükleme oturumuna ait, depoda ayrı nesne olarak tutulan parça
+type UploadPart struct {
+	ID         uint   `gorm:"primaryKey"`
+	SessionID  string `gorm:"index;size:36;not null"`
+	Offset     int64  `gorm:"column:upload_offset;not null"`
+	Size       int64  `gorm:"not null"`
+	StorageKey string `gorm:"not null"`
+	CreatedAt  time.Time
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserQuota - Admin tarafından kullanıcıya özel tanımlanan yükleme kotaları.
+// Boş (nil) alanlarda varsayılan değerler geçerlidir; 0 sınırsız anlamına gelir.
+type UserQuota struct {
+	UserID          uint      `gorm:"primaryKey" json:"userId"`
+	StorageBytes    *int64    `json:"storageBytes"`    // Toplam depolama sınırı (bayt)
+	DailyFiles      *int      `json:"dailyFiles"`      // Son 24 saatte yüklenebilecek dosya sayısı
+	MaxVideoSeconds *int      `json:"maxVideoSeconds"` // En uzun video süresi
+	UpdatedBy       uint      `json:"updatedBy"`       // Kotayı değiştiren admin
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
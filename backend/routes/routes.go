@@ -5,6 +5,8 @@ import (
 	"log"
 	"social-media-app/backend/controllers"
 	"social-media-app/backend/services"
+	"social-media-app/backend/storage"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -28,6 +30,41 @@ func SetupRoutes() *gin.Engine {
 	controllers.SetNotificationService(notificationService)
 	log.Println("Notification servisi başlatıldı")
 
+	// Medya deposunu başlat (STORAGE_DRIVER=local|s3)
+	blobStore, err := storage.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Medya deposu başlatılamadı: %v", err)
+	}
+	controllers.SetBlobStore(blobStore)
+	log.Printf("Medya deposu başlatıldı: %T", blobStore)
+
+	// Reel video işleme kuyruğunu başlat (ffmpeg gerekir)
+	controllers.StartVideoProcessing(services.NewVideoProcessorFromEnv(), 1)
+
+	// Süresi dolan yarım yüklemeleri temizle
+	controllers.StartUploadSessionCleanup()
+
+	// Süresi dolan hikayeleri temizle
+	controllers.StartStoryCleanup()
+
+	// Hiçbir içerikte kullanılmayan medya dosyalarını periyodik olarak temizle
+	controllers.StartMediaGarbageCollector()
+
+	// Etkileşim sayaçlarını kaynak tablolarla periyodik olarak uzlaştır
+	controllers.StartCounterReconciliation()
+
+	// Günlük içgörü özetlerini periyodik olarak güncelle
+	controllers.StartInsightsAggregation()
+
+	// Yayın zamanı gelen zamanlanmış gönderi ve reelleri yayınla
+	controllers.StartScheduledPublisher()
+
+	// Süresi dolan anketleri kapat ve sahiplerine bildir
+	controllers.StartPollCloser()
+
+	// Gönderi etiketleyicisini ayarla (GEMINI_API_KEY varsa Gemini, yoksa anahtar kelime tabanlı)
+	controllers.SetPostTagger(services.NewTaggerFromEnv())
+
 	// Not: Run metodu private olduğundan kendisi dahili olarak çalışır
 	log.Println("Notification servisi aktif (HTTP polling tabanlı)")
 
@@ -38,11 +75,16 @@ func SetupRoutes() *gin.Engine {
 	// CORS ayarları - tüm isteklere izin ver
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Tus-Resumable, Upload-Length, Upload-Metadata, Upload-Offset")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Location, Tus-Resumable, Tus-Version, Tus-Extension, Tus-Max-Size, Upload-Offset, Upload-Length, Upload-Expires")
 		c.Writer.Header().Set("Access-Control-Max-Age", "86400") // 24 saat
 
 		if c.Request.Method == "OPTIONS" {
+			// tus istemcileri sunucu yeteneklerini OPTIONS ile sorgular
+			if strings.HasPrefix(c.Request.URL.Path, "/api/uploads") {
+				controllers.SetTusHeaders(c)
+			}
 			c.AbortWithStatus(204)
 			return
 		}
@@ -97,6 +139,22 @@ func SetupRoutes() *gin.Engine {
 			// Image upload endpointi
 			auth.POST("/upload/image", controllers.UploadImage)
 
+			// Devam ettirilebilir (tus uyumlu) yüklemeler
+			auth.POST("/uploads", controllers.CreateUploadSession)
+			auth.HEAD("/uploads/:id", controllers.HeadUploadSession)
+			auth.GET("/uploads/:id", controllers.GetUploadSession)
+			auth.PATCH("/uploads/:id", controllers.PatchUploadSession)
+			auth.DELETE("/uploads/:id", controllers.DeleteUploadSession)
+
+			// Hikayeler
+			auth.POST("/stories", controllers.CreateStory)
+			auth.GET("/stories/tray", controllers.GetStoryTray)
+			auth.GET("/stories/user/:userId", controllers.GetUserStories)
+			auth.POST("/stories/:id/view", controllers.MarkStoryViewed)
+			auth.GET("/stories/:id/viewers", controllers.GetStoryViewers)
+			auth.POST("/stories/:id/reply", controllers.ReplyToStory)
+			auth.DELETE("/stories/:id", controllers.DeleteStory)
+
 			// Kullanıcı profili ve ayarları
 			auth.GET("/user", controllers.GetUserProfile)
 			auth.PUT("/user/profile", controllers.UpdateProfile)
@@ -112,6 +170,16 @@ func SetupRoutes() *gin.Engine {
 			auth.DELETE("/user/follow/:username", controllers.UnfollowUser)
 			auth.DELETE("/user/follow-request/:username", controllers.CancelFollowRequestByUsername)
 
+			// Kullanıcı engelleme
+			auth.GET("/user/blocks", controllers.GetBlockedUsers)
+			auth.POST("/user/block/:username", controllers.BlockUser)
+			auth.DELETE("/user/block/:username", controllers.UnblockUser)
+
+			// Yakın arkadaş listesi
+			auth.GET("/user/close-friends", controllers.GetCloseFriends)
+			auth.POST("/user/close-friends/:username", controllers.AddCloseFriend)
+			auth.DELETE("/user/close-friends/:username", controllers.RemoveCloseFriend)
+
 			// Takip İstekleri Yönetimi
 			auth.GET("/follow-requests/pending", controllers.GetPendingFollowRequestsList)
 			auth.POST("/follow-requests/:request_id/accept", controllers.AcceptFollowRequestById)
@@ -124,12 +192,27 @@ func SetupRoutes() *gin.Engine {
 			// Gönderi rotaları
 			auth.GET("/posts", controllers.GetPosts)
 			auth.POST("/posts", controllers.CreatePost)
+			auth.GET("/posts/archived", controllers.GetArchivedPosts) // Kullanıcının arşivlediği gönderiler
 			auth.GET("/posts/:id", controllers.GetPostById)
+			auth.PATCH("/posts/:id", controllers.EditPost)
+			auth.GET("/posts/:id/revisions", controllers.GetPostRevisions)
 			auth.DELETE("/posts/:id", controllers.DeletePost)
 			auth.POST("/posts/:id/like", controllers.LikePost)
 			auth.DELETE("/posts/:id/like", controllers.UnlikePost)
 			auth.POST("/posts/:id/save", controllers.SavePost)
 			auth.DELETE("/posts/:id/save", controllers.UnsavePost)
+			auth.PUT("/posts/:id/publishing", controllers.UpdatePostPublishing)
+			auth.POST("/posts/:id/repost", controllers.RepostPost)
+			auth.DELETE("/posts/:id/repost", controllers.UndoPostRepost)
+			auth.GET("/posts/:id/poll", controllers.GetPostPoll)
+			auth.POST("/posts/:id/poll/vote", controllers.VotePoll)
+			auth.POST("/posts/:id/archive", controllers.ArchivePost)
+			auth.DELETE("/posts/:id/archive", controllers.RestorePost)
+			auth.POST("/posts/:id/pin", controllers.PinPost)
+			auth.DELETE("/posts/:id/pin", controllers.UnpinPost)
+
+			// Taslak ve zamanlanmış gönderi/reel rotaları (sadece yazarına görünür)
+			auth.GET("/drafts", controllers.GetDrafts)
 
 			// Profil rotaları (Diğer kullanıcılar için)
 			auth.GET("/profile/:username", controllers.GetUserByUsername)
@@ -138,6 +221,7 @@ func SetupRoutes() *gin.Engine {
 			// Yorum rotaları
 			auth.GET("/posts/:id/comments", controllers.GetComments)
 			auth.POST("/posts/:id/comments", controllers.AddComment)
+			auth.PUT("/posts/:id/comment-permission", controllers.UpdatePostCommentPermission)
 			auth.POST("/comments/:id/like", controllers.ToggleCommentLike)
 			auth.DELETE("/comments/:id", controllers.DeleteComment)
 			auth.POST("/comments/:id/report", controllers.ReportComment)
@@ -153,6 +237,7 @@ func SetupRoutes() *gin.Engine {
 			auth.GET("/messages", controllers.GetConversations)
 			auth.GET("/messages/:userId", controllers.GetConversation)
 			auth.POST("/messages/:userId", controllers.SendMessage)
+			auth.POST("/messages/share", controllers.ShareToMessages) // Gönderi, reel veya profili birden fazla konuşmaya gönderir
 			auth.POST("/messages/:userId/typing", controllers.SendTypingStatus)
 			auth.POST("/messages/read/:id", controllers.MarkMessageAsRead)
 			auth.GET("/messages/previous-chats", controllers.GetPreviousChats)         // Daha önce mesajlaşılan kullanıcıları getirir
@@ -165,11 +250,43 @@ func SetupRoutes() *gin.Engine {
 			auth.POST("/reels/:id/like", controllers.LikeReel)
 			auth.DELETE("/reels/:id/like", controllers.UnlikeReel)
 			auth.POST("/reels/:id/share", controllers.ShareReel)
+			auth.POST("/reels/:id/repost", controllers.RepostReel)
+			auth.DELETE("/reels/:id/repost", controllers.UndoReelRepost)
+			auth.POST("/reels/:id/view", controllers.RecordReelView)
 			auth.DELETE("/reels/:id", controllers.DeleteReel)
+			auth.PATCH("/reels/:id", controllers.EditReelDraft)
+			auth.PUT("/reels/:id/publishing", controllers.UpdateReelPublishing)
 			auth.GET("/profile/:username/reels", controllers.GetUserReels)
 			auth.GET("/reels/explore", controllers.GetExploreReels)
 			auth.POST("/reels/:id/save", controllers.SaveReel)
 			auth.DELETE("/reels/:id/save", controllers.UnsaveReel)
+			auth.GET("/reels/:id/comments", controllers.GetReelComments)
+			auth.POST("/reels/:id/comments", controllers.AddReelComment)
+			auth.PUT("/reels/:id/comment-permission", controllers.UpdateReelCommentPermission)
+
+			// Kayıt koleksiyonu rotaları (gönderi ve reeller)
+			auth.GET("/collections", controllers.GetCollections)
+			auth.POST("/collections", controllers.CreateCollection)
+			auth.PUT("/collections/order", controllers.ReorderCollections)
+			auth.PUT("/collections/:id", controllers.RenameCollection)
+			auth.DELETE("/collections/:id", controllers.DeleteCollection)
+			auth.PUT("/collections/:id/cover", controllers.SetCollectionCover)
+			auth.GET("/collections/:id/items", controllers.GetCollectionItems)
+			auth.POST("/collections/:id/items", controllers.AddCollectionItem)
+			auth.POST("/collections/:id/items/move", controllers.MoveCollectionItem)
+			auth.DELETE("/collections/:id/items/:type/:itemId", controllers.RemoveCollectionItem)
+
+			// Kullanıcı önerileri
+			auth.GET("/users/suggestions", controllers.GetSuggestedUsers)
+			auth.POST("/users/suggestions/:userId/dismiss", controllers.DismissSuggestion)
+
+			// Arama
+			auth.GET("/search", controllers.Search)
+			auth.GET("/users/search", controllers.SearchUsers)
+			auth.GET("/search/history", controllers.GetSearchHistory)
+			auth.POST("/search/history", controllers.AddSearchHistory)
+			auth.DELETE("/search/history", controllers.ClearSearchHistory)
+			auth.DELETE("/search/history/:id", controllers.DeleteSearchHistoryEntry)
 
 			// Geri Bildirim Rotası (Yeni Eklendi)
 			auth.POST("/feedback", controllers.SubmitFeedback)
@@ -194,6 +311,7 @@ func SetupRoutes() *gin.Engine {
 			auth.GET("/data-privacy/settings", controllers.GetDataPrivacySettings)
 			auth.PUT("/data-privacy/settings", controllers.UpdateDataPrivacySettings)
 			auth.POST("/data-privacy/download-request", controllers.RequestDataDownload)
+			auth.GET("/data-privacy/export", controllers.ExportUserData)
 			auth.POST("/data-privacy/deletion-request", controllers.RequestDataDeletion)
 
 			// Destek ve Geri Bildirim
@@ -203,10 +321,24 @@ func SetupRoutes() *gin.Engine {
 			auth.POST("/support/tickets/:id/messages", controllers.AddTicketMessage)
 			auth.PUT("/support/tickets/:id/close", controllers.CloseTicket)
 			auth.PUT("/support/tickets/:id/reopen", controllers.ReopenTicket)
+
+			// İçerik üretici içgörüleri
+			auth.GET("/insights/overview", controllers.GetInsightsOverview)
+			auth.GET("/insights/content", controllers.GetContentInsights)
+			auth.GET("/insights/posts/:id", controllers.GetPostInsights)
+			auth.GET("/insights/reels/:id", controllers.GetReelInsights)
+			auth.GET("/insights/audience", controllers.GetAudienceInsights)
+			auth.POST("/insights/impressions", controllers.RecordPostImpressions)
+
+			// Yönetim
+			admin := auth.Group("/admin")
+			admin.Use(controllers.AdminAuthMiddleware())
+			admin.POST("/media/gc", controllers.CollectMediaGarbage)
+			admin.POST("/counters/reconcile", controllers.ReconcileCounters)
+			admin.GET("/users/:id/quota", controllers.GetUserQuota)
+			admin.PUT("/users/:id/quota", controllers.UpdateUserQuota)
 		}
 
-		// Kullanıcı arama rotası (auth dışında)
-		api.GET("/users/search", controllers.SearchUsers)
 		api.GET("/users/id/:id", controllers.GetUserById)
 
 		// Basit test endpoint
@@ -214,17 +346,16 @@ func SetupRoutes() *gin.Engine {
 			c.JSON(200, gin.H{"message": "Test endpoint çalışıyor"})
 		})
 
-		// Test için geçici: Reels comments rotaları (auth middleware'ı olmadan)
-		api.GET("/reels/:id/comments", controllers.GetReelComments)
-		api.POST("/reels/:id/comments", controllers.AddReelComment)
+		// Test için geçici: sabit yorum verisi döndürür
 		api.GET("/test/reels/:id/comments", controllers.TestGetReelComments)
 
 		// WebSocket bağlantısı
 		api.GET("/ws", controllers.WebSocketHandler)
 	}
 
-	// Static dosya sunucusunu /api grubu DIŞINDA tanımla
-	router.Static("/uploads", "./uploads")
+	// Eski /uploads adresleri ve imzalı dosya bağlantıları depo üzerinden servis edilir
+	router.GET("/uploads/*key", controllers.ServeStoredUpload)
+	router.GET("/api/files/*key", controllers.ServeSignedFile)
 
 	fmt.Println("=====================================")
 	fmt.Println("Tüm API routeları yüklendi")
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/chai2010/webp"
+	"github.com/disintegration/imaging"
+
+	// GIF kodlayıcısını image.DecodeConfig için kaydet
+	_ "image/gif"
+)
+
+// Görsel çıktı formatları
+const (
+	ImageFormatJPEG = "jpeg"
+	ImageFormatPNG  = "png"
+	ImageFormatGIF  = "gif"
+	ImageFormatWebP = "webp"
+	ImageFormatAVIF = "avif"
+)
+
+// Görsel varyant adları
+const (
+	ImageVariantThumb = "thumb"
+	ImageVariantFeed  = "feed"
+	ImageVariantFull  = "full"
+)
+
+// ImageVariantSpec bir boyut varyantının en fazla genişliğini tanımlar
+type ImageVariantSpec struct {
+	Name     string
+	MaxWidth int
+}
+
+// ImageVariantSpecs küçükten büyüğe sıralı varyantlar
+var ImageVariantSpecs = []ImageVariantSpec{
+	{Name: ImageVariantThumb, MaxWidth: 320},
+	{Name: ImageVariantFeed, MaxWidth: 1080},
+	{Name: ImageVariantFull, MaxWidth: 2048},
+}
+
+// Kodlama kaliteleri
+const (
+	jpegQuality = 85
+	webpQuality = 80
+	avifQuality = 60
+)
+
+// ErrUnsupportedImage çözümlenemeyen veya desteklenmeyen görseller için döndürülür
+var ErrUnsupportedImage = errors.New("desteklenmeyen veya bozuk görsel")
+
+// DecodedImage yönü düzeltilmiş ve meta verilerinden arındırılmış görsel
+type DecodedImage struct {
+	Image  image.Image
+	Format string // Kaynak formatı: jpeg, png, gif
+}
+
+// EncodedImage kodlanmış bir görsel varyantı
+type EncodedImage struct {
+	Variant     string
+	Format      string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// avifencPath sistemde kurulu avifenc aracının (libavif) yolu. Araç PATH'te yoksa AVIF varyantları
+// üretilmez ve istemcilere WebP veya JPEG/PNG servis edilir; durum açılışta bir kez loglanır.
+var avifencPath, _ = exec.LookPath("avifenc")
+
+func init() {
+	if avifencPath == "" {
+		log.Println("Uyarı: avifenc bulunamadı, AVIF görsel varyantları üretilmeyecek (bkz. README)")
+	}
+}
+
+// DecodeImage görseli çözer ve EXIF yön bilgisine göre döndürür.
+// Piksel verisi yeniden kodlandığında EXIF/GPS gibi meta veriler taşınmaz.
+func DecodeImage(data []byte) (*DecodedImage, error) {
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	return &DecodedImage{Image: img, Format: format}, nil
+}
+
+// FallbackFormat modern formatları desteklemeyen istemciler için kullanılacak format
+func (d *DecodedImage) FallbackFormat() string {
+	if d.Format == ImageFormatPNG {
+		return ImageFormatPNG // Saydamlık korunur
+	}
+	return ImageFormatJPEG
+}
+
+// EncodeImage görseli verilen formatta kodlar
+func EncodeImage(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case ImageFormatJPEG:
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+	case ImageFormatPNG:
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	case ImageFormatWebP:
+		if err := webp.Encode(&buf, img, &webp.Options{Quality: webpQuality}); err != nil {
+			return nil, err
+		}
+	case ImageFormatAVIF:
+		return encodeAVIF(img)
+	default:
+		return nil, fmt.Errorf("bilinmeyen görsel formatı: %s", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImageContentType format adına karşılık gelen MIME tipini döndürür
+func ImageContentType(format string) string {
+	switch format {
+	case ImageFormatJPEG:
+		return "image/jpeg"
+	case ImageFormatPNG:
+		return "image/png"
+	case ImageFormatGIF:
+		return "image/gif"
+	case ImageFormatWebP:
+		return "image/webp"
+	case ImageFormatAVIF:
+		return "image/avif"
+	}
+	return "application/octet-stream"
+}
+
+// ImageFileExt format adına karşılık gelen dosya uzantısını döndürür
+func ImageFileExt(format string) string {
+	if format == ImageFormatJPEG {
+		return ".jpg"
+	}
+	return "." + format
+}
+
+// AVIFAvailable AVIF kodlamanın mümkün olup olmadığını döndürür
+func AVIFAvailable() bool {
+	return avifencPath != ""
+}
+
+// BuildImageVariants her boyut için geri dönüş formatında, WebP ve (mümkünse) AVIF çıktısı üretir.
+// Kaynak bir varyant genişliğinden küçükse büyütme yapılmaz, aynı boyuttaki tekrar varyantlar atlanır.
+func BuildImageVariants(ctx context.Context, decoded *DecodedImage) ([]EncodedImage, error) {
+	formats := []string{decoded.FallbackFormat(), ImageFormatWebP}
+	if AVIFAvailable() {
+		formats = append(formats, ImageFormatAVIF)
+	}
+
+	sourceWidth := decoded.Image.Bounds().Dx()
+	var variants []EncodedImage
+	lastWidth := 0
+	for _, spec := range ImageVariantSpecs {
+		width := spec.MaxWidth
+		if sourceWidth < width {
+			width = sourceWidth
+		}
+		if width == lastWidth {
+			continue
+		}
+		lastWidth = width
+
+		resized := decoded.Image
+		if width != sourceWidth {
+			resized = imaging.Resize(decoded.Image, width, 0, imaging.Lanczos)
+		}
+
+		for _, format := range formats {
+			if err := ctx.Err(); err != nil {
+				return variants, err
+			}
+			data, err := EncodeImage(resized, format)
+			if err != nil {
+				log.Printf("Görsel varyantı kodlanamadı (%s/%s): %v", spec.Name, format, err)
+				continue
+			}
+			variants = append(variants, EncodedImage{
+				Variant:     spec.Name,
+				Format:      format,
+				ContentType: ImageContentType(format),
+				Width:       resized.Bounds().Dx(),
+				Height:      resized.Bounds().Dy(),
+				Data:        data,
+			})
+		}
+	}
+	return variants, nil
+}
+
+// encodeAVIF görseli avifenc aracıyla AVIF'e dönüştürür
+func encodeAVIF(img image.Image) ([]byte, error) {
+	if avifencPath == "" {
+		return nil, errors.New("avifenc bulunamadı")
+	}
+
+	dir, err := os.MkdirTemp("", "avif-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input.png")
+	output := filepath.Join(dir, "output.avif")
+	file, err := os.Create(input)
+	if err != nil {
+		return nil, err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return nil, err
+	}
+	file.Close()
+
+	cmd := exec.Command(avifencPath, "-q", fmt.Sprint(avifQuality), "--speed", "8", input, output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("avifenc hatası: %v: %s", err, out)
+	}
+	return os.ReadFile(output)
+}
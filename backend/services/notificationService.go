@@ -23,6 +23,8 @@ const (
 	NotificationTypeFollowAccept  NotificationType = "follow_accept"
 	NotificationTypeMessage       NotificationType = "message"
 	NotificationTypePost          NotificationType = "post"
+	NotificationTypeRepost        NotificationType = "repost"
+	NotificationTypePollClosed    NotificationType = "poll_closed"
 	NotificationTypeSystem        NotificationType = "system"
 )
 
@@ -281,6 +283,44 @@ func (s *NotificationService) CreatePostNotification(ctx context.Context, userID
 	return s.SendNotification(ctx, notification)
 }
 
+// CreateRepostNotification, gönderi veya reelin yeniden paylaşıldığını/alıntılandığını bildirir.
+// entityType orijinal içeriğin türüdür (post, reel); repostID yeniden paylaşım gönderisidir.
+func (s *NotificationService) CreateRepostNotification(ctx context.Context, userID, reposterID, reposterName, reposterUsername, reposterImage, entityType, entityID, repostID, content string) error {
+	notification := Notification{
+		UserID:            userID,
+		ActorID:           reposterID,
+		ActorName:         reposterName,
+		ActorUsername:     reposterUsername,
+		ActorProfileImage: reposterImage,
+		Type:              NotificationTypeRepost,
+		EntityID:          entityID,
+		EntityType:        entityType,
+		EntityURL:         "/post/" + repostID,
+		Content:           content,
+		IsRead:            false,
+		CreatedAt:         time.Now(),
+	}
+
+	return s.SendNotification(ctx, notification)
+}
+
+// CreatePollClosedNotification, gönderideki anketin süresinin dolduğunu gönderi sahibine bildirir
+func (s *NotificationService) CreatePollClosedNotification(ctx context.Context, userID, postID, content string) error {
+	notification := Notification{
+		UserID:     userID,
+		ActorID:    userID,
+		Type:       NotificationTypePollClosed,
+		EntityID:   postID,
+		EntityType: "post",
+		EntityURL:  "/post/" + postID,
+		Content:    content,
+		IsRead:     false,
+		CreatedAt:  time.Now(),
+	}
+
+	return s.SendNotification(ctx, notification)
+}
+
 // WebSocket üzerinden bildirim gönder
 func (s *NotificationService) sendWebSocketNotification(notification Notification) {
 	// Detaylı loglama ekleyerek bildirim gönderme sürecini izle
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Bir gönderiye en fazla atanacak otomatik etiket sayısı
+const MaxGeneratedTags = 6
+
+// TagInput etiketleyiciye gönderilen gönderi bilgilerini taşır
+type TagInput struct {
+	Content   string
+	Caption   string
+	ImageURLs []string
+}
+
+// Tagger gönderi içeriğinden önem sırasına göre etiket önerir.
+// İlk etiketler ana (primary), kalanlar yardımcı (auxiliary) etiket olarak kullanılır.
+type Tagger interface {
+	SuggestTags(ctx context.Context, input TagInput) ([]string, error)
+}
+
+// NewTaggerFromEnv ortam değişkenlerine göre uygun etiketleyiciyi döndürür.
+// GEMINI_API_KEY tanımlıysa Gemini kullanılır, hata durumunda anahtar kelime etiketleyicisine düşülür.
+func NewTaggerFromEnv() Tagger {
+	keywordTagger := NewKeywordTagger()
+
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return keywordTagger
+	}
+
+	gemini := NewGeminiTagger(apiKey, os.Getenv("GEMINI_MODEL"))
+	gemini.Fallback = keywordTagger
+	return gemini
+}
+
+// ---------------------------------------------------------------------------
+// Anahtar kelime tabanlı etiketleyici
+// ---------------------------------------------------------------------------
+
+// Varsayılan kategori -> anahtar kelime eşlemesi
+var defaultTagKeywords = map[string][]string{
+	"doğa":       {"doğa", "nature", "orman", "forest", "dağ", "mountain", "göl", "lake", "ağaç", "tree", "çiçek", "flower"},
+	"deniz":      {"deniz", "sea", "sahil", "beach", "plaj", "ocean", "okyanus", "dalga", "wave"},
+	"seyahat":    {"seyahat", "travel", "tatil", "holiday", "vacation", "gezi", "trip", "tur", "yolculuk"},
+	"yemek":      {"yemek", "food", "lezzet", "tarif", "recipe", "kahvaltı", "breakfast", "akşam yemeği", "dinner", "restoran", "restaurant"},
+	"kahve":      {"kahve", "coffee", "espresso", "latte", "cafe", "kafe"},
+	"spor":       {"spor", "sport", "futbol", "football", "basketbol", "basketball", "maç", "match", "koşu", "running"},
+	"fitness":    {"fitness", "gym", "antrenman", "workout", "egzersiz", "exercise"},
+	"müzik":      {"müzik", "music", "şarkı", "song", "konser", "concert", "gitar", "guitar", "piyano", "piano"},
+	"sanat":      {"sanat", "art", "resim", "painting", "çizim", "drawing", "sergi", "exhibition", "tasarım", "design"},
+	"fotoğraf":   {"fotoğraf", "photo", "photography", "kamera", "camera", "çekim"},
+	"teknoloji":  {"teknoloji", "technology", "tech", "yazılım", "software", "kod", "code", "programlama", "programming", "bilgisayar", "computer"},
+	"moda":       {"moda", "fashion", "stil", "style", "kıyafet", "outfit", "elbise", "dress"},
+	"hayvanlar":  {"kedi", "cat", "köpek", "dog", "hayvan", "animal", "pet"},
+	"kitap":      {"kitap", "book", "okuma", "reading", "roman", "novel"},
+	"film":       {"film", "movie", "sinema", "cinema", "dizi", "series"},
+	"aile":       {"aile", "family", "anne", "baba", "çocuk", "kids"},
+	"arkadaşlık": {"arkadaş", "friend", "dostluk", "friendship"},
+	"şehir":      {"şehir", "city", "sokak", "street", "mimari", "architecture"},
+}
+
+// Etiket olarak kullanılmayacak sık geçen kelimeler
+var tagStopWords = map[string]bool{
+	"bir": true, "ve": true, "ile": true, "için": true, "bu": true, "şu": true, "çok": true,
+	"daha": true, "gibi": true, "ama": true, "veya": true, "olan": true, "olarak": true,
+	"the": true, "and": true, "with": true, "for": true, "this": true, "that": true,
+	"from": true, "have": true, "just": true, "very": true, "today": true, "bugün": true,
+}
+
+// KeywordTagger içerik ve başlıktaki anahtar kelimelere göre deterministik etiket üretir.
+// Dış servise ihtiyaç duymadığı için testlerde ve yapay zeka anahtarı olmayan ortamlarda kullanılır.
+type KeywordTagger struct {
+	Keywords map[string][]string
+}
+
+// NewKeywordTagger varsayılan anahtar kelime listesiyle yeni bir etiketleyici oluşturur
+func NewKeywordTagger() *KeywordTagger {
+	return &KeywordTagger{Keywords: defaultTagKeywords}
+}
+
+// SuggestTags kategorileri eşleşme sayısına göre sıralar, kalan yeri başlıktaki
+// öne çıkan kelimelerle doldurur. Aynı girdi her zaman aynı sonucu verir.
+func (t *KeywordTagger) SuggestTags(ctx context.Context, input TagInput) ([]string, error) {
+	text := strings.ToLower(input.Caption + " " + input.Content)
+	words := tokenizeTagText(text)
+
+	// Kategori puanları (başlıktaki eşleşmeler iki kat sayılır)
+	caption := " " + strings.Join(tokenizeTagText(strings.ToLower(input.Caption)), " ") + " "
+	joined := " " + strings.Join(words, " ") + " "
+	scores := make(map[string]int)
+	for tag, keywords := range t.Keywords {
+		for _, keyword := range keywords {
+			needle := " " + keyword + " "
+			if count := strings.Count(joined, needle); count > 0 {
+				scores[tag] += count
+				scores[tag] += strings.Count(caption, needle)
+			}
+		}
+	}
+
+	tags := sortedByScore(scores)
+	if len(tags) > MaxGeneratedTags {
+		tags = tags[:MaxGeneratedTags]
+	}
+
+	// Yer kalırsa başlıktaki en sık geçen anlamlı kelimelerle tamamla
+	if len(tags) < MaxGeneratedTags {
+		frequencies := make(map[string]int)
+		for _, word := range tokenizeTagText(strings.ToLower(input.Caption)) {
+			if len([]rune(word)) < 4 || tagStopWords[word] || !hasLetter(word) {
+				continue
+			}
+			frequencies[word]++
+		}
+
+		existing := make(map[string]bool)
+		for _, tag := range tags {
+			existing[tag] = true
+		}
+		for _, word := range sortedByScore(frequencies) {
+			if len(tags) >= MaxGeneratedTags {
+				break
+			}
+			if !existing[word] {
+				tags = append(tags, word)
+				existing[word] = true
+			}
+		}
+	}
+
+	return tags, nil
+}
+
+// tokenizeTagText metni harf/rakam dışındaki karakterlerden böler
+func tokenizeTagText(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// sortedByScore anahtarları puana göre azalan, eşitlikte alfabetik sırada döndürür
+func sortedByScore(scores map[string]int) []string {
+	keys := make([]string, 0, len(scores))
+	for key := range scores {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if scores[keys[i]] != scores[keys[j]] {
+			return scores[keys[i]] > scores[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func hasLetter(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------
+// Gemini etiketleyici
+// ---------------------------------------------------------------------------
+
+// GeminiTagger etiketleri Google Gemini API'si üzerinden sunucu tarafında üretir
+type GeminiTagger struct {
+	APIKey   string
+	Model    string
+	Fallback Tagger // Gemini başarısız olursa kullanılacak etiketleyici (opsiyonel)
+	client   *resty.Client
+}
+
+// NewGeminiTagger yeni bir Gemini etiketleyicisi oluşturur
+func NewGeminiTagger(apiKey, model string) *GeminiTagger {
+	if model == "" {
+		model = "gemini-1.5-flash"
+	}
+
+	client := resty.New()
+	client.SetTimeout(20 * time.Second)
+	client.SetBaseURL("https://generativelanguage.googleapis.com/v1beta")
+
+	return &GeminiTagger{
+		APIKey: apiKey,
+		Model:  model,
+		client: client,
+	}
+}
+
+type geminiRequest struct {
+	Contents []geminiContent `json:"contents"`
+}
+
+type geminiContent struct {
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+}
+
+// SuggestTags gönderi metnini modele gönderir ve virgülle ayrılmış yanıtı etiketlere çevirir
+func (t *GeminiTagger) SuggestTags(ctx context.Context, input TagInput) ([]string, error) {
+	tags, err := t.requestTags(ctx, input)
+	if err != nil && t.Fallback != nil {
+		return t.Fallback.SuggestTags(ctx, input)
+	}
+	return tags, err
+}
+
+func (t *GeminiTagger) requestTags(ctx context.Context, input TagInput) ([]string, error) {
+	if strings.TrimSpace(input.Content) == "" && strings.TrimSpace(input.Caption) == "" {
+		return []string{}, nil
+	}
+
+	prompt := fmt.Sprintf(
+		"Aşağıdaki sosyal medya gönderisi için en fazla %d adet kısa, küçük harfli Türkçe etiket üret. "+
+			"En önemli 4 etiketi başa yaz. Sadece virgülle ayrılmış etiketleri döndür, başka açıklama ekleme.\n\n"+
+			"Başlık: %s\nİçerik: %s",
+		MaxGeneratedTags, input.Caption, input.Content,
+	)
+
+	var result geminiResponse
+	resp, err := t.client.R().
+		SetContext(ctx).
+		SetQueryParam("key", t.APIKey).
+		SetHeader("Content-Type", "application/json").
+		SetBody(geminiRequest{
+			Contents: []geminiContent{{Parts: []geminiPart{{Text: prompt}}}},
+		}).
+		SetResult(&result).
+		Post("/models/" + t.Model + ":generateContent")
+	if err != nil {
+		return nil, fmt.Errorf("gemini isteği başarısız: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("gemini hata döndürdü: %s", resp.Status())
+	}
+	if len(result.Candidates) == 0 || len(result.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("gemini boş yanıt döndürdü")
+	}
+
+	return parseTagList(result.Candidates[0].Content.Parts[0].Text), nil
+}
+
+// parseTagList model yanıtını temizlenmiş, tekrarsız bir etiket listesine çevirir
+func parseTagList(text string) []string {
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' }) {
+		tag := strings.ToLower(strings.Trim(part, " \t\r#*-.\"'"))
+		if tag == "" || len([]rune(tag)) > 40 || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if len(tags) >= MaxGeneratedTags {
+			break
+		}
+	}
+	return tags
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestKeywordTaggerSuggestTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		input TagInput
+		want  []string
+	}{
+		{
+			name:  "boş girdi",
+			input: TagInput{},
+			want:  []string{},
+		},
+		{
+			name:  "kategoriler puana göre sıralanır, kalan yer başlıkla dolar",
+			input: TagInput{Caption: "Deniz kenarında kahve", Content: "sabah sahilde espresso içtik"},
+			want:  []string{"kahve", "deniz", "kenarında"},
+		},
+		{
+			name:  "eşanlamlı anahtar kelimeler tek kategoride toplanır",
+			input: TagInput{Caption: "Kedi ve köpek", Content: "cat dog pet"},
+			want:  []string{"hayvanlar", "kedi", "köpek"},
+		},
+		{
+			name:  "kategori yoksa başlıktaki kelimeler sıklığa göre kullanılır",
+			input: TagInput{Caption: "İstanbul İstanbul boğaz manzarası", Content: "bugün çok güzel"},
+			want:  []string{"istanbul", "boğaz", "manzarası"},
+		},
+		{
+			name:  "en fazla MaxGeneratedTags etiket, eşitlikte alfabetik",
+			input: TagInput{Caption: "doğa deniz seyahat yemek kahve spor fitness müzik"},
+			want:  []string{"deniz", "doğa", "fitness", "kahve", "müzik", "seyahat"},
+		},
+		{
+			name:  "kısa, sayısal ve yaygın kelimeler etiket olmaz",
+			input: TagInput{Caption: "2024 bir ve için", Content: "the and"},
+			want:  []string{},
+		},
+	}
+
+	tagger := NewKeywordTagger()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tagger.SuggestTags(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("SuggestTags hata döndürdü: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SuggestTags = %q, beklenen %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeywordTaggerIsDeterministic(t *testing.T) {
+	input := TagInput{Caption: "Sahilde kahve ve kitap", Content: "beach coffee book reading sea"}
+	first, _ := NewKeywordTagger().SuggestTags(context.Background(), input)
+	for i := 0; i < 20; i++ {
+		got, _ := NewKeywordTagger().SuggestTags(context.Background(), input)
+		if !slices.Equal(got, first) {
+			t.Fatalf("aynı girdi farklı sonuç verdi: %q ve %q", first, got)
+		}
+	}
+}
+
+func TestParseTagList(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"doğa, Deniz, #seyahat", []string{"doğa", "deniz", "seyahat"}},
+		{"- kahve\n- \"kitap\"\n* kahve", []string{"kahve", "kitap"}},
+		{"a, b, c, d, e, f, g, h", []string{"a", "b", "c", "d", "e", "f"}},
+		{" , ,", []string{}},
+	}
+	for _, tt := range tests {
+		if got := parseTagList(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("parseTagList(%q) = %q, beklenen %q", tt.text, got, tt.want)
+		}
+	}
+}
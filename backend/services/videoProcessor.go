@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Video doğrulama hataları
+var (
+	ErrCorruptVideo = errors.New("video dosyası bozuk veya okunamıyor")
+	ErrVideoTooLong = errors.New("video süresi izin verilen sınırı aşıyor")
+)
+
+// Varsayılan en uzun reel süresi
+const defaultMaxVideoDuration = 90 * time.Second
+
+// VideoProbe ffprobe ile okunan video bilgileri
+type VideoProbe struct {
+	Duration   float64 // Saniye
+	VideoCodec string
+	AudioCodec string // Ses yoksa boş
+	Width      int
+	Height     int
+}
+
+// HLSRendition bir HLS kalite seviyesi
+type HLSRendition struct {
+	Name         string
+	Height       int
+	VideoBitrate int // bit/s
+	AudioBitrate int // bit/s
+}
+
+// HLSRenditions küçükten büyüğe üretilecek kalite seviyeleri. Height videonun kısa kenarıdır
+// (dikey reellerde genişlik); kaynaktan yüksek seviyeler atlanır (büyütme yapılmaz).
+var HLSRenditions = []HLSRendition{
+	{Name: "360p", Height: 360, VideoBitrate: 800_000, AudioBitrate: 96_000},
+	{Name: "720p", Height: 720, VideoBitrate: 2_500_000, AudioBitrate: 128_000},
+	{Name: "1080p", Height: 1080, VideoBitrate: 5_000_000, AudioBitrate: 128_000},
+}
+
+// HLSMasterPlaylist ana oynatma listesinin dosya adı
+const HLSMasterPlaylist = "master.m3u8"
+
+// HLS segment süresi (saniye)
+const hlsSegmentSeconds = 4
+
+// VideoProcessor yerelde kurulu ffmpeg/ffprobe ile video doğrulama, dönüştürme ve kapak çıkarma yapar
+type VideoProcessor struct {
+	FFmpegPath  string
+	FFprobePath string
+	MaxDuration time.Duration // Varsayılan en uzun süre; kullanıcı kotalarıyla değiştirilebilir
+}
+
+// NewVideoProcessorFromEnv FFMPEG_PATH, FFPROBE_PATH ve REEL_MAX_DURATION_SECONDS ortam
+// değişkenlerine göre işlemciyi oluşturur. Yollar verilmezse PATH içinde aranır.
+func NewVideoProcessorFromEnv() *VideoProcessor {
+	processor := &VideoProcessor{
+		FFmpegPath:  lookupBinary("FFMPEG_PATH", "ffmpeg"),
+		FFprobePath: lookupBinary("FFPROBE_PATH", "ffprobe"),
+		MaxDuration: defaultMaxVideoDuration,
+	}
+	if seconds, err := strconv.Atoi(os.Getenv("REEL_MAX_DURATION_SECONDS")); err == nil && seconds > 0 {
+		processor.MaxDuration = time.Duration(seconds) * time.Second
+	}
+	if !processor.Available() {
+		log.Println("Uyarı: ffmpeg/ffprobe bulunamadı, video işleme devre dışı")
+	}
+	return processor
+}
+
+func lookupBinary(envKey, name string) string {
+	if path := os.Getenv(envKey); path != "" {
+		return path
+	}
+	path, _ := exec.LookPath(name)
+	return path
+}
+
+// Available ffmpeg ve ffprobe'un kullanılabilir olup olmadığını döndürür
+func (p *VideoProcessor) Available() bool {
+	return p != nil && p.FFmpegPath != "" && p.FFprobePath != ""
+}
+
+// Probe videonun gerçek süresini ve kodeklerini okur
+func (p *VideoProcessor) Probe(ctx context.Context, input string) (VideoProbe, error) {
+	out, err := exec.CommandContext(ctx, p.FFprobePath,
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format", "-show_streams",
+		input,
+	).Output()
+	if err != nil {
+		return VideoProbe{}, ErrCorruptVideo
+	}
+
+	var result struct {
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+		Streams []struct {
+			CodecType string `json:"codec_type"`
+			CodecName string `json:"codec_name"`
+			Width     int    `json:"width"`
+			Height    int    `json:"height"`
+			Tags      struct {
+				Rotate string `json:"rotate"`
+			} `json:"tags"`
+			SideDataList []struct {
+				Rotation int `json:"rotation"`
+			} `json:"side_data_list"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return VideoProbe{}, ErrCorruptVideo
+	}
+
+	var probe VideoProbe
+	probe.Duration, _ = strconv.ParseFloat(result.Format.Duration, 64)
+	for _, stream := range result.Streams {
+		switch stream.CodecType {
+		case "video":
+			if probe.VideoCodec == "" {
+				probe.VideoCodec = stream.CodecName
+				probe.Width = stream.Width
+				probe.Height = stream.Height
+
+				// Telefonla çekilen videolarda döndürme bilgisi varsa ffmpeg otomatik döndürür
+				rotation, _ := strconv.Atoi(stream.Tags.Rotate)
+				for _, sideData := range stream.SideDataList {
+					if sideData.Rotation != 0 {
+						rotation = sideData.Rotation
+					}
+				}
+				if rotation%180 != 0 {
+					probe.Width, probe.Height = probe.Height, probe.Width
+				}
+			}
+		case "audio":
+			if probe.AudioCodec == "" {
+				probe.AudioCodec = stream.CodecName
+			}
+		}
+	}
+	if probe.VideoCodec == "" || probe.Width == 0 || probe.Height == 0 || probe.Duration <= 0 {
+		return VideoProbe{}, ErrCorruptVideo
+	}
+	return probe, nil
+}
+
+// Validate videonun süre sınırını aşmadığını kontrol eder. maxDuration 0 ise sınır uygulanmaz;
+// kullanıcıya özel sınır yoksa MaxDuration verilmelidir.
+func (p *VideoProcessor) Validate(probe VideoProbe, maxDuration time.Duration) error {
+	if maxDuration > 0 && probe.Duration > maxDuration.Seconds() {
+		return ErrVideoTooLong
+	}
+	return nil
+}
+
+// PackageHLS videoyu H.264/AAC kalite seviyelerine dönüştürür, outDir içine HLS segmentlerini,
+// seviye oynatma listelerini ve master.m3u8 dosyasını yazar
+func (p *VideoProcessor) PackageHLS(ctx context.Context, input, outDir string, probe VideoProbe) error {
+	shortSide := probe.Height
+	if probe.Width < shortSide {
+		shortSide = probe.Width
+	}
+
+	var renditions []HLSRendition
+	for _, rendition := range HLSRenditions {
+		if rendition.Height <= shortSide || len(renditions) == 0 {
+			renditions = append(renditions, rendition)
+		}
+	}
+
+	var master strings.Builder
+	master.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, rendition := range renditions {
+		side := rendition.Height
+		if side > shortSide {
+			side = shortSide
+		}
+		width, height := probe.Width*side/shortSide, probe.Height*side/shortSide
+		width -= width % 2
+		height -= height % 2
+
+		args := []string{
+			"-y", "-v", "error",
+			"-i", input,
+			"-vf", fmt.Sprintf("scale=%d:%d", width, height),
+			"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main",
+			"-b:v", strconv.Itoa(rendition.VideoBitrate),
+			"-maxrate", strconv.Itoa(rendition.VideoBitrate * 107 / 100),
+			"-bufsize", strconv.Itoa(rendition.VideoBitrate * 3 / 2),
+			"-g", "48", "-keyint_min", "48", "-sc_threshold", "0",
+		}
+		bandwidth := rendition.VideoBitrate
+		codecs := "avc1.4d401f"
+		if probe.AudioCodec != "" {
+			args = append(args, "-c:a", "aac", "-ac", "2", "-b:a", strconv.Itoa(rendition.AudioBitrate))
+			bandwidth += rendition.AudioBitrate
+			codecs += ",mp4a.40.2"
+		} else {
+			args = append(args, "-an")
+		}
+		args = append(args,
+			"-f", "hls",
+			"-hls_time", strconv.Itoa(hlsSegmentSeconds),
+			"-hls_playlist_type", "vod",
+			"-hls_segment_filename", filepath.Join(outDir, rendition.Name+"_%03d.ts"),
+			filepath.Join(outDir, rendition.Name+".m3u8"),
+		)
+
+		if out, err := exec.CommandContext(ctx, p.FFmpegPath, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("ffmpeg %s dönüştürme hatası: %v: %s", rendition.Name, err, out)
+		}
+
+		fmt.Fprintf(&master, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,CODECS=\"%s\"\n%s.m3u8\n",
+			bandwidth, width, height, codecs, rendition.Name)
+	}
+
+	return os.WriteFile(filepath.Join(outDir, HLSMasterPlaylist), []byte(master.String()), 0644)
+}
+
+// ExtractPoster videonun belirtilen saniyesinden JPEG kapak karesi çıkarır
+func (p *VideoProcessor) ExtractPoster(ctx context.Context, input, output string, at float64) error {
+	out, err := exec.CommandContext(ctx, p.FFmpegPath,
+		"-y", "-v", "error",
+		"-ss", strconv.FormatFloat(at, 'f', 2, 64),
+		"-i", input,
+		"-frames:v", "1",
+		"-q:v", "3",
+		output,
+	).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("kapak karesi çıkarılamadı: %v: %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	// HLS dosyaları için sistemde tanımlı olmayabilecek MIME tipleri
+	mime.AddExtensionType(".m3u8", "application/vnd.apple.mpegurl")
+	mime.AddExtensionType(".ts", "video/mp2t")
+}
+
+// LocalStore nesneleri yerel dosya sisteminde bir kök dizin altında saklar
+type LocalStore struct {
+	Root       string // Mutlak kök dizin
+	SignedBase string // İmzalı URL'lerin servis edildiği yol (ör. /api/files)
+	secret     []byte
+}
+
+// NewLocalStore kök dizini oluşturur ve yeni bir yerel depo döndürür
+func NewLocalStore(root, signedBase string, secret []byte) (*LocalStore, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("storage: imzalı URL anahtarı boş olamaz")
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(absRoot, 0755); err != nil {
+		return nil, fmt.Errorf("storage: kök dizin oluşturulamadı: %w", err)
+	}
+	return &LocalStore{Root: absRoot, SignedBase: signedBase, secret: secret}, nil
+}
+
+// Path anahtarın dosya sistemindeki tam yolunu döndürür
+func (s *LocalStore) Path(key string) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(cleaned)), nil
+}
+
+// Put dosyayı önce geçici bir dosyaya yazar, sonra yerine taşır (yarım dosya görünmez)
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (ObjectInfo, error) {
+	fullPath, err := s.Path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return ObjectInfo{}, err
+	}
+	if err := tmp.Close(); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := os.Rename(tmp.Name(), fullPath); err != nil {
+		return ObjectInfo{}, err
+	}
+
+	return s.Stat(ctx, key)
+}
+
+// Get dosyayı okumak için açar. Dönen okuyucu *os.File olduğundan io.Seeker'ı da destekler.
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	fullPath, _ := s.Path(key)
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ObjectInfo{}, ErrNotFound
+		}
+		return nil, ObjectInfo{}, err
+	}
+	return file, info, nil
+}
+
+// Stat dosya bilgilerini döndürür
+func (s *LocalStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	fullPath, err := s.Path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	fileInfo, err := os.Stat(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ObjectInfo{}, ErrNotFound
+		}
+		return ObjectInfo{}, err
+	}
+	if fileInfo.IsDir() {
+		return ObjectInfo{}, ErrNotFound
+	}
+
+	cleaned, _ := CleanKey(key)
+	return ObjectInfo{
+		Key:         cleaned,
+		Size:        fileInfo.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(fullPath)),
+		ModTime:     fileInfo.ModTime(),
+		ETag:        fmt.Sprintf("%x-%x", fileInfo.ModTime().UnixNano(), fileInfo.Size()),
+	}, nil
+}
+
+// Delete dosyayı siler
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	fullPath, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL SignedBase altında HMAC ile imzalanmış bir adres üretir
+func (s *LocalStore) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if len(s.secret) == 0 {
+		return "", errors.New("storage: imzalama anahtarı tanımlı değil")
+	}
+
+	expiresAt := time.Now().Add(expires).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", signKey(s.secret, cleaned, expiresAt))
+	return s.SignedBase + "/" + cleaned + "?" + query.Encode(), nil
+}
+
+// VerifySignature SignedURL ile üretilmiş imzanın geçerli ve süresinin dolmamış olduğunu kontrol eder
+func (s *LocalStore) VerifySignature(key, expires, signature string) bool {
+	cleaned, err := CleanKey(key)
+	if err != nil || len(s.secret) == 0 {
+		return false
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+	return hmac.Equal([]byte(signKey(s.secret, cleaned, expiresAt)), []byte(signature))
+}
+
+// List prefix altındaki dosyaları döndürür; geçici yükleme dosyaları atlanır
+func (s *LocalStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	dir := s.Root
+	if prefix != "" {
+		cleaned, err := CleanKey(prefix)
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(s.Root, filepath.FromSlash(cleaned))
+	}
+
+	var objects []ObjectInfo
+	err := filepath.WalkDir(dir, func(fullPath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.Root, fullPath)
+		if err != nil {
+			return err
+		}
+		info, err := s.Stat(ctx, filepath.ToSlash(rel))
+		if err != nil {
+			return nil
+		}
+		objects = append(objects, info)
+		return nil
+	})
+	return objects, err
+}
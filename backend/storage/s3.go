@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config S3 uyumlu depo bağlantı ayarları.
+// Yerelde MinIO gibi bir S3 uyumlu sunucuyla (ör. Endpoint "localhost:9000", UseSSL false) test edilebilir.
+type S3Config struct {
+	Endpoint  string // Şema olmadan sunucu adresi, ör. "s3.amazonaws.com" veya "localhost:9000"
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
+// S3Store nesneleri S3 uyumlu bir kovada saklar
+type S3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Store istemciyi oluşturur ve kova yoksa oluşturur
+func NewS3Store(ctx context.Context, cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("storage: S3_ENDPOINT ve S3_BUCKET tanımlanmalı")
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: S3 istemcisi oluşturulamadı: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("storage: kova kontrol edilemedi: %w", err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+			return nil, fmt.Errorf("storage: kova oluşturulamadı: %w", err)
+		}
+	}
+
+	return &S3Store{client: client, bucket: cfg.Bucket}, nil
+}
+
+// Put nesneyi kovaya yükler
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (ObjectInfo, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	info, err := s.client.PutObject(ctx, s.bucket, cleaned, r, size, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{
+		Key:         cleaned,
+		Size:        info.Size,
+		ContentType: contentType,
+		ModTime:     info.LastModified,
+		ETag:        info.ETag,
+	}, nil
+}
+
+// Get nesneyi okumak için açar. Dönen *minio.Object io.Seeker'ı da destekler.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	object, err := s.client.GetObject(ctx, s.bucket, cleaned, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, mapS3Error(err)
+	}
+	stat, err := object.Stat()
+	if err != nil {
+		object.Close()
+		return nil, ObjectInfo{}, mapS3Error(err)
+	}
+	return object, objectInfoFromS3(stat), nil
+}
+
+// Stat nesnenin meta verilerini döndürür
+func (s *S3Store) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	stat, err := s.client.StatObject(ctx, s.bucket, cleaned, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, mapS3Error(err)
+	}
+	return objectInfoFromS3(stat), nil
+}
+
+// Delete nesneyi kovadan siler
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, cleaned, minio.RemoveObjectOptions{})
+}
+
+// SignedURL nesne için önceden imzalanmış (presigned) GET adresi üretir
+func (s *S3Store) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	signed, err := s.client.PresignedGetObject(ctx, s.bucket, cleaned, expires, nil)
+	if err != nil {
+		return "", err
+	}
+	return signed.String(), nil
+}
+
+func objectInfoFromS3(stat minio.ObjectInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:         stat.Key,
+		Size:        stat.Size,
+		ContentType: stat.ContentType,
+		ModTime:     stat.LastModified,
+		ETag:        stat.ETag,
+	}
+}
+
+// mapS3Error "bulunamadı" yanıtlarını ErrNotFound'a çevirir
+func mapS3Error(err error) error {
+	response := minio.ToErrorResponse(err)
+	if response.StatusCode == http.StatusNotFound || response.Code == "NoSuchKey" {
+		return ErrNotFound
+	}
+	return err
+}
+
+// List prefix ile başlayan nesneleri döndürür
+func (s *S3Store) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		objects = append(objects, objectInfoFromS3(object))
+	}
+	return objects, nil
+}
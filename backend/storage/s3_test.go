@@ -0,0 +1,304 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 testler için bellekte çalışan, S3Store'un kullandığı istekleri karşılayan asgari S3 sunucusu
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]fakeS3Object
+}
+
+type fakeS3Object struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+func (o fakeS3Object) etag() string {
+	sum := md5.Sum(o.data)
+	return hex.EncodeToString(sum[:])
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	t.Helper()
+	fake := &fakeS3{buckets: map[string]map[string]fakeS3Object{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucketName, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	bucket, exists := f.buckets[bucketName]
+
+	if key == "" {
+		switch {
+		case r.Method == http.MethodHead && exists:
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPut:
+			f.buckets[bucketName] = map[string]fakeS3Object{}
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodGet && exists && r.URL.Query().Get("list-type") == "2":
+			f.list(w, bucketName, bucket, r.URL.Query().Get("prefix"))
+		default:
+			writeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		}
+		return
+	}
+	if !exists {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readS3Payload(r)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		object := fakeS3Object{data: data, contentType: r.Header.Get("Content-Type"), modTime: time.Now().UTC()}
+		bucket[key] = object
+		w.Header().Set("ETag", `"`+object.etag()+`"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		object, ok := bucket[key]
+		if !ok {
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.data)))
+		w.Header().Set("ETag", `"`+object.etag()+`"`)
+		w.Header().Set("Last-Modified", object.modTime.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(object.data)
+		}
+	case http.MethodDelete:
+		delete(bucket, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, bucketName string, bucket map[string]fakeS3Object, prefix string) {
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int
+		StorageClass string
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		MaxKeys     int
+		IsTruncated bool
+		Contents    []content
+	}{Name: bucketName, Prefix: prefix, MaxKeys: 1000}
+
+	keys := make([]string, 0, len(bucket))
+	for key := range bucket {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		object := bucket[key]
+		result.Contents = append(result.Contents, content{
+			Key:          key,
+			LastModified: object.modTime.Format("2006-01-02T15:04:05.000Z"),
+			ETag:         `"` + object.etag() + `"`,
+			Size:         len(object.data),
+			StorageClass: "STANDARD",
+		})
+	}
+	result.KeyCount = len(result.Contents)
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+}
+
+// readS3Payload istek gövdesini okur; şifresiz bağlantılarda istemcinin kullandığı
+// aws-chunked (imzalı parçalı) kodlamayı çözer
+func readS3Payload(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	var payload bytes.Buffer
+	reader := bufio.NewReader(r.Body)
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return payload.Bytes(), nil
+		}
+		if _, err := io.CopyN(&payload, reader, size); err != nil {
+			return nil, err
+		}
+		if _, err := reader.Discard(2); err != nil { // parça sonundaki \r\n
+			return nil, err
+		}
+	}
+}
+
+func newTestS3Store(t *testing.T) (*S3Store, *fakeS3) {
+	t.Helper()
+	fake, server := newFakeS3(t)
+	endpoint, _ := url.Parse(server.URL)
+
+	store, err := NewS3Store(context.Background(), S3Config{
+		Endpoint:  endpoint.Host,
+		AccessKey: "test-access",
+		SecretKey: "test-secret",
+		Bucket:    "media",
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Store: %v", err)
+	}
+	return store, fake
+}
+
+func TestS3StoreCreatesMissingBucket(t *testing.T) {
+	_, fake := newTestS3Store(t)
+	if _, ok := fake.buckets["media"]; !ok {
+		t.Fatal("NewS3Store kovayı oluşturmadı")
+	}
+}
+
+func TestS3StoreRoundTrip(t *testing.T) {
+	store, _ := newTestS3Store(t)
+	ctx := context.Background()
+	body := []byte("hello s3")
+
+	info, err := store.Put(ctx, "images/a.jpg", bytes.NewReader(body), int64(len(body)), "image/jpeg")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if info.Key != "images/a.jpg" || info.Size != int64(len(body)) || info.ETag == "" {
+		t.Fatalf("Put bilgisi = %+v", info)
+	}
+
+	reader, info, err := store.Get(ctx, "images/a.jpg")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil || !bytes.Equal(data, body) {
+		t.Fatalf("Get içeriği = %q, %v", data, err)
+	}
+	if info.ContentType != "image/jpeg" || info.Size != int64(len(body)) {
+		t.Fatalf("Get bilgisi = %+v", info)
+	}
+
+	stat, err := store.Stat(ctx, "images/a.jpg")
+	if err != nil || stat.Size != int64(len(body)) || stat.ContentType != "image/jpeg" {
+		t.Fatalf("Stat = %+v, %v", stat, err)
+	}
+
+	if err := store.Delete(ctx, "images/a.jpg"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Stat(ctx, "images/a.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("silinen nesne için Stat hatası = %v, ErrNotFound bekleniyordu", err)
+	}
+	if _, _, err := store.Get(ctx, "images/a.jpg"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("silinen nesne için Get hatası = %v, ErrNotFound bekleniyordu", err)
+	}
+}
+
+func TestS3StoreListFiltersByPrefix(t *testing.T) {
+	store, _ := newTestS3Store(t)
+	ctx := context.Background()
+	for _, key := range []string{"images/a.jpg", "images/b.jpg", "videos/c.mp4"} {
+		if _, err := store.Put(ctx, key, strings.NewReader(key), int64(len(key)), ""); err != nil {
+			t.Fatalf("Put %s: %v", key, err)
+		}
+	}
+
+	objects, err := store.List(ctx, "images/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(objects) != 2 || objects[0].Key != "images/a.jpg" || objects[1].Key != "images/b.jpg" {
+		t.Fatalf("List = %+v", objects)
+	}
+}
+
+func TestS3StoreRejectsInvalidKeys(t *testing.T) {
+	store, _ := newTestS3Store(t)
+	for _, key := range []string{"", "/etc/passwd", "../secret", "images/../../x"} {
+		if _, err := store.Stat(context.Background(), key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Stat(%q) hatası = %v, ErrInvalidKey bekleniyordu", key, err)
+		}
+	}
+}
+
+func TestS3StoreSignedURL(t *testing.T) {
+	store, _ := newTestS3Store(t)
+
+	signed, err := store.SignedURL(context.Background(), "images/a.jpg", 5*time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	parsed, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("SignedURL geçersiz adres döndürdü: %v", err)
+	}
+	if parsed.Path != "/media/images/a.jpg" {
+		t.Errorf("imzalı adres yolu = %q", parsed.Path)
+	}
+	query := parsed.Query()
+	if query.Get("X-Amz-Signature") == "" || query.Get("X-Amz-Expires") != "300" {
+		t.Errorf("imzalı adres sorgusu eksik: %s", parsed.RawQuery)
+	}
+}
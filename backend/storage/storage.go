@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound istenen nesne depoda yoksa döndürülür
+var ErrNotFound = errors.New("storage: nesne bulunamadı")
+
+// ErrInvalidKey anahtar geçersizse (boş, mutlak yol, ".." içeren) döndürülür
+var ErrInvalidKey = errors.New("storage: geçersiz anahtar")
+
+// ObjectInfo depodaki bir nesnenin meta verileri
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+	ETag        string
+}
+
+// BlobStore dosya depolama arka uçları için ortak arayüz.
+// Anahtarlar "images/abc.jpg" gibi "/" ile ayrılmış göreli yollardır.
+type BlobStore interface {
+	// Put nesneyi yazar; size bilinmiyorsa -1 verilebilir
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (ObjectInfo, error)
+	// Get nesneyi okumak için açar; okuyucu çağıran tarafından kapatılmalıdır
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	// Stat nesnenin meta verilerini döndürür
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Delete nesneyi siler; nesne yoksa hata döndürmez
+	Delete(ctx context.Context, key string) error
+	// SignedURL nesneye belirli bir süre geçerli imzalı erişim adresi üretir
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+	// List prefix ile başlayan tüm nesneleri döndürür (temizlik görevleri için)
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// CleanKey anahtarı normalleştirir ve depo dışına çıkmaya çalışan anahtarları reddeder
+func CleanKey(key string) (string, error) {
+	key = strings.TrimSpace(strings.ReplaceAll(key, "\\", "/"))
+	if key == "" || strings.HasPrefix(key, "/") {
+		return "", ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == "" {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+// NewFromEnv STORAGE_DRIVER ortam değişkenine göre depoyu oluşturur ("local" varsayılan, "s3")
+func NewFromEnv() (BlobStore, error) {
+	switch strings.ToLower(os.Getenv("STORAGE_DRIVER")) {
+	case "", "local":
+		root := os.Getenv("STORAGE_LOCAL_ROOT")
+		if root == "" {
+			root = "uploads"
+		}
+		secret, err := signingKeyFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return NewLocalStore(root, "/api/files", secret)
+	case "s3":
+		useSSL, _ := strconv.ParseBool(getenvDefault("S3_USE_SSL", "true"))
+		return NewS3Store(context.Background(), S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			UseSSL:    useSSL,
+		})
+	default:
+		return nil, fmt.Errorf("storage: bilinmeyen STORAGE_DRIVER %q", os.Getenv("STORAGE_DRIVER"))
+	}
+}
+
+// signingKeyFromEnv imzalı URL anahtarını okur. Anahtar JWT_SECRET'tan bağımsız olmalıdır; aksi halde
+// birinin sızması diğerini de geçersiz kılar. Tanımlı değilse depo başlatılmaz.
+func signingKeyFromEnv() ([]byte, error) {
+	key := os.Getenv("STORAGE_SIGNING_KEY")
+	if key == "" {
+		return nil, errors.New("storage: STORAGE_SIGNING_KEY tanımlanmalı (imzalı dosya adresleri için)")
+	}
+	return []byte(key), nil
+}
+
+func getenvDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// signKey anahtar ve son geçerlilik zamanı için HMAC-SHA256 imzası üretir
+func signKey(secret []byte, key string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%s\n%d", key, expiresAt)
+	return hex.EncodeToString(mac.Sum(nil))
+}
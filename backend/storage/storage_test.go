@@ -0,0 +1,23 @@
+package storage
+
+import "testing"
+
+func TestNewFromEnvRequiresSigningKeyForLocalStore(t *testing.T) {
+	t.Setenv("STORAGE_DRIVER", "local")
+	t.Setenv("STORAGE_LOCAL_ROOT", t.TempDir())
+	t.Setenv("JWT_SECRET", "jwt-secret")
+	t.Setenv("STORAGE_SIGNING_KEY", "")
+
+	if _, err := NewFromEnv(); err == nil {
+		t.Fatal("STORAGE_SIGNING_KEY olmadan yerel depo oluşturuldu")
+	}
+
+	t.Setenv("STORAGE_SIGNING_KEY", "signing-key")
+	store, err := NewFromEnv()
+	if err != nil {
+		t.Fatalf("NewFromEnv: %v", err)
+	}
+	if string(store.(*LocalStore).secret) != "signing-key" {
+		t.Fatal("yerel depo STORAGE_SIGNING_KEY yerine başka bir anahtar kullanıyor")
+	}
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"social-media-app/backend/models"
+)
+
+// Varlık türleri
+const (
+	EntityTypeHashtag = "hashtag"
+	EntityTypeMention = "mention"
+	EntityTypeURL     = "url"
+)
+
+// Kullanıcı adları kayıt sırasında en fazla 30 karakter olabiliyor
+const maxMentionLength = 30
+
+var (
+	urlPattern     = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s<>"]+`)
+	hashtagPattern = regexp.MustCompile(`#([\p{L}\p{N}_]+)`)
+	mentionPattern = regexp.MustCompile(`@([\p{L}\p{N}_.]+)`)
+)
+
+// ParseTextEntities metindeki #hashtag, @mention ve URL'leri ofsetleriyle birlikte çıkarır.
+// URL içinde kalan # ve @ karakterleri ayrı varlık olarak sayılmaz.
+func ParseTextEntities(text string) []models.TextEntity {
+	if text == "" {
+		return []models.TextEntity{}
+	}
+
+	type span struct{ start, end int }
+	var taken []span
+	overlaps := func(start, end int) bool {
+		for _, s := range taken {
+			if start < s.end && end > s.start {
+				return true
+			}
+		}
+		return false
+	}
+
+	var entities []models.TextEntity
+	add := func(entityType string, start, end int, value string) {
+		taken = append(taken, span{start, end})
+		entities = append(entities, models.TextEntity{
+			Type:  entityType,
+			Text:  text[start:end],
+			Value: value,
+			Start: utf16Offset(text, start),
+			End:   utf16Offset(text, end),
+		})
+	}
+
+	// Önce URL'ler: içlerindeki # ve @ karakterlerini sahiplenirler
+	for _, loc := range urlPattern.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if !isEntityBoundary(text, start) {
+			continue
+		}
+		// Cümle sonundaki noktalama işaretlerini URL'ye dahil etme
+		end = start + len(strings.TrimRight(text[start:end], ".,!?;:'\")]}"))
+		value := text[start:end]
+		if strings.HasPrefix(strings.ToLower(value), "www.") {
+			value = "https://" + value
+		}
+		add(EntityTypeURL, start, end, value)
+	}
+
+	for _, loc := range hashtagPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		name := text[loc[2]:loc[3]]
+		if !isEntityBoundary(text, start) || overlaps(start, end) || !containsLetter(name) {
+			continue
+		}
+		add(EntityTypeHashtag, start, end, NormalizeTagName(name))
+	}
+
+	for _, loc := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		start := loc[0]
+		// Kullanıcı adı sonundaki noktalar cümle noktalamasıdır
+		username := strings.TrimRight(text[loc[2]:loc[3]], ".")
+		end := loc[2] + len(username)
+		if username == "" || utf8.RuneCountInString(username) > maxMentionLength {
+			continue
+		}
+		if !isEntityBoundary(text, start) || overlaps(start, end) {
+			continue
+		}
+		add(EntityTypeMention, start, end, username)
+	}
+
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].Start < entities[j].Start
+	})
+
+	if entities == nil {
+		return []models.TextEntity{}
+	}
+	return entities
+}
+
+// ExtractHashtags metindeki benzersiz hashtag adlarını (normalleştirilmiş) döndürür
+func ExtractHashtags(texts ...string) []string {
+	return uniqueEntityValues(EntityTypeHashtag, texts...)
+}
+
+// ExtractMentions metindeki benzersiz kullanıcı adlarını döndürür
+func ExtractMentions(texts ...string) []string {
+	return uniqueEntityValues(EntityTypeMention, texts...)
+}
+
+// NormalizeTagName etiket adını karşılaştırma ve saklama için standart hale getirir
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "#")))
+}
+
+func uniqueEntityValues(entityType string, texts ...string) []string {
+	seen := make(map[string]bool)
+	values := []string{}
+	for _, text := range texts {
+		for _, entity := range ParseTextEntities(text) {
+			if entity.Type != entityType {
+				continue
+			}
+			key := strings.ToLower(entity.Value)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			values = append(values, entity.Value)
+		}
+	}
+	return values
+}
+
+// isEntityBoundary varlığın bir kelimenin ortasında (örn. e-posta adresi) başlamadığını kontrol eder
+func isEntityBoundary(text string, start int) bool {
+	if start == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:start])
+	return !(unicode.IsLetter(prev) || unicode.IsDigit(prev) || prev == '_' || prev == '.' || prev == '&' || prev == '/')
+}
+
+func containsLetter(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// utf16Offset bayt ofsetini UTF-16 kod birimi ofsetine çevirir
+func utf16Offset(text string, byteOffset int) int {
+	offset := 0
+	for _, r := range text[:byteOffset] {
+		offset += utf16.RuneLen(r)
+	}
+	return offset
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+// entitySummary testlerde karşılaştırma kolaylığı için varlığın tür, değer ve ofsetlerini tutar
+type entitySummary struct {
+	Type  string
+	Value string
+	Start int
+	End   int
+}
+
+func summarizeEntities(text string) []entitySummary {
+	summaries := []entitySummary{}
+	for _, entity := range ParseTextEntities(text) {
+		summaries = append(summaries, entitySummary{entity.Type, entity.Value, entity.Start, entity.End})
+	}
+	return summaries
+}
+
+func TestParseTextEntities(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []entitySummary
+	}{
+		{
+			name: "boş metin",
+			text: "",
+			want: []entitySummary{},
+		},
+		{
+			name: "sondaki noktalama varlığa dahil edilmez",
+			text: "#go! @alice. (@bob)",
+			want: []entitySummary{
+				{EntityTypeHashtag, "go", 0, 3},
+				{EntityTypeMention, "alice", 5, 11},
+				{EntityTypeMention, "bob", 14, 18},
+			},
+		},
+		{
+			name: "unicode etiket ve kullanıcı adı, ofsetler UTF-16 birimiyle",
+			text: "👋 #Çiçek @Ayşe",
+			want: []entitySummary{
+				{EntityTypeHashtag, "çiçek", 3, 9},
+				{EntityTypeMention, "Ayşe", 10, 15},
+			},
+		},
+		{
+			name: "tekrarlanan varlıkların her biri ofsetiyle döner",
+			text: "#Go #go @bob @bob",
+			want: []entitySummary{
+				{EntityTypeHashtag, "go", 0, 3},
+				{EntityTypeHashtag, "go", 4, 7},
+				{EntityTypeMention, "bob", 8, 12},
+				{EntityTypeMention, "bob", 13, 17},
+			},
+		},
+		{
+			name: "e-posta adresleri mention sayılmaz",
+			text: "x@example.com ve a.b@c.io adresine yaz",
+			want: []entitySummary{},
+		},
+		{
+			name: "kelime içindeki # ve @ varlık değildir",
+			text: "foo#bar baz@qux #son",
+			want: []entitySummary{{EntityTypeHashtag, "son", 16, 20}},
+		},
+		{
+			name: "URL içindeki # ve @ URL'ye aittir",
+			text: "https://x.com/a#frag?u=@me #tag",
+			want: []entitySummary{
+				{EntityTypeURL, "https://x.com/a#frag?u=@me", 0, 26},
+				{EntityTypeHashtag, "tag", 27, 31},
+			},
+		},
+		{
+			name: "www adresine şema eklenir, sondaki nokta atılır",
+			text: "bkz. www.example.com.",
+			want: []entitySummary{{EntityTypeURL, "https://www.example.com", 5, 20}},
+		},
+		{
+			name: "sadece rakam veya alt çizgiden oluşan etiketler atlanır",
+			text: "#123 #_ #a1 @",
+			want: []entitySummary{{EntityTypeHashtag, "a1", 8, 11}},
+		},
+		{
+			name: "30 karakterden uzun kullanıcı adları atlanır",
+			text: "@aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa @ok_user.name..",
+			want: []entitySummary{{EntityTypeMention, "ok_user.name", 33, 46}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarizeEntities(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("ParseTextEntities(%q)\n got  %+v\n want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTextEntitiesKeepsOriginalText(t *testing.T) {
+	entities := ParseTextEntities("Merhaba @Ayşe #YeniYıl")
+	if len(entities) != 2 {
+		t.Fatalf("%d varlık bulundu, 2 bekleniyordu", len(entities))
+	}
+	if entities[0].Text != "@Ayşe" || entities[1].Text != "#YeniYıl" {
+		t.Errorf("varlık metinleri = %q, %q", entities[0].Text, entities[1].Text)
+	}
+	if entities[1].Value != "yeniyıl" {
+		t.Errorf("etiket değeri = %q, küçük harfe çevrilmiş olmalı", entities[1].Value)
+	}
+}
+
+func TestExtractHashtagsAndMentionsDeduplicate(t *testing.T) {
+	if got := ExtractHashtags("#Go #go #GO", "#Çiçek #çiçek"); !slices.Equal(got, []string{"go", "çiçek"}) {
+		t.Errorf("ExtractHashtags = %q", got)
+	}
+	// Kullanıcı adları büyük/küçük harf duyarsız tekilleştirilir, ilk yazım korunur
+	if got := ExtractMentions("@Alice @alice", "@bob"); !slices.Equal(got, []string{"Alice", "bob"}) {
+		t.Errorf("ExtractMentions = %q", got)
+	}
+}